@@ -2,12 +2,19 @@ package gormcache
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
+// ErrCacheMiss is returned, possibly wrapped, by Adapter.Get when the key does
+// not exist or has expired
+// Any other error is reported as an adapter failure, see Config.FailOnCacheErrors
+var ErrCacheMiss = errors.New("gorm:cache: cache miss")
+
 // Adapter defines the interface for cache storage implementations
 type Adapter interface {
 	// Get retrieves a value from cache by key
+	// It returns an error wrapping ErrCacheMiss if the key is not cached
 	Get(ctx context.Context, key string) ([]byte, error)
 
 	// Set stores a value in cache with the given key and TTL
@@ -25,3 +32,93 @@ type Adapter interface {
 	// Close closes the adapter connection
 	Close() error
 }
+
+// CountableAdapter is implemented by adapters able to report how many entries they hold
+type CountableAdapter interface {
+	// Count returns the number of cached entries
+	Count(ctx context.Context) (int, error)
+}
+
+// atomicPatternDeleter is implemented by adapters able to delete all keys
+// matching a pattern atomically
+type atomicPatternDeleter interface {
+	DeletePatternAtomic(ctx context.Context, pattern string) error
+}
+
+// PingableAdapter is implemented by adapters able to check that their backend is reachable
+type PingableAdapter interface {
+	// Ping returns an error if the backend cannot be reached
+	Ping(ctx context.Context) error
+}
+
+// ScannableAdapter is implemented by adapters able to list their keys
+type ScannableAdapter interface {
+	// Scan returns the keys matching the pattern
+	Scan(ctx context.Context, pattern string) ([]string, error)
+}
+
+// BatchAdapter is implemented by adapters able to read, write and delete
+// several keys in one round-trip
+//
+// Adapter itself is unchanged, so custom adapters keep working without it
+// Implementations must follow this contract:
+//   - MGet returns the cached values by key; missing or expired keys are absent
+//     from the map rather than reported as ErrCacheMiss, so an error means the
+//     whole batch failed
+//   - MSet stores all items with the same TTL
+//   - MDelete ignores keys that do not exist
+type BatchAdapter interface {
+	// MGet retrieves the values of the given keys
+	MGet(ctx context.Context, keys []string) (map[string][]byte, error)
+
+	// MSet stores all items with the given TTL
+	MSet(ctx context.Context, items map[string][]byte, ttl time.Duration) error
+
+	// MDelete removes the given keys
+	MDelete(ctx context.Context, keys []string) error
+}
+
+// TTLAdapter is implemented by adapters able to report the remaining lifetime
+// of a cached key
+type TTLAdapter interface {
+	// TTL returns the remaining lifetime of key, 0 if it never expires, or an
+	// error wrapping ErrCacheMiss if it is not cached
+	TTL(ctx context.Context, key string) (time.Duration, error)
+}
+
+// TouchAdapter is implemented by adapters able to reset the TTL of a cached
+// key without rewriting its value
+type TouchAdapter interface {
+	// Touch sets the remaining lifetime of key to ttl, or returns an error
+	// wrapping ErrCacheMiss if it is not cached
+	Touch(ctx context.Context, key string, ttl time.Duration) error
+}
+
+// WrapperAdapter is implemented by adapters wrapping another adapter, so the
+// optional interfaces of the wrapped adapter can be found
+type WrapperAdapter interface {
+	// Unwrap returns the wrapped adapter
+	Unwrap() Adapter
+}
+
+// adapterChain returns adapter followed by the adapters it wraps, following
+// WrapperAdapter
+func adapterChain(adapter Adapter) []Adapter {
+	var chain []Adapter
+	for adapter != nil {
+		chain = append(chain, adapter)
+		w, ok := adapter.(WrapperAdapter)
+		if !ok {
+			break
+		}
+		adapter = w.Unwrap()
+	}
+	return chain
+}
+
+// ExistsAdapter is implemented by adapters able to check whether a key is
+// cached without fetching its value
+type ExistsAdapter interface {
+	// Exists reports whether key is cached and not expired
+	Exists(ctx context.Context, key string) (bool, error)
+}
@@ -0,0 +1,31 @@
+package gormcache
+
+// GetAdapter returns the adapter currently used by the plugin
+func (p *CachePlugin) GetAdapter() Adapter {
+	return p.adapter()
+}
+
+// SetAdapter replaces the adapter of the plugin at runtime, e.g. to move the
+// cache to a new Redis server without restarting
+// The previous adapter is neither cleared nor closed, and queries already
+// running may still use it; with Config.ReadOnly, adapter is wrapped as in New
+// The subscription of BroadcastInvalidation keeps using the Redis client of
+// the adapter the plugin was initialized with
+func (p *CachePlugin) SetAdapter(adapter Adapter) {
+	if p.config.ReadOnly {
+		if _, ok := adapter.(*ReadOnlyAdapter); !ok {
+			adapter = NewReadOnlyAdapter(adapter)
+		}
+	}
+
+	p.adapterMu.Lock()
+	p.config.Adapter = adapter
+	p.adapterMu.Unlock()
+}
+
+// adapter returns the current adapter, see SetAdapter
+func (p *CachePlugin) adapter() Adapter {
+	p.adapterMu.RLock()
+	defer p.adapterMu.RUnlock()
+	return p.config.Adapter
+}
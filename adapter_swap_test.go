@@ -0,0 +1,72 @@
+package gormcache
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSetAdapter(t *testing.T) {
+	db := setupTestDB(t)
+
+	oldAdapter := NewMemoryAdapter()
+	defer oldAdapter.Close()
+	cachePlugin := New(Config{Adapter: oldAdapter, TTL: 5 * time.Minute})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+
+	db.Create(&TestUser{Name: "John"})
+	var users []TestUser
+	db.Find(&users)
+	if len(oldAdapter.store) != 1 {
+		t.Fatalf("expected 1 entry in the old adapter, got %d", len(oldAdapter.store))
+	}
+
+	newAdapter := NewMemoryAdapter()
+	defer newAdapter.Close()
+	cachePlugin.SetAdapter(newAdapter)
+	if cachePlugin.GetAdapter() != newAdapter {
+		t.Fatal("expected GetAdapter to return the new adapter")
+	}
+
+	queries := countQueries(t, db)
+	db.Where("name = ?", "John").Find(&users)
+	db.Find(&users)
+
+	// The new adapter starts empty, so both queries reach the database and are cached in it
+	if *queries != 2 {
+		t.Errorf("expected 2 database queries after the swap, got %d", *queries)
+	}
+	if len(newAdapter.store) != 2 {
+		t.Errorf("expected 2 entries in the new adapter, got %d", len(newAdapter.store))
+	}
+	if len(oldAdapter.store) != 1 {
+		t.Errorf("expected the old adapter to keep its entry, got %d", len(oldAdapter.store))
+	}
+}
+
+func TestSetAdapterConcurrent(t *testing.T) {
+	db := setupTestDB(t)
+
+	cachePlugin := New(Config{Adapter: NewMemoryAdapter(), TTL: 5 * time.Minute})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	db.Create(&TestUser{Name: "John"})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			var users []TestUser
+			db.Find(&users)
+		}()
+		go func() {
+			defer wg.Done()
+			cachePlugin.SetAdapter(NewMemoryAdapter())
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,118 @@
+package gormcache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+var errInterrupted = errors.New("connection interrupted")
+
+// interruptHook simulates a connection dropping halfway through a pipeline:
+// only the first half of the commands reach Redis. For a MULTI/EXEC transaction
+// the EXEC is lost with the connection, so Redis discards the queued commands
+type interruptHook struct{}
+
+func (interruptHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (interruptHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return next
+}
+
+func (interruptHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if cmds[0].Name() == "multi" {
+			discard := append([]redis.Cmder{}, cmds[:len(cmds)-1]...)
+			discard = append(discard, redis.NewStatusCmd(ctx, "discard"))
+			_ = next(ctx, discard)
+			return errInterrupted
+		}
+
+		_ = next(ctx, cmds[:len(cmds)/2])
+		return errInterrupted
+	}
+}
+
+func newInterruptedRedisAdapter(t *testing.T, mr *miniredis.Miniredis) *RedisAdapter {
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	client.AddHook(interruptHook{})
+	t.Cleanup(func() { client.Close() })
+	return NewRedisAdapterWithClient(client)
+}
+
+func TestDeletePatternAtomic(t *testing.T) {
+	mr := miniredis.RunT(t)
+	ctx := context.Background()
+
+	for i := 0; i < 10; i++ {
+		mr.Set(fmt.Sprintf("gorm:cache:test_users:%d", i), "value")
+	}
+
+	adapter := newInterruptedRedisAdapter(t, mr)
+
+	// An interrupted pipeline leaves part of the keys behind
+	if err := adapter.DeletePattern(ctx, "gorm:cache:test_users:*"); err == nil {
+		t.Fatal("expected interrupted DeletePattern to fail")
+	}
+	if n := len(mr.Keys()); n == 0 || n == 10 {
+		t.Fatalf("expected a partial deletion, got %d keys left", n)
+	}
+
+	for i := 0; i < 10; i++ {
+		mr.Set(fmt.Sprintf("gorm:cache:test_users:%d", i), "value")
+	}
+
+	// An interrupted transaction deletes none of them
+	if err := adapter.DeletePatternAtomic(ctx, "gorm:cache:test_users:*"); err == nil {
+		t.Fatal("expected interrupted DeletePatternAtomic to fail")
+	}
+	if n := len(mr.Keys()); n != 10 {
+		t.Errorf("expected no key to be deleted, got %d keys left", n)
+	}
+
+	// Without interruption all keys are deleted
+	healthy := NewRedisAdapter(RedisAdapterConfig{Addr: mr.Addr()})
+	defer healthy.Close()
+
+	if err := healthy.DeletePatternAtomic(ctx, "gorm:cache:test_users:*"); err != nil {
+		t.Fatalf("failed to delete pattern: %v", err)
+	}
+	if n := len(mr.Keys()); n != 0 {
+		t.Errorf("expected all keys to be deleted, got %d keys left", n)
+	}
+}
+
+func TestAtomicInvalidation(t *testing.T) {
+	db := setupTestDB(t)
+	mr := miniredis.RunT(t)
+
+	cachePlugin := New(Config{
+		Adapter:            newInterruptedRedisAdapter(t, mr),
+		TTL:                5 * time.Minute,
+		InvalidateOnUpdate: true,
+		AtomicInvalidation: true,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+
+	user := TestUser{Name: "Atomic"}
+	db.Create(&user)
+
+	for i := 0; i < 10; i++ {
+		mr.Set(fmt.Sprintf("gorm:cache:test_users:%d", i), "value")
+	}
+
+	// The interrupted invalidation keeps the batch intact
+	db.Model(&user).Update("Name", "Updated")
+	if n := len(mr.Keys()); n != 10 {
+		t.Errorf("expected invalidation to delete all or none of the keys, got %d keys left", n)
+	}
+}
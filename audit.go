@@ -0,0 +1,29 @@
+package gormcache
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// AuditReport describes the configuration and state of a CachePlugin
+type AuditReport struct {
+	AdapterType             string        // Concrete type of the cache adapter
+	CallbacksRegistered     []string      // GORM callbacks registered by Initialize
+	CacheModelsCount        int           // Number of models selected for caching (0 = all)
+	TTL                     time.Duration // Default time-to-live
+	HasCustomKeyGenerator   bool          // Whether CacheKeyGenerator is set
+	SkipConditionConfigured bool          // Whether SkipCacheCondition is set
+}
+
+// Audit reports the current plugin state, e.g. to verify the configuration after deployment
+func (p *CachePlugin) Audit(ctx context.Context) *AuditReport {
+	return &AuditReport{
+		AdapterType:             fmt.Sprintf("%T", p.adapter()),
+		CallbacksRegistered:     append([]string(nil), p.callbacks...),
+		CacheModelsCount:        len(p.config.CacheModels),
+		TTL:                     p.config.TTL,
+		HasCustomKeyGenerator:   p.config.CacheKeyGenerator != nil,
+		SkipConditionConfigured: p.config.SkipCacheCondition != nil,
+	}
+}
@@ -0,0 +1,74 @@
+package gormcache
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func TestAudit(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		want   AuditReport
+	}{
+		{
+			name: "minimal",
+			config: Config{
+				Adapter: NewMemoryAdapter(),
+				TTL:     time.Minute,
+			},
+			want: AuditReport{
+				AdapterType:         "*gormcache.MemoryAdapter",
+				CallbacksRegistered: []string{"gorm:cache:query", "gorm:cache:after_query"},
+				TTL:                 time.Minute,
+			},
+		},
+		{
+			name: "customized",
+			config: Config{
+				Adapter:            NewPinnedKeysAdapter(NewMemoryAdapter()),
+				TTL:                10 * time.Minute,
+				CacheModels:        []interface{}{TestUser{}},
+				InvalidateOnCreate: true,
+				InvalidateOnDelete: true,
+				CacheKeyGenerator:  func(db *gorm.DB) string { return db.Statement.Table },
+				SkipCacheCondition: func(db *gorm.DB) bool { return false },
+			},
+			want: AuditReport{
+				AdapterType: "*gormcache.PinnedKeysAdapter",
+				CallbacksRegistered: []string{
+					"gorm:cache:query",
+					"gorm:cache:after_query",
+					"gorm:cache:after_create",
+					"gorm:cache:after_delete",
+					"gorm:cache:after_soft_delete",
+				},
+				CacheModelsCount:        1,
+				TTL:                     10 * time.Minute,
+				HasCustomKeyGenerator:   true,
+				SkipConditionConfigured: true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := setupTestDB(t)
+
+			cachePlugin := New(tt.config)
+			if err := db.Use(cachePlugin); err != nil {
+				t.Fatalf("failed to install plugin: %v", err)
+			}
+			defer cachePlugin.Close()
+
+			report := cachePlugin.Audit(context.Background())
+			if !reflect.DeepEqual(*report, tt.want) {
+				t.Errorf("unexpected audit report:\n got %+v\nwant %+v", *report, tt.want)
+			}
+		})
+	}
+}
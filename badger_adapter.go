@@ -0,0 +1,135 @@
+package gormcache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dgraph-io/badger/v4"
+)
+
+// BadgerAdapter is an embedded cache implementation backed by BadgerDB, which
+// persists cached data on disk across restarts
+type BadgerAdapter struct {
+	db *badger.DB
+}
+
+// NewBadgerAdapter opens a Badger database at path, or an in-process one
+// without persistence if path is ":memory:"
+func NewBadgerAdapter(path string) (*BadgerAdapter, error) {
+	opts := badger.DefaultOptions(path)
+	if path == ":memory:" {
+		opts = badger.DefaultOptions("").WithInMemory(true)
+	}
+	opts = opts.WithLogger(nil)
+
+	db, err := badger.Open(opts)
+	if err != nil {
+		return nil, fmt.Errorf("gorm:cache: open badger database %q: %w", path, err)
+	}
+	return &BadgerAdapter{db: db}, nil
+}
+
+// Get retrieves a value from Badger cache
+func (b *BadgerAdapter) Get(ctx context.Context, key string) ([]byte, error) {
+	var value []byte
+	err := b.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte(key))
+		if err != nil {
+			return err
+		}
+		value, err = item.ValueCopy(nil)
+		return err
+	})
+	if errors.Is(err, badger.ErrKeyNotFound) {
+		return nil, fmt.Errorf("%w: %w", ErrCacheMiss, err)
+	}
+	return value, err
+}
+
+// Exists reports whether key is cached, without reading its value
+func (b *BadgerAdapter) Exists(ctx context.Context, key string) (bool, error) {
+	err := b.db.View(func(txn *badger.Txn) error {
+		_, err := txn.Get([]byte(key))
+		return err
+	})
+	if errors.Is(err, badger.ErrKeyNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Set stores a value in Badger cache
+func (b *BadgerAdapter) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	entry := badger.NewEntry([]byte(key), value)
+	if ttl > 0 {
+		entry = entry.WithTTL(ttl)
+	}
+	return b.db.Update(func(txn *badger.Txn) error {
+		return txn.SetEntry(entry)
+	})
+}
+
+// Delete removes a value from Badger cache
+func (b *BadgerAdapter) Delete(ctx context.Context, key string) error {
+	return b.db.Update(func(txn *badger.Txn) error {
+		return txn.Delete([]byte(key))
+	})
+}
+
+// DeletePattern removes all keys matching the pattern
+func (b *BadgerAdapter) DeletePattern(ctx context.Context, pattern string) error {
+	keys, err := b.Scan(ctx, pattern)
+	if err != nil {
+		return err
+	}
+
+	// WriteBatch 会按需拆分事务，不受单个事务大小限制
+	batch := b.db.NewWriteBatch()
+	defer batch.Cancel()
+	for _, key := range keys {
+		if err := batch.Delete([]byte(key)); err != nil {
+			return err
+		}
+	}
+	return batch.Flush()
+}
+
+// Scan returns the keys matching the pattern, iterating over the keys sharing
+// the literal prefix of the pattern
+func (b *BadgerAdapter) Scan(ctx context.Context, pattern string) ([]string, error) {
+	prefix := pattern
+	if i := strings.IndexAny(pattern, "*?["); i >= 0 {
+		prefix = pattern[:i]
+	}
+
+	var keys []string
+	err := b.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		opts.Prefix = []byte(prefix)
+
+		it := txn.NewIterator(opts)
+		defer it.Close()
+		for it.Rewind(); it.Valid(); it.Next() {
+			key := string(it.Item().KeyCopy(nil))
+			if matchPattern(pattern, key) {
+				keys = append(keys, key)
+			}
+		}
+		return nil
+	})
+	return keys, err
+}
+
+// Clear removes all cached data
+func (b *BadgerAdapter) Clear(ctx context.Context) error {
+	return b.db.DropAll()
+}
+
+// Close closes the Badger database
+func (b *BadgerAdapter) Close() error {
+	return b.db.Close()
+}
@@ -0,0 +1,118 @@
+package gormcache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTestBadgerAdapter(t *testing.T) *BadgerAdapter {
+	t.Helper()
+	adapter, err := NewBadgerAdapter(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open badger: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = adapter.Close()
+	})
+	return adapter
+}
+
+func TestBadgerAdapterSetGetDelete(t *testing.T) {
+	adapter := newTestBadgerAdapter(t)
+	ctx := context.Background()
+
+	if _, err := adapter.Get(ctx, "missing"); !errors.Is(err, ErrCacheMiss) {
+		t.Errorf("expected ErrCacheMiss, got %v", err)
+	}
+
+	if err := adapter.Set(ctx, "key1", []byte("value1"), time.Minute); err != nil {
+		t.Fatalf("failed to set: %v", err)
+	}
+	value, err := adapter.Get(ctx, "key1")
+	if err != nil || string(value) != "value1" {
+		t.Fatalf("expected value1, got %q (%v)", value, err)
+	}
+
+	if err := adapter.Delete(ctx, "key1"); err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+	if _, err := adapter.Get(ctx, "key1"); err == nil {
+		t.Error("expected key1 to be deleted")
+	}
+}
+
+func TestBadgerAdapterExpiration(t *testing.T) {
+	adapter := newTestBadgerAdapter(t)
+	ctx := context.Background()
+
+	// Badger 的过期时间精确到秒
+	adapter.Set(ctx, "short", []byte("value"), time.Second)
+	adapter.Set(ctx, "forever", []byte("value"), 0)
+	time.Sleep(2100 * time.Millisecond)
+
+	if _, err := adapter.Get(ctx, "short"); !errors.Is(err, ErrCacheMiss) {
+		t.Errorf("expected the entry to expire, got %v", err)
+	}
+	if _, err := adapter.Get(ctx, "forever"); err != nil {
+		t.Errorf("expected the entry without TTL to be kept: %v", err)
+	}
+}
+
+func TestBadgerAdapterDeletePatternAndClear(t *testing.T) {
+	adapter := newTestBadgerAdapter(t)
+	ctx := context.Background()
+
+	for _, key := range []string{"gorm:cache:users:1", "gorm:cache:users:2", "gorm:cache:orders:1"} {
+		adapter.Set(ctx, key, []byte("value"), time.Minute)
+	}
+
+	if err := adapter.DeletePattern(ctx, "gorm:cache:users:*"); err != nil {
+		t.Fatalf("failed to delete pattern: %v", err)
+	}
+	for _, key := range []string{"gorm:cache:users:1", "gorm:cache:users:2"} {
+		if _, err := adapter.Get(ctx, key); err == nil {
+			t.Errorf("expected %s to be deleted", key)
+		}
+	}
+	if _, err := adapter.Get(ctx, "gorm:cache:orders:1"); err != nil {
+		t.Errorf("expected gorm:cache:orders:1 to be kept: %v", err)
+	}
+
+	if err := adapter.Clear(ctx); err != nil {
+		t.Fatalf("failed to clear: %v", err)
+	}
+	if _, err := adapter.Get(ctx, "gorm:cache:orders:1"); err == nil {
+		t.Error("expected Clear to remove all entries")
+	}
+}
+
+func TestBadgerAdapterWithPlugin(t *testing.T) {
+	db := setupTestDB(t)
+
+	cachePlugin := New(Config{
+		Adapter:            newTestBadgerAdapter(t),
+		TTL:                5 * time.Minute,
+		InvalidateOnCreate: true,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+
+	db.Create(&TestUser{Name: "John"})
+	queries := countQueries(t, db)
+
+	var users []TestUser
+	db.Find(&users)
+	db.Find(&users)
+	if *queries != 1 {
+		t.Errorf("expected the second query to be served from cache, got %d database queries", *queries)
+	}
+
+	db.Create(&TestUser{Name: "Jane"})
+	db.Find(&users)
+	if *queries != 2 || len(users) != 2 {
+		t.Errorf("expected the insert to invalidate the cached query, got %d queries and %d users", *queries, len(users))
+	}
+}
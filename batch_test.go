@@ -0,0 +1,166 @@
+package gormcache
+
+import (
+	"context"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func testBatchAdapter(t *testing.T, adapter BatchAdapter) {
+	ctx := context.Background()
+
+	items := map[string][]byte{
+		"key1": []byte("value1"),
+		"key2": []byte("value2"),
+		"key3": []byte("value3"),
+	}
+	if err := adapter.MSet(ctx, items, time.Minute); err != nil {
+		t.Fatalf("MSet failed: %v", err)
+	}
+
+	values, err := adapter.MGet(ctx, []string{"key1", "key2", "key3", "missing"})
+	if err != nil {
+		t.Fatalf("MGet failed: %v", err)
+	}
+	if !reflect.DeepEqual(values, items) {
+		t.Errorf("expected %v, got %v", items, values)
+	}
+
+	if err := adapter.MDelete(ctx, []string{"key1", "key3", "missing"}); err != nil {
+		t.Fatalf("MDelete failed: %v", err)
+	}
+
+	values, err = adapter.MGet(ctx, []string{"key1", "key2", "key3"})
+	if err != nil {
+		t.Fatalf("MGet failed: %v", err)
+	}
+	expected := map[string][]byte{"key2": []byte("value2")}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("expected %v, got %v", expected, values)
+	}
+}
+
+func TestBatchAdapter(t *testing.T) {
+	t.Run("memory", func(t *testing.T) {
+		adapter := NewMemoryAdapter()
+		defer adapter.Close()
+		testBatchAdapter(t, adapter)
+	})
+
+	t.Run("memory lru", func(t *testing.T) {
+		adapter := NewMemoryAdapterWithOptions(WithMaxItems(10))
+		defer adapter.Close()
+		testBatchAdapter(t, adapter)
+	})
+
+	t.Run("memory sync.Map", func(t *testing.T) {
+		adapter := NewMemoryAdapterWithSyncMap()
+		defer adapter.Close()
+		testBatchAdapter(t, adapter)
+	})
+
+	t.Run("redis", func(t *testing.T) {
+		mr := miniredis.RunT(t)
+		adapter := NewRedisAdapter(RedisAdapterConfig{Addr: mr.Addr()})
+		defer adapter.Close()
+		testBatchAdapter(t, adapter)
+	})
+}
+
+func TestMemoryAdapterMGetSkipsExpired(t *testing.T) {
+	adapter := NewMemoryAdapter()
+	defer adapter.Close()
+
+	ctx := context.Background()
+	adapter.Set(ctx, "short", []byte("value"), 50*time.Millisecond)
+	adapter.Set(ctx, "long", []byte("value"), time.Minute)
+
+	time.Sleep(100 * time.Millisecond)
+
+	values, err := adapter.MGet(ctx, []string{"short", "long"})
+	if err != nil {
+		t.Fatalf("MGet failed: %v", err)
+	}
+	if _, ok := values["short"]; ok {
+		t.Error("expected expired key to be absent")
+	}
+	if _, ok := values["long"]; !ok {
+		t.Error("expected live key to be present")
+	}
+}
+
+func TestMemoryAdapterMSetEvicts(t *testing.T) {
+	adapter := NewMemoryAdapterWithOptions(WithMaxItems(2))
+	defer adapter.Close()
+
+	ctx := context.Background()
+	adapter.MSet(ctx, map[string][]byte{
+		"key1": []byte("value1"),
+		"key2": []byte("value2"),
+		"key3": []byte("value3"),
+	}, time.Minute)
+
+	if count, _ := adapter.Count(ctx); count != 2 {
+		t.Errorf("expected 2 entries after eviction, got %d", count)
+	}
+}
+
+// roundTripHook counts the commands and pipelines sent to Redis
+type roundTripHook struct {
+	commands  atomic.Int64
+	pipelines atomic.Int64
+}
+
+func (h *roundTripHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h *roundTripHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		h.commands.Add(1)
+		return next(ctx, cmd)
+	}
+}
+
+func (h *roundTripHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		h.pipelines.Add(1)
+		return next(ctx, cmds)
+	}
+}
+
+func TestRedisAdapterMGetSinglePipeline(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	adapter := NewRedisAdapterWithClient(client)
+	defer adapter.Close()
+
+	ctx := context.Background()
+	keys := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}
+	for _, key := range keys {
+		mr.Set(key, "value")
+	}
+
+	hook := &roundTripHook{}
+	client.AddHook(hook)
+
+	values, err := adapter.MGet(ctx, append(keys, "missing"))
+	if err != nil {
+		t.Fatalf("MGet failed: %v", err)
+	}
+	if len(values) != len(keys) {
+		t.Errorf("expected %d values, got %d", len(keys), len(values))
+	}
+
+	if pipelines := hook.pipelines.Load(); pipelines != 1 {
+		t.Errorf("expected 1 pipeline round-trip, got %d", pipelines)
+	}
+	if commands := hook.commands.Load(); commands != 0 {
+		t.Errorf("expected no individual commands, got %d", commands)
+	}
+}
@@ -0,0 +1,157 @@
+package gormcache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// invalidationMessage is published on Config.PubSubChannel for every
+// invalidation of a plugin with BroadcastInvalidation
+type invalidationMessage struct {
+	// Origin identifies the publishing plugin, which ignores its own messages
+	Origin  string `json:"origin"`
+	Pattern string `json:"pattern,omitempty"`
+	// Keys are the cache keys deleted by InvalidateTags
+	Keys []string `json:"keys,omitempty"`
+	// Clear is set by InvalidateAll
+	Clear bool `json:"clear,omitempty"`
+}
+
+// invalidationBroadcast publishes the invalidations of a plugin and applies
+// those of the other instances
+type invalidationBroadcast struct {
+	client  redis.UniversalClient
+	pubsub  *redis.PubSub
+	channel string
+	origin  string
+}
+
+// startBroadcast subscribes to Config.PubSubChannel, once per plugin, and
+// deletes the patterns invalidated by other instances in the background
+func (p *CachePlugin) startBroadcast(ctx context.Context) error {
+	p.broadcastMu.Lock()
+	defer p.broadcastMu.Unlock()
+
+	if p.broadcast != nil {
+		return nil
+	}
+
+	adapter := p.adapter()
+	client, ok := pubSubClient(adapter)
+	if !ok {
+		return fmt.Errorf("gorm:cache: BroadcastInvalidation requires a Redis adapter, got %T", adapter)
+	}
+
+	origin := make([]byte, 8)
+	if _, err := rand.Read(origin); err != nil {
+		return err
+	}
+
+	pubsub := client.Subscribe(ctx, p.config.PubSubChannel)
+	// 等待订阅确认，避免初始化后立即发生的失效消息丢失
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return fmt.Errorf("gorm:cache: subscribe to %q: %w", p.config.PubSubChannel, err)
+	}
+
+	p.broadcast = &invalidationBroadcast{
+		client:  client,
+		pubsub:  pubsub,
+		channel: p.config.PubSubChannel,
+		origin:  hex.EncodeToString(origin),
+	}
+	go p.receiveInvalidations(p.broadcast)
+	return nil
+}
+
+// receiveInvalidations applies the invalidations published by other instances
+// until the subscription is closed
+func (p *CachePlugin) receiveInvalidations(b *invalidationBroadcast) {
+	for msg := range b.pubsub.Channel() {
+		var m invalidationMessage
+		if err := json.Unmarshal([]byte(msg.Payload), &m); err != nil {
+			p.onError(fmt.Errorf("gorm:cache: decode invalidation message: %w", err))
+			continue
+		}
+		if m.Origin == b.origin {
+			continue
+		}
+
+		if err := p.applyInvalidation(context.Background(), m); err != nil {
+			p.onError(err)
+		}
+	}
+}
+
+// applyInvalidation runs an invalidation received from another instance the
+// way a local one would run
+func (p *CachePlugin) applyInvalidation(ctx context.Context, m invalidationMessage) error {
+	switch {
+	case m.Clear:
+		return p.adapter().Clear(ctx)
+	case len(m.Keys) > 0:
+		var errs []error
+		for _, key := range m.Keys {
+			errs = append(errs, p.adapter().Delete(ctx, key))
+		}
+		return errors.Join(errs...)
+	case m.Pattern != "":
+		return p.invalidatePattern(ctx, m.Pattern)
+	}
+	return nil
+}
+
+// publishInvalidation tells the other instances to run the invalidation of m
+func (p *CachePlugin) publishInvalidation(ctx context.Context, m invalidationMessage) {
+	p.broadcastMu.Lock()
+	b := p.broadcast
+	p.broadcastMu.Unlock()
+	if b == nil {
+		return
+	}
+
+	m.Origin = b.origin
+	payload, err := json.Marshal(m)
+	if err == nil {
+		err = b.client.Publish(ctx, b.channel, payload).Err()
+	}
+	if err != nil {
+		p.onError(fmt.Errorf("gorm:cache: publish invalidation: %w", err))
+	}
+}
+
+// stopBroadcast closes the subscription started by startBroadcast
+func (p *CachePlugin) stopBroadcast() error {
+	p.broadcastMu.Lock()
+	defer p.broadcastMu.Unlock()
+
+	if p.broadcast == nil {
+		return nil
+	}
+	err := p.broadcast.pubsub.Close()
+	p.broadcast = nil
+	return err
+}
+
+// pubSubClient returns the Redis client backing adapter, looking through
+// wrapping adapters; the L1 of a TwoLevelAdapter is kept by each instance and
+// messages go through the shared L2
+func pubSubClient(adapter Adapter) (redis.UniversalClient, bool) {
+	for _, a := range adapterChain(adapter) {
+		switch a := a.(type) {
+		case *RedisAdapter:
+			return a.client, true
+		case *RedisSentinelAdapter:
+			return a.client, true
+		case *RedisClusterAdapter:
+			return a.client, true
+		}
+	}
+	return nil, false
+}
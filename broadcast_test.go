@@ -0,0 +1,136 @@
+package gormcache
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func TestBroadcastInvalidation(t *testing.T) {
+	mr := miniredis.RunT(t)
+
+	newInstance := func() (*CachePlugin, *MemoryAdapter) {
+		l1 := NewMemoryAdapter()
+		l2 := NewRedisAdapter(RedisAdapterConfig{Addr: mr.Addr()})
+		plugin := New(Config{
+			Adapter:               NewTwoLevelAdapter(l1, l2, time.Minute),
+			TTL:                   time.Minute,
+			InvalidateOnCreate:    true,
+			BroadcastInvalidation: true,
+		})
+		t.Cleanup(func() { plugin.Close() })
+		return plugin, l1
+	}
+
+	pluginA, _ := newInstance()
+	pluginB, l1B := newInstance()
+
+	dbA := setupTestDB(t)
+	if err := dbA.Use(pluginA); err != nil {
+		t.Fatalf("failed to use plugin A: %v", err)
+	}
+	dbB := setupTestDB(t)
+	if err := dbB.Use(pluginB); err != nil {
+		t.Fatalf("failed to use plugin B: %v", err)
+	}
+
+	// B 缓存查询结果，条目同时存在于它的 L1 和共享的 L2
+	dbB.Create(&TestUser{Name: "Bob"})
+	var users []TestUser
+	dbB.Find(&users)
+	ctx := context.Background()
+	if keys, _ := l1B.Scan(ctx, "gorm:cache:test_users*"); len(keys) == 0 {
+		t.Fatal("expected the query to be cached in the L1 of instance B")
+	}
+
+	dbA.Create(&TestUser{Name: "Alice"})
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		keys, _ := l1B.Scan(ctx, "gorm:cache:test_users*")
+		if len(keys) == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the write on instance A to invalidate the L1 of instance B, still cached: %v", keys)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestBroadcastInvalidationRequiresRedis(t *testing.T) {
+	plugin := New(Config{Adapter: NewMemoryAdapter(), BroadcastInvalidation: true})
+	defer plugin.Close()
+
+	db := setupTestDB(t)
+	err := db.Use(plugin)
+	if err == nil || !strings.Contains(err.Error(), "requires a Redis adapter") {
+		t.Errorf("expected Initialize to reject a non-Redis adapter, got %v", err)
+	}
+}
+
+func TestBroadcastManualInvalidation(t *testing.T) {
+	mr := miniredis.RunT(t)
+	ctx := context.Background()
+
+	newInstance := func() (*CachePlugin, *MemoryAdapter) {
+		l1 := NewMemoryAdapter()
+		l2 := NewRedisAdapter(RedisAdapterConfig{Addr: mr.Addr()})
+		plugin := New(Config{
+			Adapter:               NewTwoLevelAdapter(l1, l2, time.Minute),
+			TTL:                   time.Minute,
+			CacheTags:             map[interface{}][]string{TestUser{}: {"user-data"}},
+			BroadcastInvalidation: true,
+		})
+		t.Cleanup(func() { plugin.Close() })
+		return plugin, l1
+	}
+
+	pluginA, _ := newInstance()
+	pluginB, l1B := newInstance()
+	if err := setupTestDB(t).Use(pluginA); err != nil {
+		t.Fatalf("failed to use plugin A: %v", err)
+	}
+	dbB := setupTestDB(t)
+	if err := dbB.Use(pluginB); err != nil {
+		t.Fatalf("failed to use plugin B: %v", err)
+	}
+	dbB.Create(&TestUser{Name: "Bob"})
+
+	tests := []struct {
+		name       string
+		invalidate func() error
+	}{
+		{"table", func() error { return pluginA.InvalidateTable(ctx, "test_users") }},
+		{"tags", func() error { return pluginA.InvalidateTags(ctx, "user-data") }},
+		{"all", func() error { return pluginA.InvalidateAll(ctx) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var users []TestUser
+			dbB.Find(&users)
+			if keys, _ := l1B.Scan(ctx, "gorm:cache:test_users*"); len(keys) == 0 {
+				t.Fatal("expected the query to be cached in the L1 of instance B")
+			}
+
+			if err := tt.invalidate(); err != nil {
+				t.Fatalf("invalidation failed: %v", err)
+			}
+
+			deadline := time.Now().Add(2 * time.Second)
+			for {
+				keys, _ := l1B.Scan(ctx, "gorm:cache:test_users*")
+				if len(keys) == 0 {
+					break
+				}
+				if time.Now().After(deadline) {
+					t.Fatalf("expected the invalidation on instance A to reach the L1 of instance B, still cached: %v", keys)
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package gormcache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+var errAdapterDown = errors.New("adapter is down")
+
+// failingAdapter fails every operation
+type failingAdapter struct{}
+
+func (failingAdapter) Get(ctx context.Context, key string) ([]byte, error) {
+	return nil, errAdapterDown
+}
+
+func (failingAdapter) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return errAdapterDown
+}
+
+func (failingAdapter) Delete(ctx context.Context, key string) error { return errAdapterDown }
+
+func (failingAdapter) DeletePattern(ctx context.Context, pattern string) error {
+	return errAdapterDown
+}
+
+func (failingAdapter) Clear(ctx context.Context) error { return errAdapterDown }
+
+func (failingAdapter) Close() error { return nil }
+
+func TestIgnoreCacheErrors(t *testing.T) {
+	db := setupTestDB(t)
+
+	var reported []error
+	config := DefaultConfig()
+	config.Adapter = failingAdapter{}
+	config.OnError = func(err error) { reported = append(reported, err) }
+	cachePlugin := New(config)
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	if err := db.Create(&TestUser{Name: "John"}).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	var users []TestUser
+	if err := db.Find(&users).Error; err != nil {
+		t.Fatalf("expected the adapter error to be ignored, got %v", err)
+	}
+	if len(users) != 1 {
+		t.Errorf("expected 1 user from the database, got %d", len(users))
+	}
+
+	// Create 的失效、Get 和 Set 的错误都会上报
+	if len(reported) != 3 {
+		t.Fatalf("expected 3 reported errors, got %d: %v", len(reported), reported)
+	}
+	for _, err := range reported {
+		if !errors.Is(err, errAdapterDown) {
+			t.Errorf("expected the adapter error, got %v", err)
+		}
+	}
+}
+
+func TestCacheErrorsIgnoredByDefault(t *testing.T) {
+	db := setupTestDB(t)
+
+	// Config 字面量的零值同样忽略适配器错误
+	cachePlugin := New(Config{Adapter: failingAdapter{}, TTL: 5 * time.Minute})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	db.Create(&TestUser{Name: "John"})
+
+	var users []TestUser
+	if err := db.Find(&users).Error; err != nil {
+		t.Fatalf("expected the adapter error to be ignored, got %v", err)
+	}
+	if len(users) != 1 {
+		t.Errorf("expected 1 user from the database, got %d", len(users))
+	}
+}
+
+func TestPropagateCacheErrors(t *testing.T) {
+	db := setupTestDB(t)
+
+	var reported int
+	cachePlugin := New(Config{
+		Adapter:           failingAdapter{},
+		TTL:               5 * time.Minute,
+		FailOnCacheErrors: true,
+		OnError:           func(error) { reported++ },
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	queries := countQueries(t, db)
+
+	var users []TestUser
+	err := db.Find(&users).Error
+	if !errors.Is(err, errAdapterDown) {
+		t.Fatalf("expected the adapter error to fail the query, got %v", err)
+	}
+	if *queries != 0 {
+		t.Errorf("expected the database not to be queried, got %d queries", *queries)
+	}
+	if reported != 1 {
+		t.Errorf("expected OnError to be called once, got %d", reported)
+	}
+}
+
+func TestCacheMissIsNotAnError(t *testing.T) {
+	db := setupTestDB(t)
+
+	var reported int
+	cachePlugin := New(Config{
+		Adapter: NewMemoryAdapter(),
+		TTL:     5 * time.Minute,
+		OnError: func(error) { reported++ },
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	var users []TestUser
+	if err := db.Find(&users).Error; err != nil {
+		t.Fatalf("expected a cache miss not to fail the query, got %v", err)
+	}
+	if reported != 0 {
+		t.Errorf("expected a cache miss not to be reported, got %d errors", reported)
+	}
+}
+
+func TestAdaptersReportCacheMiss(t *testing.T) {
+	mr := miniredis.RunT(t)
+
+	adapters := map[string]Adapter{
+		"memory":    NewMemoryAdapter(),
+		"sync.Map":  NewMemoryAdapterWithSyncMap(),
+		"redis":     NewRedisAdapter(RedisAdapterConfig{Addr: mr.Addr()}),
+		"memcached": &MemcachedAdapter{client: newFakeMemcache()},
+	}
+	for name, adapter := range adapters {
+		t.Run(name, func(t *testing.T) {
+			defer adapter.Close()
+
+			ctx := context.Background()
+			if _, err := adapter.Get(ctx, "missing"); !errors.Is(err, ErrCacheMiss) {
+				t.Errorf("expected ErrCacheMiss for a missing key, got %v", err)
+			}
+
+			// fakeMemcache 不模拟过期
+			if name == "memcached" {
+				return
+			}
+			adapter.Set(ctx, "short", []byte("value"), time.Millisecond)
+			time.Sleep(5 * time.Millisecond)
+			mr.FastForward(time.Second)
+			if _, err := adapter.Get(ctx, "short"); !errors.Is(err, ErrCacheMiss) {
+				t.Errorf("expected ErrCacheMiss for an expired key, got %v", err)
+			}
+		})
+	}
+}
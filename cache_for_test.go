@@ -0,0 +1,55 @@
+package gormcache
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCacheForRawQuery(t *testing.T) {
+	db := setupTestDB(t)
+
+	adapter := NewMemoryAdapter()
+	cachePlugin := New(Config{
+		Adapter:            adapter,
+		TTL:                5 * time.Minute,
+		InvalidateOnUpdate: true,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	user := TestUser{Name: "Original Name"}
+	db.Create(&user)
+
+	query := func() string {
+		var rows []map[string]interface{}
+		db.Scopes(CacheFor(TestUser{})).Raw("SELECT name FROM test_users WHERE id = ?", user.ID).Find(&rows)
+		if len(rows) != 1 {
+			t.Fatalf("expected 1 row, got %d", len(rows))
+		}
+		return rows[0]["name"].(string)
+	}
+
+	if name := query(); name != "Original Name" {
+		t.Fatalf("expected 'Original Name', got '%s'", name)
+	}
+
+	// The raw query is cached in the test_users namespace
+	if len(adapter.store) == 0 {
+		t.Fatal("expected raw query to be cached")
+	}
+	for key := range adapter.store {
+		if !strings.HasPrefix(key, "gorm:cache:test_users:") {
+			t.Errorf("expected key in test_users namespace, got %q", key)
+		}
+	}
+
+	// Updating a user invalidates the raw query
+	db.Model(&user).Update("Name", "Updated Name")
+
+	if name := query(); name != "Updated Name" {
+		t.Errorf("expected 'Updated Name', got '%s'", name)
+	}
+}
@@ -0,0 +1,72 @@
+package gormcache
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// cacheStatusLogger wraps a GORM logger and prefixes traced queries with
+// [CACHE HIT] or [CACHE MISS]
+type cacheStatusLogger struct {
+	logger.Interface
+}
+
+// newCacheStatusLogger wraps l, unless it is already wrapped
+func newCacheStatusLogger(l logger.Interface) logger.Interface {
+	if _, ok := l.(*cacheStatusLogger); ok {
+		return l
+	}
+	return &cacheStatusLogger{Interface: l}
+}
+
+// LogMode returns a wrapped logger with the given log level
+func (l *cacheStatusLogger) LogMode(level logger.LogLevel) logger.Interface {
+	return &cacheStatusLogger{Interface: l.Interface.LogMode(level)}
+}
+
+// Trace prefixes the SQL with the cache status recorded in ctx, if any
+func (l *cacheStatusLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	hit, ok := ctx.Value(contextKeyCacheHit).(bool)
+	if !ok {
+		l.Interface.Trace(ctx, begin, fc, err)
+		return
+	}
+
+	prefix := "[CACHE MISS] "
+	if hit {
+		prefix = "[CACHE HIT] "
+	}
+	l.Interface.Trace(ctx, begin, func() (string, int64) {
+		sql, rowsAffected := fc()
+		return prefix + sql, rowsAffected
+	}, err)
+}
+
+// ParamsFilter delegates to the wrapped logger, if it filters params
+func (l *cacheStatusLogger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	if filter, ok := l.Interface.(gorm.ParamsFilter); ok {
+		return filter.ParamsFilter(ctx, sql, params...)
+	}
+	return sql, params
+}
+
+// annotateCacheStatus copies the cache status of the statement into its
+// context, where the logger can read it when the query is traced
+func annotateCacheStatus(db *gorm.DB) {
+	ctx := db.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// 未查询缓存（如跳过缓存）时清除状态，避免沿用上一次的结果
+	var status interface{}
+	if hit, ok := db.Statement.Settings.Load("gorm:cache:hit"); ok {
+		status = hit
+	} else if ctx.Value(contextKeyCacheHit) == nil {
+		return
+	}
+	db.Statement.Context = context.WithValue(ctx, contextKeyCacheHit, status)
+}
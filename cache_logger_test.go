@@ -0,0 +1,71 @@
+package gormcache
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm/logger"
+)
+
+func TestInstrumentGORMLogger(t *testing.T) {
+	db := setupTestDB(t)
+
+	var buf bytes.Buffer
+	db.Logger = logger.New(log.New(&buf, "", 0), logger.Config{
+		LogLevel: logger.Info,
+		Colorful: false,
+	})
+
+	cachePlugin := New(Config{
+		Adapter:              NewMemoryAdapter(),
+		TTL:                  5 * time.Minute,
+		InstrumentGORMLogger: true,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	user := TestUser{Name: "Logged"}
+	db.Create(&user)
+	if strings.Contains(buf.String(), "[CACHE") {
+		t.Errorf("expected writes not to be annotated, got %q", buf.String())
+	}
+
+	lastLine := func() string {
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		return lines[len(lines)-1]
+	}
+
+	var first TestUser
+	db.First(&first, user.ID)
+	if line := lastLine(); !strings.Contains(line, "[CACHE MISS] SELECT") {
+		t.Errorf("expected a cache miss annotation, got %q", line)
+	}
+
+	var second TestUser
+	db.First(&second, user.ID)
+	if line := lastLine(); !strings.Contains(line, "[CACHE HIT] SELECT") {
+		t.Errorf("expected a cache hit annotation, got %q", line)
+	}
+
+	var third TestUser
+	db.Scopes(SkipCache()).First(&third, user.ID)
+	if line := lastLine(); strings.Contains(line, "[CACHE") {
+		t.Errorf("expected skipped queries not to be annotated, got %q", line)
+	}
+}
+
+func TestInstrumentGORMLoggerLogMode(t *testing.T) {
+	wrapped := newCacheStatusLogger(logger.Discard)
+
+	if _, ok := wrapped.LogMode(logger.Silent).(*cacheStatusLogger); !ok {
+		t.Error("expected LogMode to keep the wrapper")
+	}
+	if newCacheStatusLogger(wrapped) != wrapped {
+		t.Error("expected an already wrapped logger not to be wrapped again")
+	}
+}
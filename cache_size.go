@@ -0,0 +1,59 @@
+package gormcache
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// CacheSize returns the number of entries currently cached
+// Adapters implementing ScannableAdapter only count the query results under
+// Config.KeyPrefix; sidecars and stale markers are left out
+func (p *CachePlugin) CacheSize(ctx context.Context) (int, error) {
+	if !scannable(p.adapter()) {
+		return countEntries(ctx, p.adapter(), p.config.KeyPrefix+"*")
+	}
+
+	keys, err := scanKeys(ctx, p.adapter(), p.config.KeyPrefix+"*")
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, key := range keys {
+		if p.isEntryKey(key) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// isEntryKey reports whether key holds a cached query result rather than data
+// kept by the plugin about other entries
+func (p *CachePlugin) isEntryKey(key string) bool {
+	return !isSidecarKey(key) && !strings.HasPrefix(key, staleMarkerPrefix)
+}
+
+// scannable reports whether adapter, or an adapter it wraps, can list its keys
+func scannable(adapter Adapter) bool {
+	for _, a := range adapterChain(adapter) {
+		if _, ok := a.(ScannableAdapter); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// countEntries counts the entries of adapter, looking through wrapping
+// adapters
+func countEntries(ctx context.Context, adapter Adapter, pattern string) (int, error) {
+	for _, a := range adapterChain(adapter) {
+		switch a := a.(type) {
+		case *RedisAdapter:
+			// DBSIZE 统计的是整个数据库，这里只统计本插件前缀下的 key
+			return a.CountPattern(ctx, pattern)
+		case CountableAdapter:
+			return a.Count(ctx)
+		}
+	}
+	return 0, fmt.Errorf("gorm:cache: adapter %T does not implement CountableAdapter", adapter)
+}
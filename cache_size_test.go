@@ -0,0 +1,121 @@
+package gormcache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func TestCacheSize(t *testing.T) {
+	db := setupTestDB(t)
+
+	cachePlugin := New(Config{
+		Adapter: NewMemoryAdapter(),
+		TTL:     5 * time.Minute,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	const n = 25
+	for i := 0; i < n; i++ {
+		db.Create(&TestUser{Name: fmt.Sprintf("User %d", i)})
+	}
+	for i := 1; i <= n; i++ {
+		var user TestUser
+		db.First(&user, i)
+	}
+
+	size, err := cachePlugin.CacheSize(context.Background())
+	if err != nil {
+		t.Fatalf("failed to get cache size: %v", err)
+	}
+	if size != n {
+		t.Errorf("expected %d cached entries, got %d", n, size)
+	}
+}
+
+func TestCacheSizeSyncMap(t *testing.T) {
+	adapter := NewMemoryAdapterWithSyncMap()
+	defer adapter.Close()
+
+	ctx := context.Background()
+	for i := 0; i < 10; i++ {
+		adapter.Set(ctx, fmt.Sprintf("key:%d", i), []byte("value"), 1*time.Minute)
+	}
+
+	if n, err := adapter.Count(ctx); err != nil || n != 10 {
+		t.Errorf("expected 10 entries, got %d, %v", n, err)
+	}
+}
+
+func TestCacheSizeRedisNamespace(t *testing.T) {
+	mr := miniredis.RunT(t)
+	ctx := context.Background()
+
+	cachePlugin := New(Config{
+		Adapter:  NewRedisAdapter(RedisAdapterConfig{Addr: mr.Addr()}),
+		TTL:      5 * time.Minute,
+		ReadOnly: true,
+	})
+	defer cachePlugin.Close()
+
+	for i := 0; i < 10; i++ {
+		mr.Set(fmt.Sprintf("gorm:cache:test_users:%d", i), "value")
+	}
+	// Keys of other applications sharing the database are not counted
+	mr.Set("session:1", "value")
+	mr.Set("session:2", "value")
+
+	size, err := cachePlugin.CacheSize(ctx)
+	if err != nil {
+		t.Fatalf("failed to get cache size: %v", err)
+	}
+	if size != 10 {
+		t.Errorf("expected 10 cached entries, got %d", size)
+	}
+
+	adapter := NewRedisAdapter(RedisAdapterConfig{Addr: mr.Addr()})
+	defer adapter.Close()
+
+	if n, err := adapter.Count(ctx); err != nil || n != 12 {
+		t.Errorf("expected DBSIZE of 12, got %d, %v", n, err)
+	}
+}
+
+func TestCacheSizeSkipsSidecars(t *testing.T) {
+	db := setupTestDB(t)
+
+	cachePlugin := New(Config{
+		Adapter:          NewMetricsAdapter(NewMemoryAdapter(), "gormcache_test_cache_size"),
+		TTL:              5 * time.Minute,
+		MaxQueryCacheAge: time.Minute,
+		CacheTags:        map[interface{}][]string{TestUser{}: {"user-data"}},
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	const n = 5
+	for i := 0; i < n; i++ {
+		db.Create(&TestUser{Name: fmt.Sprintf("User %d", i)})
+	}
+	for i := 1; i <= n; i++ {
+		var user TestUser
+		db.First(&user, i)
+	}
+
+	// Metadata sidecars and the tag index are not cached queries
+	size, err := cachePlugin.CacheSize(context.Background())
+	if err != nil {
+		t.Fatalf("failed to get cache size: %v", err)
+	}
+	if size != n {
+		t.Errorf("expected %d cached entries, got %d", n, size)
+	}
+}
@@ -0,0 +1,90 @@
+package gormcache
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCacheVersion(t *testing.T) {
+	db := setupTestDB(t)
+
+	adapter := NewMemoryAdapter()
+	v1 := New(Config{Adapter: adapter, TTL: 5 * time.Minute, CacheVersion: "v1"})
+	v2 := New(Config{Adapter: adapter, TTL: 5 * time.Minute, CacheVersion: "v2"})
+
+	user := TestUser{Name: "Versioned"}
+	db.Create(&user)
+
+	if err := db.Use(v1); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+
+	var first TestUser
+	db.First(&first, user.ID)
+
+	if len(adapter.store) != 1 {
+		t.Fatalf("expected 1 cached entry, got %d", len(adapter.store))
+	}
+	for key := range adapter.store {
+		if !strings.HasPrefix(key, "gorm:cache:v1:test_users:") {
+			t.Errorf("expected the key to contain the cache version, got %q", key)
+		}
+	}
+
+	// Simulate a deployment bumping the cache version
+	db2 := setupTestDB(t)
+	db2.Create(&TestUser{Name: "Versioned"})
+	if err := db2.Use(v2); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	queries := countQueries(t, db2)
+
+	var second TestUser
+	db2.First(&second, user.ID)
+	if *queries != 1 {
+		t.Errorf("expected the v2 plugin to miss the v1 entry, got %d database queries", *queries)
+	}
+	if len(adapter.store) != 2 {
+		t.Errorf("expected v1 and v2 entries side by side, got %d", len(adapter.store))
+	}
+
+	var third TestUser
+	db2.First(&third, user.ID)
+	if *queries != 1 {
+		t.Errorf("expected the v2 entry to be hit, got %d database queries", *queries)
+	}
+}
+
+func TestCacheVersionInvalidation(t *testing.T) {
+	db := setupTestDB(t)
+
+	adapter := NewMemoryAdapter()
+	cachePlugin := New(Config{
+		Adapter:            adapter,
+		TTL:                5 * time.Minute,
+		CacheVersion:       "v2",
+		InvalidateOnCreate: true,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+
+	var users []TestUser
+	db.Find(&users)
+	if len(adapter.store) != 0 {
+		t.Fatalf("expected empty results not to be cached, got %d entries", len(adapter.store))
+	}
+
+	db.Create(&TestUser{Name: "First"})
+	db.Find(&users)
+	if len(adapter.store) != 1 {
+		t.Fatalf("expected 1 cached entry, got %d", len(adapter.store))
+	}
+
+	// Writes invalidate entries of the current version
+	db.Create(&TestUser{Name: "Second"})
+	if len(adapter.store) != 0 {
+		t.Errorf("expected the versioned entry to be invalidated, got %d entries", len(adapter.store))
+	}
+}
@@ -0,0 +1,280 @@
+package gormcache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned by CircuitBreakerAdapter operations while the
+// circuit is open
+var ErrCircuitOpen = errors.New("gorm:cache: circuit breaker is open")
+
+const (
+	defaultOpenThreshold  = 5
+	defaultResetTimeout   = 30 * time.Second
+	defaultHalfOpenProbes = 1
+)
+
+// CircuitBreakerConfig holds configuration for the circuit breaker adapter
+type CircuitBreakerConfig struct {
+	OpenThreshold  int           // Consecutive failures opening the circuit (default: 5)
+	ResetTimeout   time.Duration // Time the circuit stays open before allowing probes (default: 30s)
+	HalfOpenProbes int           // Trial operations allowed while half-open (default: 1)
+}
+
+// circuitState is the state of a CircuitBreakerAdapter
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// CircuitBreakerAdapter wraps an adapter and stops calling it after
+// OpenThreshold consecutive failures, failing fast with ErrCircuitOpen instead
+// After ResetTimeout, up to HalfOpenProbes operations are let through: a
+// successful one closes the circuit, a failed one opens it again
+// Cache misses count as successes
+// Invalidations rejected while the circuit is open are recorded and replayed
+// before any other operation once the inner adapter is reachable again, so
+// entries written before the outage are not served after a missed write
+type CircuitBreakerAdapter struct {
+	inner  Adapter
+	config CircuitBreakerConfig
+
+	mu       sync.Mutex
+	state    circuitState
+	failures int
+	openedAt time.Time
+	probes   int
+
+	// pending holds the invalidations rejected while open
+	pending pendingInvalidations
+}
+
+// pendingInvalidations are invalidations waiting to be replayed
+type pendingInvalidations struct {
+	clear    bool
+	keys     map[string]struct{}
+	patterns map[string]struct{}
+}
+
+// empty reports whether nothing is waiting to be replayed
+func (p *pendingInvalidations) empty() bool {
+	return !p.clear && len(p.keys) == 0 && len(p.patterns) == 0
+}
+
+// addKey records the deletion of key
+func (p *pendingInvalidations) addKey(key string) {
+	if p.keys == nil {
+		p.keys = make(map[string]struct{})
+	}
+	p.keys[key] = struct{}{}
+}
+
+// addPattern records the deletion of the keys matching pattern
+func (p *pendingInvalidations) addPattern(pattern string) {
+	if p.patterns == nil {
+		p.patterns = make(map[string]struct{})
+	}
+	p.patterns[pattern] = struct{}{}
+}
+
+// NewCircuitBreakerAdapter creates a new circuit breaker adapter around inner
+func NewCircuitBreakerAdapter(inner Adapter, config CircuitBreakerConfig) *CircuitBreakerAdapter {
+	if config.OpenThreshold <= 0 {
+		config.OpenThreshold = defaultOpenThreshold
+	}
+	if config.ResetTimeout <= 0 {
+		config.ResetTimeout = defaultResetTimeout
+	}
+	if config.HalfOpenProbes <= 0 {
+		config.HalfOpenProbes = defaultHalfOpenProbes
+	}
+	return &CircuitBreakerAdapter{inner: inner, config: config}
+}
+
+// allow reports whether an operation may call the inner adapter, moving an
+// open circuit to half-open once ResetTimeout has elapsed
+func (a *CircuitBreakerAdapter) allow() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.state == circuitOpen {
+		if time.Since(a.openedAt) < a.config.ResetTimeout {
+			return ErrCircuitOpen
+		}
+		a.state = circuitHalfOpen
+		a.probes = 0
+	}
+
+	if a.state == circuitHalfOpen {
+		if a.probes >= a.config.HalfOpenProbes {
+			return ErrCircuitOpen
+		}
+		a.probes++
+	}
+	return nil
+}
+
+// record updates the circuit with the result of an operation
+func (a *CircuitBreakerAdapter) record(err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err == nil || errors.Is(err, ErrCacheMiss) {
+		a.state = circuitClosed
+		a.failures = 0
+		return
+	}
+
+	a.failures++
+	// 半开状态下探测失败立即重新打开
+	if a.state == circuitHalfOpen || a.failures >= a.config.OpenThreshold {
+		a.state = circuitOpen
+		a.openedAt = time.Now()
+	}
+}
+
+// currentState returns the state of the circuit
+func (a *CircuitBreakerAdapter) currentState() circuitState {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.state
+}
+
+// do runs op through the circuit breaker, replaying pending invalidations
+// first
+func (a *CircuitBreakerAdapter) do(ctx context.Context, op func() error) error {
+	if err := a.allow(); err != nil {
+		return err
+	}
+	if err := a.replay(ctx); err != nil {
+		a.record(err)
+		return err
+	}
+	err := op()
+	a.record(err)
+	return err
+}
+
+// invalidate runs an invalidation through the circuit breaker, recording it
+// with add for replay if the circuit rejects it
+func (a *CircuitBreakerAdapter) invalidate(ctx context.Context, op func() error, add func(*pendingInvalidations)) error {
+	err := a.do(ctx, op)
+	if errors.Is(err, ErrCircuitOpen) {
+		a.mu.Lock()
+		add(&a.pending)
+		a.mu.Unlock()
+	}
+	return err
+}
+
+// replay runs the pending invalidations against the inner adapter, keeping
+// those that fail for a later attempt
+func (a *CircuitBreakerAdapter) replay(ctx context.Context) error {
+	a.mu.Lock()
+	if a.pending.empty() {
+		a.mu.Unlock()
+		return nil
+	}
+	pending := a.pending
+	a.pending = pendingInvalidations{}
+	a.mu.Unlock()
+
+	// 失败时放回未完成的失效操作，与期间新增的合并
+	restore := func(rest pendingInvalidations) {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		if rest.clear {
+			a.pending.clear = true
+		}
+		for key := range rest.keys {
+			a.pending.addKey(key)
+		}
+		for pattern := range rest.patterns {
+			a.pending.addPattern(pattern)
+		}
+	}
+
+	if pending.clear {
+		if err := a.inner.Clear(ctx); err != nil {
+			restore(pending)
+			return err
+		}
+		return nil
+	}
+	for pattern := range pending.patterns {
+		if err := a.inner.DeletePattern(ctx, pattern); err != nil {
+			restore(pending)
+			return err
+		}
+		delete(pending.patterns, pattern)
+	}
+	for key := range pending.keys {
+		if err := a.inner.Delete(ctx, key); err != nil {
+			restore(pending)
+			return err
+		}
+		delete(pending.keys, key)
+	}
+	return nil
+}
+
+// Get retrieves a value from the inner adapter
+func (a *CircuitBreakerAdapter) Get(ctx context.Context, key string) ([]byte, error) {
+	var value []byte
+	err := a.do(ctx, func() (err error) {
+		value, err = a.inner.Get(ctx, key)
+		return err
+	})
+	return value, err
+}
+
+// Set stores a value in the inner adapter
+func (a *CircuitBreakerAdapter) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return a.do(ctx, func() error {
+		return a.inner.Set(ctx, key, value, ttl)
+	})
+}
+
+// Delete removes a value from the inner adapter
+func (a *CircuitBreakerAdapter) Delete(ctx context.Context, key string) error {
+	return a.invalidate(ctx, func() error {
+		return a.inner.Delete(ctx, key)
+	}, func(p *pendingInvalidations) {
+		p.addKey(key)
+	})
+}
+
+// DeletePattern removes all keys matching the pattern from the inner adapter
+func (a *CircuitBreakerAdapter) DeletePattern(ctx context.Context, pattern string) error {
+	return a.invalidate(ctx, func() error {
+		return a.inner.DeletePattern(ctx, pattern)
+	}, func(p *pendingInvalidations) {
+		p.addPattern(pattern)
+	})
+}
+
+// Clear removes all cached data from the inner adapter
+func (a *CircuitBreakerAdapter) Clear(ctx context.Context) error {
+	return a.invalidate(ctx, func() error {
+		return a.inner.Clear(ctx)
+	}, func(p *pendingInvalidations) {
+		// 清空缓存覆盖所有其他失效操作
+		*p = pendingInvalidations{clear: true}
+	})
+}
+
+// Close closes the inner adapter
+func (a *CircuitBreakerAdapter) Close() error {
+	return a.inner.Close()
+}
+
+// Unwrap returns the inner adapter
+func (a *CircuitBreakerAdapter) Unwrap() Adapter {
+	return a.inner
+}
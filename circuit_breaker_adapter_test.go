@@ -0,0 +1,154 @@
+package gormcache
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// switchableAdapter fails every operation while down, and counts the calls
+// reaching it
+type switchableAdapter struct {
+	Adapter
+	down  atomic.Bool
+	calls atomic.Int64
+}
+
+func (a *switchableAdapter) Get(ctx context.Context, key string) ([]byte, error) {
+	a.calls.Add(1)
+	if a.down.Load() {
+		return nil, errAdapterDown
+	}
+	return a.Adapter.Get(ctx, key)
+}
+
+func (a *switchableAdapter) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	a.calls.Add(1)
+	if a.down.Load() {
+		return errAdapterDown
+	}
+	return a.Adapter.Set(ctx, key, value, ttl)
+}
+
+func TestCircuitBreakerAdapter(t *testing.T) {
+	inner := &switchableAdapter{Adapter: NewMemoryAdapter()}
+	adapter := NewCircuitBreakerAdapter(inner, CircuitBreakerConfig{
+		OpenThreshold:  5,
+		ResetTimeout:   50 * time.Millisecond,
+		HalfOpenProbes: 1,
+	})
+	defer adapter.Close()
+
+	ctx := context.Background()
+	inner.down.Store(true)
+
+	for i := 0; i < 5; i++ {
+		if _, err := adapter.Get(ctx, "key"); !errors.Is(err, errAdapterDown) {
+			t.Fatalf("call %d: expected adapter error, got %v", i, err)
+		}
+	}
+	if state := adapter.currentState(); state != circuitOpen {
+		t.Fatalf("expected circuit to open after 5 failures, got state %d", state)
+	}
+
+	// While open, operations fail fast without reaching the inner adapter
+	calls := inner.calls.Load()
+	if err := adapter.Set(ctx, "key", []byte("value"), time.Minute); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected ErrCircuitOpen, got %v", err)
+	}
+	if _, err := adapter.Get(ctx, "key"); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected ErrCircuitOpen, got %v", err)
+	}
+	if inner.calls.Load() != calls {
+		t.Error("expected the inner adapter not to be called while the circuit is open")
+	}
+
+	// After ResetTimeout, one successful probe closes the circuit
+	inner.down.Store(false)
+	time.Sleep(60 * time.Millisecond)
+
+	if _, err := adapter.Get(ctx, "key"); !errors.Is(err, ErrCacheMiss) {
+		t.Errorf("expected the probe to reach the inner adapter, got %v", err)
+	}
+	if state := adapter.currentState(); state != circuitClosed {
+		t.Fatalf("expected circuit to close after a successful probe, got state %d", state)
+	}
+	if err := adapter.Set(ctx, "key", []byte("value"), time.Minute); err != nil {
+		t.Errorf("expected Set to succeed once closed, got %v", err)
+	}
+}
+
+func TestCircuitBreakerAdapterFailedProbe(t *testing.T) {
+	inner := &switchableAdapter{Adapter: NewMemoryAdapter()}
+	adapter := NewCircuitBreakerAdapter(inner, CircuitBreakerConfig{
+		OpenThreshold: 2,
+		ResetTimeout:  50 * time.Millisecond,
+	})
+	defer adapter.Close()
+
+	ctx := context.Background()
+	inner.down.Store(true)
+	adapter.Get(ctx, "key")
+	adapter.Get(ctx, "key")
+
+	time.Sleep(60 * time.Millisecond)
+
+	// The probe fails and reopens the circuit; further calls fail fast
+	if _, err := adapter.Get(ctx, "key"); !errors.Is(err, errAdapterDown) {
+		t.Fatalf("expected the probe to reach the inner adapter, got %v", err)
+	}
+	if _, err := adapter.Get(ctx, "key"); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected ErrCircuitOpen after a failed probe, got %v", err)
+	}
+}
+
+func TestCircuitBreakerAdapterCacheMissIsSuccess(t *testing.T) {
+	inner := &switchableAdapter{Adapter: NewMemoryAdapter()}
+	adapter := NewCircuitBreakerAdapter(inner, CircuitBreakerConfig{OpenThreshold: 2})
+	defer adapter.Close()
+
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		adapter.Get(ctx, "missing")
+	}
+	if state := adapter.currentState(); state != circuitClosed {
+		t.Errorf("expected cache misses to keep the circuit closed, got state %d", state)
+	}
+}
+
+func TestCircuitBreakerAdapterReplaysInvalidations(t *testing.T) {
+	inner := &switchableAdapter{Adapter: NewMemoryAdapter()}
+	adapter := NewCircuitBreakerAdapter(inner, CircuitBreakerConfig{
+		OpenThreshold: 1,
+		ResetTimeout:  50 * time.Millisecond,
+	})
+	defer adapter.Close()
+
+	ctx := context.Background()
+	adapter.Set(ctx, "gorm:cache:users:1", []byte("stale"), time.Minute)
+	adapter.Set(ctx, "gorm:cache:orders:1", []byte("kept"), time.Minute)
+
+	inner.down.Store(true)
+	adapter.Get(ctx, "gorm:cache:users:1")
+	if state := adapter.currentState(); state != circuitOpen {
+		t.Fatalf("expected circuit to open, got state %d", state)
+	}
+
+	// 熔断期间的失效操作被记录下来
+	if err := adapter.DeletePattern(ctx, "gorm:cache:users*"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+
+	inner.down.Store(false)
+	time.Sleep(60 * time.Millisecond)
+
+	// The invalidation is replayed before the probe reads the stale entry
+	if value, err := adapter.Get(ctx, "gorm:cache:users:1"); !errors.Is(err, ErrCacheMiss) {
+		t.Errorf("expected the missed invalidation to be replayed, got %q, %v", value, err)
+	}
+	if value, err := adapter.Get(ctx, "gorm:cache:orders:1"); err != nil || string(value) != "kept" {
+		t.Errorf("expected other entries to be kept, got %q, %v", value, err)
+	}
+}
@@ -0,0 +1,111 @@
+package gormcache
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// CompressionType selects how cached values are compressed
+type CompressionType int
+
+const (
+	// CompressionNone stores serialized values as is (default)
+	CompressionNone CompressionType = iota
+	// CompressionGzip compresses values with compress/gzip
+	CompressionGzip
+	// CompressionZstd compresses values with Zstandard
+	CompressionZstd
+)
+
+// codec compresses serialized values before they are stored and decompresses
+// them after they are loaded
+type codec interface {
+	compress(data []byte) ([]byte, error)
+	decompress(data []byte) ([]byte, error)
+}
+
+// newCodec returns the codec for compression at the given gzip-style level,
+// or nil if values are not compressed
+func newCodec(compression CompressionType, level int) codec {
+	switch compression {
+	case CompressionGzip:
+		if level == 0 || level < gzip.HuffmanOnly || level > gzip.BestCompression {
+			level = gzip.DefaultCompression
+		}
+		return &gzipCodec{level: level}
+	case CompressionZstd:
+		encoder, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstdLevel(level)))
+		if err != nil {
+			return nil
+		}
+		decoder, err := zstd.NewReader(nil)
+		if err != nil {
+			return nil
+		}
+		return &zstdCodec{encoder: encoder, decoder: decoder}
+	}
+	return nil
+}
+
+// zstdLevel maps a gzip-style level (1 = best speed, 9 = best compression) to
+// the closest zstd encoder level
+func zstdLevel(level int) zstd.EncoderLevel {
+	switch {
+	case level <= 0:
+		return zstd.SpeedDefault
+	case level <= 2:
+		return zstd.SpeedFastest
+	case level <= 5:
+		return zstd.SpeedDefault
+	case level <= 8:
+		return zstd.SpeedBetterCompression
+	}
+	return zstd.SpeedBestCompression
+}
+
+// gzipCodec compresses values with compress/gzip
+type gzipCodec struct {
+	level int
+}
+
+func (c *gzipCodec) compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := gzip.NewWriterLevel(&buf, c.level)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (c *gzipCodec) decompress(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// zstdCodec compresses values with Zstandard; EncodeAll and DecodeAll are safe
+// for concurrent use
+type zstdCodec struct {
+	encoder *zstd.Encoder
+	decoder *zstd.Decoder
+}
+
+func (c *zstdCodec) compress(data []byte) ([]byte, error) {
+	return c.encoder.EncodeAll(data, nil), nil
+}
+
+func (c *zstdCodec) decompress(data []byte) ([]byte, error) {
+	return c.decoder.DecodeAll(data, nil)
+}
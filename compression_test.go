@@ -0,0 +1,131 @@
+package gormcache
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestCodecRoundTrip(t *testing.T) {
+	payload, _ := json.Marshal(serializerFixture())
+
+	for _, tt := range []struct {
+		name        string
+		compression CompressionType
+		level       int
+	}{
+		{"gzip default", CompressionGzip, 0},
+		{"gzip best speed", CompressionGzip, gzip.BestSpeed},
+		{"gzip best compression", CompressionGzip, gzip.BestCompression},
+		{"zstd default", CompressionZstd, 0},
+		{"zstd best speed", CompressionZstd, gzip.BestSpeed},
+		{"zstd best compression", CompressionZstd, gzip.BestCompression},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newCodec(tt.compression, tt.level)
+			if c == nil {
+				t.Fatal("expected a codec")
+			}
+
+			compressed, err := c.compress(payload)
+			if err != nil {
+				t.Fatalf("compress failed: %v", err)
+			}
+			if len(compressed) >= len(payload) {
+				t.Errorf("expected compression to shrink %d bytes, got %d", len(payload), len(compressed))
+			}
+
+			decompressed, err := c.decompress(compressed)
+			if err != nil {
+				t.Fatalf("decompress failed: %v", err)
+			}
+			if !bytes.Equal(decompressed, payload) {
+				t.Error("round trip mismatch")
+			}
+		})
+	}
+
+	if newCodec(CompressionNone, 9) != nil {
+		t.Error("expected no codec without compression")
+	}
+}
+
+func TestCompression(t *testing.T) {
+	for _, compression := range []CompressionType{CompressionGzip, CompressionZstd} {
+		t.Run(fmt.Sprint(compression), func(t *testing.T) {
+			db := setupTestDB(t)
+
+			adapter := NewMemoryAdapter()
+			cachePlugin := New(Config{
+				Adapter:     adapter,
+				TTL:         5 * time.Minute,
+				Compression: compression,
+			})
+			if err := db.Use(cachePlugin); err != nil {
+				t.Fatalf("failed to install plugin: %v", err)
+			}
+			defer cachePlugin.Close()
+
+			for i := 0; i < 100; i++ {
+				db.Create(&TestUser{Name: fmt.Sprintf("user-%03d", i)})
+			}
+			queries := countQueries(t, db)
+
+			var first []TestUser
+			db.Find(&first)
+
+			var stored []byte
+			for _, item := range adapter.store {
+				stored = item.value
+			}
+			serialized, _ := json.Marshal(first)
+			if len(stored) >= len(serialized) {
+				t.Errorf("expected the stored value to be compressed, got %d bytes for %d serialized", len(stored), len(serialized))
+			}
+
+			var second []TestUser
+			db.Find(&second)
+			if *queries != 1 {
+				t.Errorf("expected the second query to be cached, got %d database queries", *queries)
+			}
+			if len(second) != 100 || second[99].Name != "user-099" {
+				t.Errorf("expected the decompressed result, got %d users", len(second))
+			}
+		})
+	}
+}
+
+func TestCompressionUncompressedEntry(t *testing.T) {
+	db := setupTestDB(t)
+
+	adapter := NewMemoryAdapter()
+	uncompressed := New(Config{Adapter: adapter, TTL: 5 * time.Minute})
+	if err := db.Use(uncompressed); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	user := TestUser{Name: "Legacy"}
+	db.Create(&user)
+
+	var first TestUser
+	db.First(&first, user.ID)
+
+	// Entries written before compression was enabled are treated as misses
+	db2 := setupTestDB(t)
+	db2.Create(&TestUser{Name: "Legacy"})
+	compressed := New(Config{Adapter: adapter, TTL: 5 * time.Minute, Compression: CompressionGzip})
+	if err := db2.Use(compressed); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	queries := countQueries(t, db2)
+
+	var second TestUser
+	if err := db2.First(&second, user.ID).Error; err != nil || second.Name != "Legacy" {
+		t.Fatalf("expected the database result, got %+v (error: %v)", second, err)
+	}
+	if *queries != 1 {
+		t.Errorf("expected the uncompressed entry to miss, got %d database queries", *queries)
+	}
+}
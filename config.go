@@ -1,13 +1,18 @@
 package gormcache
 
 import (
-	"crypto/md5"
-	"encoding/hex"
+	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"text/template"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // Config holds the configuration for the cache plugin
@@ -18,7 +23,8 @@ type Config struct {
 	// TTL is the default time-to-live for cached data
 	TTL time.Duration
 
-	// CacheModels defines which models should be cached
+	// CacheModels defines which models should be cached, as zero-value
+	// instances (User{}) or their reflect.Type (reflect.TypeOf(User{}))
 	// If empty, all models will be cached
 	CacheModels []interface{}
 
@@ -45,21 +51,388 @@ type Config struct {
 	// Serializer is the data serialization implementation
 	// If nil, default JSON serializer will be used
 	Serializer Serializer
+
+	// SkipForLockingClauses skips cache for queries carrying a locking clause
+	// (SELECT ... FOR UPDATE / FOR SHARE) or an ON CONFLICT clause, which must
+	// always reach the database
+	SkipForLockingClauses bool
+
+	// AutoVersionFromSchema includes a short hash of the database schema in all
+	// cache keys; the hash is refreshed by the first query after a migration, so
+	// entries cached with an older schema shape become unreachable
+	AutoVersionFromSchema bool
+
+	// KeyHashAlgorithm is the hash function applied to the query in cache keys
+	// Default is HashMD5
+	KeyHashAlgorithm HashAlgorithm
+
+	// KeyHasher hashes the query in cache keys, replacing KeyHashAlgorithm
+	// (e.g. MD5Hasher, XXHashHasher); changing it makes existing entries unreachable
+	// If nil, KeyHashAlgorithm is used
+	KeyHasher func(data []byte) string
+
+	// KeyEncoder encodes the KeyHashAlgorithm hash of the query in cache keys
+	// (e.g. HexEncoder, Base64URLEncoder); changing it makes existing entries
+	// unreachable, and it is not used with KeyHasher
+	// If nil, hashes are hex encoded
+	KeyEncoder func(hash []byte) string
+
+	// KeyHashLength truncates the encoded query hash to the given number of
+	// characters (32 keeps full MD5 compatible keys, 16 gives shorter keys)
+	// If 0, the full hash is used
+	KeyHashLength int
+
+	// WarmupQueries are the queries executed by CachePlugin.WarmUp when it is
+	// given none
+	WarmupQueries []WarmQuery
+
+	// MaxConcurrentWarmups limits how many warmup queries run simultaneously
+	// Default is 5
+	MaxConcurrentWarmups int
+
+	// WarmupStaggerInterval spreads warmup queries over time by starting them
+	// one interval apart instead of all at once
+	// If 0, all warmup queries are started immediately
+	WarmupStaggerInterval time.Duration
+
+	// PanicOnNilContext panics when a statement reaches the cache without a context
+	// instead of silently falling back to context.Background()
+	PanicOnNilContext bool
+
+	// HotKeyThreshold is the number of hits within HotKeyWindow after which a
+	// cache key is reported to HotKeyHandler
+	// If 0, hot key detection is disabled
+	HotKeyThreshold int
+
+	// HotKeyWindow is the time window hot key hits are counted in
+	// Default is 1 minute
+	HotKeyWindow time.Duration
+
+	// HotKeyHandler is called once per window for every hot key, e.g. to
+	// replicate the key or promote it to a local MemoryAdapter
+	HotKeyHandler func(key string, hitCount int64)
+
+	// ReadOnly wraps Adapter in a ReadOnlyAdapter, so cached data is served but
+	// never written, invalidated or cleared
+	ReadOnly bool
+
+	// DBQueryHook post-processes a database result before it is cached, e.g. to
+	// decrypt fields; changes made to dest are both returned and cached
+	// If it returns an error, the result is not cached
+	DBQueryHook func(ctx context.Context, db *gorm.DB, dest interface{}) error
+
+	// SkipCacheForIsolationLevel skips cache for queries inside transactions
+	// running at or above this isolation level (e.g. sql.LevelRepeatableRead),
+	// whose results may not be visible to other transactions
+	// The level is read from the context, see BeginTx and WithIsolationLevel
+	// If sql.LevelDefault, the isolation level is ignored
+	// It only matters when SkipCacheInTransaction is false
+	SkipCacheForIsolationLevel sql.IsolationLevel
+
+	// SkipCacheInTransaction bypasses the cache for every query inside a
+	// transaction, so transactions neither read possibly stale entries nor cache
+	// uncommitted data
+	// DefaultConfig sets it to true
+	SkipCacheInTransaction bool
+
+	// SlidingExpiration resets the TTL of a cached result on every cache hit,
+	// so results only expire after going unread for their TTL
+	SlidingExpiration bool
+
+	// StaleWhileRevalidate starts a background refresh of a cached result when it
+	// is served within StaleWhileRevalidate of its expiry; the caller gets the
+	// cached result immediately and the refresh renews the entry before it expires
+	// The expiry is kept in a metadata sidecar key (cache key + ":meta")
+	// If 0, entries are only reloaded once they have expired
+	StaleWhileRevalidate time.Duration
+
+	// RefreshThreshold queues a background refresh of a cached result when it
+	// is served with less than this fraction of its TTL remaining, e.g. 0.2
+	// refreshes entries in the last 20% of their lifetime; unlike
+	// StaleWhileRevalidate the threshold scales with each entry's TTL
+	// The expiry is kept in a metadata sidecar key (cache key + ":meta")
+	// If 0, entries are not refreshed ahead of their expiry
+	RefreshThreshold float64
+
+	// RefreshPoolSize is the number of goroutines running the refreshes of
+	// RefreshThreshold; refreshes are skipped while all of them are busy
+	// If 0, defaults to 10
+	RefreshPoolSize int
+
+	// NamespaceExtractor returns the namespace (e.g. a tenant ID) of a query
+	// context, inserted after KeyPrefix in its cache keys and invalidation
+	// patterns so that namespaces never share cached results, and writes in
+	// one namespace do not invalidate the others
+	// A namespace set with WithCacheNamespace takes precedence; if both are
+	// empty, keys have no namespace
+	NamespaceExtractor func(context.Context) string
+
+	// CacheRawQueries caches db.Raw(...).Find(...) queries, keyed by their SQL
+	// and vars; they are invalidated with the table of the destination model,
+	// or kept for their TTL when the destination has no model (e.g. maps)
+	// Raw queries scoped with CacheFor are always cached
+	// db.Raw(...).Scan(...) runs through the row callbacks and is never cached
+	CacheRawQueries bool
+
+	// RequestCacheEnabled serves repeated queries of a request from a map
+	// attached to its context by WithRequestCache, before the adapter is used
+	// Results are kept in the map for the lifetime of the context, and writes
+	// made with the context remove the entries they invalidate
+	RequestCacheEnabled bool
+
+	// CacheCountQueries caches the results of db.Count() queries, under keys
+	// with a "count:" segment after the table name (e.g. "gorm:cache:users:count:abc")
+	// Counts of GROUP BY queries are never cached
+	// The zero value leaves counts uncached; DefaultConfig sets it to true
+	CacheCountQueries bool
+
+	// CachePluckQueries caches the results of db.Pluck() queries, i.e. queries
+	// reading into a slice of scalars, under keys with a "pluck:" segment after
+	// the table name
+	// The zero value leaves plucks uncached; DefaultConfig sets it to true
+	CachePluckQueries bool
+
+	// MaxCacheableRows is the maximum number of rows of a cached result; larger
+	// results are returned from the database without being cached
+	// If 0, results of any size are cached
+	MaxCacheableRows int
+
+	// CachePredicateFunc decides from the completed query whether its result is
+	// cached, e.g. only results with between 1 and 10 rows; returning false
+	// returns the result from the database without caching it
+	// It receives the statement after the query, including RowsAffected
+	CachePredicateFunc func(db *gorm.DB, rowsAffected int64) bool
+
+	// CacheKey_ScopeFunc returns a scope (e.g. "tenant:42") appended to every
+	// cache key and invalidation pattern, partitioning the cache by user or tenant
+	// without replacing the default key generation
+	// If it returns an empty string, no scope is appended
+	CacheKey_ScopeFunc func(*gorm.DB) string
+
+	// AtomicInvalidation deletes the keys of an invalidated model in a single
+	// MULTI/EXEC transaction when the adapter supports it (RedisAdapter), so an
+	// interrupted invalidation never leaves part of the keys stale
+	AtomicInvalidation bool
+
+	// WildcardPattern is the wildcard used in the invalidation patterns passed
+	// to Adapter.DeletePattern
+	// Default is "*"
+	WildcardPattern string
+
+	// SoftInvalidation marks cached entries as stale on writes instead of
+	// deleting them; stale entries are still served, and refreshed from the
+	// database in the background
+	// Requires an adapter able to list keys (MemoryAdapter, RedisAdapter),
+	// other adapters fall back to deleting the entries
+	SoftInvalidation bool
+
+	// NormalizePlaceholders rewrites numbered placeholders ($1, $2, ...) to ? and
+	// collapses whitespace in the SQL before hashing, so the same query gets the
+	// same cache key whether or not prepared statements are used
+	NormalizePlaceholders bool
+
+	// NormalizeSQL collapses whitespace and lowercases SQL keywords before
+	// hashing, so SQL written with different formatting (e.g. by hand in
+	// db.Raw) gets the same cache key; quoted strings and identifiers are kept
+	// ORDER BY columns keep their order, as it changes the order of the rows
+	NormalizeSQL bool
+
+	// CacheTags assigns tags to every cached query of a model, so the queries
+	// can be invalidated together with CachePlugin.InvalidateTags
+	// Example: map[interface{}][]string{User{}: {"user-data"}}
+	CacheTags map[interface{}][]string
+
+	// IncludeOrderByInCacheKey adds a canonical form of the ORDER BY clause
+	// (lower case columns with an explicit direction) to the hashed cache key
+	IncludeOrderByInCacheKey bool
+
+	// CacheExpiryFunc returns an absolute expiry time for a query result, used
+	// instead of TTL (e.g. MidnightExpiry, EndOfHourExpiry)
+	// Results whose expiry time has already passed are not cached
+	CacheExpiryFunc func(db *gorm.DB) time.Time
+
+	// MaxQueryCacheAge is the maximum age of a served cached result, regardless
+	// of the TTL it was stored with; older entries are treated as misses
+	// The write time is kept in a metadata sidecar key (cache key + ":meta")
+	// If 0, entries are served until their TTL expires
+	MaxQueryCacheAge time.Duration
+
+	// CacheVersion is inserted between KeyPrefix and the table name of every
+	// cache key (e.g. "gorm:cache:v2:users:abc")
+	// Bumping it makes all existing entries unreachable; they expire via their TTL
+	CacheVersion string
+
+	// InstrumentGORMLogger wraps the GORM logger so that traced queries are
+	// prefixed with [CACHE HIT] or [CACHE MISS]
+	InstrumentGORMLogger bool
+
+	// TraceQueries stores the stack trace of the query that populated each cache
+	// entry under the entry key + ":trace", see CachePlugin.GetTrace
+	// Capturing stacks is costly, enable it only while debugging
+	TraceQueries bool
+
+	// FailFastOnAdapterError makes Initialize fail if the adapter backend is
+	// unreachable, instead of starting with a cache that errors on every query
+	// Adapters are checked with Ping if they implement PingableAdapter
+	FailFastOnAdapterError bool
+
+	// AdapterInitTimeout bounds the adapter check of FailFastOnAdapterError and the
+	// subscription of BroadcastInvalidation
+	// If 0, defaults to 5 seconds
+	AdapterInitTimeout time.Duration
+
+	// SingleflightEnabled collapses concurrent cache misses of the same key into
+	// one database query whose result is shared by all waiting queries
+	SingleflightEnabled bool
+
+	// SingleflightTimeout bounds how long a cache miss waits for the query of
+	// another statement with SingleflightEnabled before querying the database
+	// itself, in case that query never completes (e.g. it panicked)
+	// If 0, defaults to 10 seconds
+	SingleflightTimeout time.Duration
+
+	// Compression compresses serialized query results before they are stored
+	// Entries stored without compression are treated as misses once it is enabled
+	Compression CompressionType
+
+	// CompressionLevel is the compression level on the gzip scale, from
+	// gzip.BestSpeed (1) to gzip.BestCompression (9); 0 uses the default level
+	// Zstandard levels are mapped to the closest encoder level
+	CompressionLevel int
+
+	// VersionKey is the adapter key holding the cache version incremented by
+	// CachePlugin.BumpVersion, which is embedded in every cache key
+	// Keep it outside KeyPrefix so invalidation patterns never delete it; a
+	// bounded MemoryAdapter never evicts it. If empty, BumpVersion is disabled
+	// DefaultConfig sets it to "gorm:cache-version"
+	VersionKey string
+
+	// VersionRefreshInterval is how long the value of VersionKey is cached
+	// locally before being read from the adapter again, i.e. how long other
+	// processes may take to see a bump (default 1s)
+	VersionRefreshInterval time.Duration
+
+	// NegativeTTL is the expiration of cached empty results
+	// A query returning no rows is cached as a negative entry for NegativeTTL
+	// and served as an empty result until it expires; if zero, empty results
+	// are not cached
+	NegativeTTL time.Duration
+
+	// TTLJitter adds a random duration in [0, TTLJitter) to the TTL of every
+	// cached result, so entries cached at the same time do not all expire at once
+	// If 0, entries are cached with their exact TTL
+	TTLJitter time.Duration
+
+	// ModelTTLs overrides TTL for the results of individual tables
+	// Keys are table names, e.g. "users"; tables not listed use TTL
+	ModelTTLs map[string]time.Duration
+
+	// OnHit, OnMiss, OnStore and OnInvalidate are called synchronously by the
+	// plugin's GORM callbacks when a query is served from the cache, looked up
+	// without success, has its result stored, and when a write invalidates the
+	// cached queries matching pattern
+	// OnStore is also called by SoftInvalidation background refreshes
+	OnHit        func(key string)
+	OnMiss       func(key string)
+	OnStore      func(key string, ttl time.Duration)
+	OnInvalidate func(pattern string)
+
+	// BeforeGet is called before a query result is read from the cache; if it
+	// returns true, the read is skipped and the query goes to the database,
+	// e.g. to bypass the cache for some keys
+	// AfterGet is called after the adapter read with whether it was a hit
+	BeforeGet func(ctx context.Context, key string) (skip bool)
+	AfterGet  func(ctx context.Context, key string, hit bool)
+
+	// BeforeSet is called before a query result is stored in the cache; if it
+	// returns true, the result is not stored
+	// AfterSet is called after the adapter write with its error, if any
+	// Both are called for every query result loaded from the database or a
+	// read-through loader, but not when SlidingExpiration stores a hit again
+	BeforeSet func(ctx context.Context, key string) (skip bool)
+	AfterSet  func(ctx context.Context, key string, err error)
+
+	// FailOnCacheErrors fails a query whose Adapter.Get fails, instead of
+	// running it against the database
+	// Missing keys reported with ErrCacheMiss are never a failure
+	FailOnCacheErrors bool
+
+	// OnError is called with every adapter error of a read, write or
+	// invalidation, whether or not it is ignored
+	OnError func(err error)
+
+	// RetryCount is the number of times a failed adapter Get or Set of a query
+	// result is retried; cache misses are never retried
+	// Retries give up early when the statement context is done or its deadline
+	// would pass before the next attempt
+	RetryCount int
+
+	// RetryBackoff returns the delay before retry attempt (starting at 1)
+	// If nil, the delay grows linearly by 100ms per attempt
+	RetryBackoff func(attempt int) time.Duration
+
+	// ModelInvalidationPatterns overrides the pattern invalidated by writes to
+	// individual tables; keys are table names and values are text/template
+	// patterns rendered with the *gorm.DB of the write, prefixed with KeyPrefix
+	// and the namespace, see NamespaceExtractor
+	// Example: {"users": `tenant_{{.Statement.Context.Value "tenant_id"}}:users:*`}
+	// Templates failing to parse or render fall back to the default pattern
+	ModelInvalidationPatterns map[string]string
+
+	// BroadcastInvalidation publishes every invalidation on PubSubChannel,
+	// including InvalidateTable, InvalidateTags and InvalidateAll, and applies
+	// those published by other instances like local ones, so
+	// instances keeping local entries (e.g. the L1 of a TwoLevelAdapter) do not
+	// serve stale results after writes made elsewhere
+	// The adapter must be backed by Redis; Initialize subscribes to the channel
+	// and Close unsubscribes
+	BroadcastInvalidation bool
+
+	// PubSubChannel is the Redis channel of BroadcastInvalidation
+	// If empty, defaults to "gorm:cache:invalidations"
+	PubSubChannel string
+
+	// EnableInvalidationLog keeps the most recent invalidations of writes and
+	// InvalidateTable in memory, for CachePlugin.InvalidationHistory and
+	// CachePlugin.ReplayInvalidations
+	EnableInvalidationLog bool
+
+	// InvalidationLogSize is the number of invalidations kept by
+	// EnableInvalidationLog; older ones are dropped
+	// If 0, defaults to 1000
+	InvalidationLogSize int
+
+	// hashKey is the key hash function selected by New
+	hashKey func([]byte) string
+
+	// codec is the value compression selected by New, nil if disabled
+	codec codec
+
+	// invalidationTemplates are the parsed ModelInvalidationPatterns
+	invalidationTemplates map[string]*template.Template
 }
 
 // DefaultConfig returns a default configuration
 func DefaultConfig() Config {
 	return Config{
-		Adapter:            NewMemoryAdapter(),
-		TTL:                5 * time.Minute,
-		CacheModels:        []interface{}{},
-		InvalidateOnUpdate: true,
-		InvalidateOnCreate: true,
-		InvalidateOnDelete: true,
-		KeyPrefix:          "gorm:cache:",
-		SkipCacheCondition: nil,
-		CacheKeyGenerator:  nil,
-		Serializer:         &JSONSerializer{}, // 默认使用 JSON
+		Adapter:                NewMemoryAdapter(),
+		TTL:                    5 * time.Minute,
+		CacheModels:            []interface{}{},
+		InvalidateOnUpdate:     true,
+		InvalidateOnCreate:     true,
+		InvalidateOnDelete:     true,
+		KeyPrefix:              "gorm:cache:",
+		SkipCacheCondition:     nil,
+		CacheKeyGenerator:      nil,
+		Serializer:             &JSONSerializer{}, // 默认使用 JSON
+		SkipForLockingClauses:  true,
+		MaxConcurrentWarmups:   defaultMaxConcurrentWarmups,
+		WildcardPattern:        defaultWildcardPattern,
+		SingleflightEnabled:    true,
+		VersionKey:             defaultVersionKey,
+		SkipCacheInTransaction: true,
+		CacheCountQueries:      true,
+		CachePluckQueries:      true,
 	}
 }
 
@@ -77,6 +450,15 @@ func (c *Config) shouldCacheModel(db *gorm.DB) bool {
 
 	modelType := db.Statement.Schema.ModelType
 	for _, cacheModel := range c.CacheModels {
+		if t, ok := cacheModel.(reflect.Type); ok {
+			if t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+			if t == modelType {
+				return true
+			}
+			continue
+		}
 		if fmt.Sprintf("%T", cacheModel) == modelType.String() {
 			return true
 		}
@@ -87,19 +469,40 @@ func (c *Config) shouldCacheModel(db *gorm.DB) bool {
 
 // shouldSkipCache checks if cache should be skipped
 func (c *Config) shouldSkipCache(db *gorm.DB) bool {
+	if c.SkipCacheInTransaction && inTransaction(db) {
+		return true
+	}
+
+	// ForceCache 覆盖用户配置的跳过条件，但不覆盖下面与正确性相关的检查
+	forced := isForced(db)
+
 	// Check context first
-	if skip, ok := getSkipCacheFromContext(db.Statement.Context); ok && skip {
+	if skip, ok := getSkipCacheFromContext(db.Statement.Context); ok && skip && !forced {
+		return true
+	}
+
+	// Locking reads must never be served from cache
+	if c.SkipForLockingClauses && hasLockingClause(db) {
+		return true
+	}
+
+	// Transactions with a high isolation level may see data other transactions cannot
+	if c.SkipCacheForIsolationLevel != sql.LevelDefault && inIsolatedTransaction(db, c.SkipCacheForIsolationLevel) {
+		return true
+	}
+
+	if c.skipCount(db) || c.skipPluck(db) {
 		return true
 	}
 
 	// Check custom skip condition
-	if c.SkipCacheCondition != nil && c.SkipCacheCondition(db) {
+	if c.SkipCacheCondition != nil && !forced && c.SkipCacheCondition(db) {
 		return true
 	}
 
 	// Check if explicitly disabled in the statement
 	if v, ok := db.Statement.Settings.Load("gorm:cache:skip"); ok {
-		if skip, ok := v.(bool); ok && skip {
+		if skip, ok := v.(bool); ok && skip && !forced {
 			return true
 		}
 	}
@@ -107,37 +510,240 @@ func (c *Config) shouldSkipCache(db *gorm.DB) bool {
 	return false
 }
 
+// isForced reports whether the statement was marked with ForceCache
+func isForced(db *gorm.DB) bool {
+	v, ok := db.Statement.Settings.Load("gorm:cache:force")
+	forced, _ := v.(bool)
+	return ok && forced
+}
+
+// hasLockingClause reports whether the statement carries a clause that changes
+// the query semantics in a way that makes a cached result unsafe to serve
+func hasLockingClause(db *gorm.DB) bool {
+	for _, c := range db.Statement.Clauses {
+		switch c.Expression.(type) {
+		case clause.Locking, clause.OnConflict:
+			return true
+		}
+	}
+	return false
+}
+
+// inTransaction reports whether the statement runs inside a transaction
+func inTransaction(db *gorm.DB) bool {
+	_, ok := db.Statement.ConnPool.(gorm.TxCommitter)
+	return ok
+}
+
+// inIsolatedTransaction reports whether the statement runs inside a transaction
+// whose isolation level is at or above level
+func inIsolatedTransaction(db *gorm.DB, level sql.IsolationLevel) bool {
+	if !inTransaction(db) {
+		return false
+	}
+	current, ok := getIsolationLevelFromContext(db.Statement.Context)
+	return ok && current >= level
+}
+
 // generateCacheKey generates a cache key for the query
-func (c *Config) generateCacheKey(db *gorm.DB) string {
+// version, if not empty, is placed between the table name and the query hash
+func (c *Config) generateCacheKey(db *gorm.DB, version string) string {
 	// Use custom generator if provided
 	if c.CacheKeyGenerator != nil {
-		return c.KeyPrefix + c.CacheKeyGenerator(db)
+		return c.keyPrefix() + c.keyNamespace(db.Statement.Context) + c.CacheKeyGenerator(db) + c.keyScope(db)
 	}
 
 	// Default key generation
+	query := db.Statement.SQL.String()
+	if c.NormalizePlaceholders {
+		query = normalizeSQL(query)
+	}
+	if c.NormalizeSQL {
+		query = canonicalSQL(query)
+	}
+
 	key := struct {
-		SQL  string
-		Vars []interface{}
+		SQL     string
+		Vars    []interface{}
+		OrderBy string `json:",omitempty"`
 	}{
-		SQL:  db.Statement.SQL.String(),
+		SQL:  query,
 		Vars: db.Statement.Vars,
 	}
+	// 复制的语句（如 Session）带有空而非 nil 的 Vars，两者应得到相同的 key
+	if len(key.Vars) == 0 {
+		key.Vars = nil
+	}
+	if c.IncludeOrderByInCacheKey {
+		key.OrderBy = orderByKey(db)
+	}
 
 	jsonBytes, _ := json.Marshal(key)
-	hash := md5.Sum(jsonBytes)
 
-	tableName := "unknown"
-	if db.Statement.Schema != nil {
-		tableName = db.Statement.Schema.Table
+	hashKey := c.hashKey
+	if hashKey == nil {
+		hashKey = c.newKeyHasher()
+	}
+
+	tableName := statementTable(db)
+	if tableName == "" {
+		tableName = "unknown"
+	}
+
+	if version != "" {
+		tableName += ":" + version
+	}
+	if isCountQuery(db) {
+		tableName += ":" + countKeySegment
+	} else if isPluckQuery(db) {
+		tableName += ":" + pluckKeySegment
+	}
+
+	return c.keyPrefix() + c.keyNamespace(db.Statement.Context) + tableName + ":" + hashKey(jsonBytes) + c.keyScope(db)
+}
+
+var (
+	numberedPlaceholder = regexp.MustCompile(`\$\d+`)
+	whitespaceRun       = regexp.MustCompile(`\s+`)
+)
+
+// normalizeSQL replaces numbered placeholders with ? and collapses whitespace
+func normalizeSQL(query string) string {
+	query = numberedPlaceholder.ReplaceAllString(query, "?")
+	return strings.TrimSpace(whitespaceRun.ReplaceAllString(query, " "))
+}
+
+// orderByKey returns the ORDER BY clause of the statement in canonical form,
+// e.g. "name asc,id desc"
+// Columns keep their order, since ORDER BY a, b and ORDER BY b, a sort rows differently
+func orderByKey(db *gorm.DB) string {
+	c, ok := db.Statement.Clauses["ORDER BY"]
+	if !ok {
+		return ""
+	}
+	orderBy, ok := c.Expression.(clause.OrderBy)
+	if !ok {
+		return ""
+	}
+
+	columns := make([]string, 0, len(orderBy.Columns))
+	for _, column := range orderBy.Columns {
+		fields := strings.Fields(strings.ToLower(column.Column.Name))
+		desc := column.Desc
+
+		// Raw columns such as Order("name DESC") carry their direction in the name
+		if n := len(fields); n > 1 && (fields[n-1] == "asc" || fields[n-1] == "desc") {
+			desc = fields[n-1] == "desc"
+			fields = fields[:n-1]
+		}
+
+		name := strings.Join(fields, " ")
+		if column.Column.Table != "" {
+			name = strings.ToLower(column.Column.Table) + "." + name
+		}
+		if desc {
+			columns = append(columns, name+" desc")
+		} else {
+			columns = append(columns, name+" asc")
+		}
 	}
 
-	return c.KeyPrefix + tableName + ":" + hex.EncodeToString(hash[:])
+	if orderBy.Expression != nil {
+		columns = append(columns, fmt.Sprintf("%v", orderBy.Expression))
+	}
+
+	return strings.Join(columns, ",")
 }
 
 // getModelPattern returns the cache key pattern for a model
 func (c *Config) getModelPattern(db *gorm.DB) string {
-	if db.Statement.Schema == nil {
-		return c.KeyPrefix + "*"
+	wildcard := c.WildcardPattern
+	if wildcard == "" {
+		wildcard = defaultWildcardPattern
+	}
+
+	prefix := c.keyPrefix() + c.keyNamespace(db.Statement.Context)
+	tableName := statementTable(db)
+	if tableName == "" {
+		return prefix + wildcard + c.keyScope(db)
+	}
+	if pattern, ok := c.renderInvalidationPattern(db, tableName); ok {
+		return pattern
+	}
+	return prefix + tableName + ":" + wildcard + c.keyScope(db)
+}
+
+// tablePattern returns the pattern matching all cached queries of tableName
+// in the namespace of ctx
+func (c *Config) tablePattern(ctx context.Context, tableName string) string {
+	wildcard := c.WildcardPattern
+	if wildcard == "" {
+		wildcard = defaultWildcardPattern
+	}
+	return c.keyPrefix() + c.keyNamespace(ctx) + tableName + ":" + wildcard
+}
+
+// newKeyHasher returns the query hash function of KeyHasher or
+// KeyHashAlgorithm and KeyEncoder, truncated to KeyHashLength
+func (c *Config) newKeyHasher() func([]byte) string {
+	if c.KeyHasher != nil {
+		return truncateHasher(c.KeyHasher, c.KeyHashLength)
+	}
+	return newKeyHasher(c.KeyHashAlgorithm, c.KeyEncoder, c.KeyHashLength)
+}
+
+// keyPrefix returns KeyPrefix followed by the CacheVersion segment, if any
+func (c *Config) keyPrefix() string {
+	if c.CacheVersion == "" {
+		return c.KeyPrefix
+	}
+	return c.KeyPrefix + c.CacheVersion + ":"
+}
+
+// keyNamespace returns the ":"-terminated cache namespace of ctx, or ""
+func (c *Config) keyNamespace(ctx context.Context) string {
+	ns, ok := getNamespaceFromContext(ctx)
+	if !ok && c.NamespaceExtractor != nil && ctx != nil {
+		ns = c.NamespaceExtractor(ctx)
+	}
+	if ns == "" {
+		return ""
+	}
+	return ns + ":"
+}
+
+// keyScope returns the ":"-prefixed scope suffix from CacheKey_ScopeFunc, or ""
+func (c *Config) keyScope(db *gorm.DB) string {
+	if c.CacheKey_ScopeFunc == nil {
+		return ""
+	}
+	if scope := c.CacheKey_ScopeFunc(db); scope != "" {
+		return ":" + scope
+	}
+	return ""
+}
+
+// shouldCacheRaw reports whether the raw query of db may be cached
+func (c *Config) shouldCacheRaw(db *gorm.DB) bool {
+	if c.CacheRawQueries {
+		return true
+	}
+	_, ok := db.Statement.Settings.Load("gorm:cache:model_override")
+	return ok
+}
+
+// statementTable returns the table whose cache namespace the statement uses,
+// honoring the CacheFor override
+func statementTable(db *gorm.DB) string {
+	if v, ok := db.Statement.Settings.Load("gorm:cache:model_override"); ok {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(v); err == nil {
+			return stmt.Schema.Table
+		}
+	}
+
+	if db.Statement.Schema != nil {
+		return db.Statement.Schema.Table
 	}
-	return c.KeyPrefix + db.Statement.Schema.Table + ":*"
+	return ""
 }
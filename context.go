@@ -1,11 +1,19 @@
 package gormcache
 
-import "context"
+import (
+	"context"
+	"database/sql"
+)
 
 type contextKey string
 
 const (
-	contextKeySkipCache contextKey = "gorm:cache:skip"
+	contextKeySkipCache      contextKey = "gorm:cache:skip"
+	contextKeyIsolationLevel contextKey = "gorm:cache:isolation_level"
+	contextKeyCacheHit       contextKey = "gorm:cache:hit"
+	contextKeyNamespace      contextKey = "gorm:cache:namespace"
+	contextKeyRequestCache   contextKey = "gorm:cache:request_cache"
+	contextKeyPeek           contextKey = "gorm:cache:peek"
 )
 
 // SkipCacheContext returns a new context that will skip cache for queries
@@ -30,3 +38,34 @@ func getSkipCacheFromContext(ctx context.Context) (bool, bool) {
 	}
 	return false, false
 }
+
+// WithIsolationLevel records the isolation level of the transaction the context is used in
+// *sql.Tx does not expose its isolation level, so transactions started with
+// explicit options should carry it in their context (see BeginTx)
+func WithIsolationLevel(ctx context.Context, level sql.IsolationLevel) context.Context {
+	return context.WithValue(ctx, contextKeyIsolationLevel, level)
+}
+
+// getIsolationLevelFromContext returns the isolation level recorded in the context
+func getIsolationLevelFromContext(ctx context.Context) (sql.IsolationLevel, bool) {
+	if ctx == nil {
+		return sql.LevelDefault, false
+	}
+	level, ok := ctx.Value(contextKeyIsolationLevel).(sql.IsolationLevel)
+	return level, ok
+}
+
+// WithCacheNamespace returns a new context whose queries use the cache namespace ns,
+// taking precedence over Config.NamespaceExtractor
+func WithCacheNamespace(ctx context.Context, ns string) context.Context {
+	return context.WithValue(ctx, contextKeyNamespace, ns)
+}
+
+// getNamespaceFromContext returns the cache namespace set by WithCacheNamespace
+func getNamespaceFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	ns, ok := ctx.Value(contextKeyNamespace).(string)
+	return ns, ok
+}
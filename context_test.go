@@ -108,3 +108,62 @@ func TestWithSkipCacheFunction(t *testing.T) {
 		t.Errorf("expected name 'Test User', got '%s'", user1.Name)
 	}
 }
+
+// nilContextDB returns a session whose statements carry no context
+func nilContextDB(db *gorm.DB) *gorm.DB {
+	tx := db.Session(&gorm.Session{})
+	tx.Statement.Context = nil
+	return tx
+}
+
+// expectNilContextPanic fails the test if fn does not panic with errNilContext
+func expectNilContextPanic(t *testing.T, fn func()) {
+	t.Helper()
+	defer func() {
+		if r := recover(); r != errNilContext {
+			t.Errorf("expected panic %q, got %v", errNilContext, r)
+		}
+	}()
+	fn()
+}
+
+func TestPanicOnNilContext(t *testing.T) {
+	db := setupTestDB(t)
+
+	cachePlugin := New(Config{
+		Adapter:           NewMemoryAdapter(),
+		TTL:               5 * time.Minute,
+		PanicOnNilContext: true,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	user := TestUser{Name: "Test User"}
+	db.Create(&user)
+
+	// With a context the query works as usual
+	var user1 TestUser
+	if err := db.WithContext(context.Background()).First(&user1, user.ID).Error; err != nil {
+		t.Fatalf("failed to query: %v", err)
+	}
+
+	expectNilContextPanic(t, func() {
+		var user2 TestUser
+		nilContextDB(db).First(&user2, user.ID)
+	})
+}
+
+func TestRequireContext(t *testing.T) {
+	db := setupTestDB(t)
+
+	var users []TestUser
+	if err := db.Scopes(RequireContext()).Find(&users).Error; err != nil {
+		t.Fatalf("failed to query: %v", err)
+	}
+
+	expectNilContextPanic(t, func() {
+		nilContextDB(db).Scopes(RequireContext()).Find(&users)
+	})
+}
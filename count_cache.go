@@ -0,0 +1,41 @@
+package gormcache
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// countKeySegment is inserted after the table name in the cache keys of Count
+// queries, keeping them apart from row queries of the same table
+const countKeySegment = "count"
+
+// isCountQuery reports whether the statement is a db.Count() query
+func isCountQuery(db *gorm.DB) bool {
+	if _, ok := db.Statement.Dest.(*int64); !ok {
+		return false
+	}
+
+	c, ok := db.Statement.Clauses["SELECT"]
+	if !ok {
+		return false
+	}
+	// clause.Select 合并时直接保存其 Expression（Count 添加的 count(*)）
+	expr, ok := c.Expression.(clause.Expr)
+	return ok && strings.HasPrefix(strings.ToLower(strings.TrimSpace(expr.SQL)), "count(")
+}
+
+// skipCount reports whether the statement is a Count query that must not be
+// cached, because CacheCountQueries is off or it counts GROUP BY groups, whose
+// number GORM reads from RowsAffected
+func (c *Config) skipCount(db *gorm.DB) bool {
+	if !isCountQuery(db) {
+		return false
+	}
+	if !c.CacheCountQueries {
+		return true
+	}
+	_, grouped := db.Statement.Clauses["GROUP BY"]
+	return grouped
+}
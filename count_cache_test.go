@@ -0,0 +1,124 @@
+package gormcache
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCacheCountQueries(t *testing.T) {
+	db := setupTestDB(t)
+
+	adapter := NewMemoryAdapter()
+	cachePlugin := New(Config{
+		Adapter:            adapter,
+		TTL:                5 * time.Minute,
+		InvalidateOnCreate: true,
+		CacheCountQueries:  true,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	db.Create(&TestUser{Name: "John"})
+	db.Create(&TestUser{Name: "Jane"})
+
+	queries := countQueries(t, db)
+
+	var first, second int64
+	db.Model(&TestUser{}).Count(&first)
+	db.Model(&TestUser{}).Count(&second)
+	if first != 2 || second != 2 {
+		t.Errorf("expected counts of 2, got %d and %d", first, second)
+	}
+	if *queries != 1 {
+		t.Errorf("expected the second count to be served from cache, got %d database queries", *queries)
+	}
+
+	// Counts are kept apart from row queries of the same table
+	keys, _ := adapter.Scan(context.Background(), "gorm:cache:test_users:count:*")
+	if len(keys) != 1 {
+		t.Errorf("expected 1 count key, got %v", keys)
+	}
+	var users []TestUser
+	db.Find(&users)
+	if len(users) != 2 {
+		t.Errorf("expected 2 users, got %d", len(users))
+	}
+
+	// Writes invalidate cached counts
+	db.Create(&TestUser{Name: "Bob"})
+	var third int64
+	db.Model(&TestUser{}).Count(&third)
+	if third != 3 {
+		t.Errorf("expected a count of 3 after the create, got %d", third)
+	}
+}
+
+func TestCacheCountQueriesDisabled(t *testing.T) {
+	db := setupTestDB(t)
+
+	adapter := NewMemoryAdapter()
+	cachePlugin := New(Config{
+		Adapter: adapter,
+		TTL:     5 * time.Minute,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	db.Create(&TestUser{Name: "John"})
+
+	queries := countQueries(t, db)
+	var n int64
+	db.Model(&TestUser{}).Count(&n)
+	db.Model(&TestUser{}).Count(&n)
+	if *queries != 2 {
+		t.Errorf("expected counts to reach the database, got %d database queries", *queries)
+	}
+
+	// Row queries are still cached
+	var users []TestUser
+	db.Find(&users)
+	db.Find(&users)
+	if *queries != 3 {
+		t.Errorf("expected row queries to be cached, got %d database queries", *queries)
+	}
+}
+
+func TestCacheCountGroupBy(t *testing.T) {
+	db := setupTestDB(t)
+
+	adapter := NewMemoryAdapter()
+	cachePlugin := New(Config{
+		Adapter:            adapter,
+		TTL:                5 * time.Minute,
+		InvalidateOnCreate: true,
+		CacheCountQueries:  true,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	db.Create(&TestUser{Name: "John"})
+	db.Create(&TestUser{Name: "John"})
+	db.Create(&TestUser{Name: "Jane"})
+
+	var first, second int64
+	db.Model(&TestUser{}).Group("name").Count(&first)
+	db.Model(&TestUser{}).Group("name").Count(&second)
+	if first != 2 || second != 2 {
+		t.Errorf("expected 2 groups, got %d and %d", first, second)
+	}
+
+	keys, _ := adapter.Scan(context.Background(), "*")
+	for _, key := range keys {
+		if strings.Contains(key, ":count:") {
+			t.Errorf("expected GROUP BY counts not to be cached, got key %s", key)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package gormcache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func TestDBQueryHook(t *testing.T) {
+	db := setupTestDB(t)
+
+	adapter := NewMemoryAdapter()
+	cachePlugin := New(Config{
+		Adapter: adapter,
+		TTL:     5 * time.Minute,
+		DBQueryHook: func(ctx context.Context, db *gorm.DB, dest interface{}) error {
+			if user, ok := dest.(*TestUser); ok {
+				user.Name = strings.ToUpper(user.Name)
+			}
+			return nil
+		},
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	user := TestUser{Name: "john"}
+	db.Create(&user)
+
+	// The database result is post-processed before it is returned
+	var result1 TestUser
+	db.First(&result1, user.ID)
+	if result1.Name != "JOHN" {
+		t.Fatalf("expected 'JOHN', got '%s'", result1.Name)
+	}
+
+	// The post-processed result is cached
+	for key, item := range adapter.store {
+		if !strings.Contains(string(item.value), `"JOHN"`) {
+			t.Errorf("expected cached value of %q to be uppercased, got %s", key, item.value)
+		}
+	}
+
+	var result2 TestUser
+	db.First(&result2, user.ID)
+	if result2.Name != "JOHN" {
+		t.Errorf("expected cached 'JOHN', got '%s'", result2.Name)
+	}
+}
+
+func TestDBQueryHookErrorSkipsCache(t *testing.T) {
+	db := setupTestDB(t)
+
+	adapter := NewMemoryAdapter()
+	cachePlugin := New(Config{
+		Adapter: adapter,
+		TTL:     5 * time.Minute,
+		DBQueryHook: func(ctx context.Context, db *gorm.DB, dest interface{}) error {
+			return errors.New("decryption failed")
+		},
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	user := TestUser{Name: "john"}
+	db.Create(&user)
+
+	var result TestUser
+	if err := db.First(&result, user.ID).Error; err != nil {
+		t.Fatalf("expected query to succeed, got %v", err)
+	}
+
+	if len(adapter.store) != 0 {
+		t.Errorf("expected no cached entries, got %d", len(adapter.store))
+	}
+}
@@ -0,0 +1,57 @@
+package gormcache
+
+import (
+	"errors"
+	"reflect"
+
+	"gorm.io/gorm"
+)
+
+// SetDefault registers a factory producing the result of cached queries on
+// model that find no record, e.g. p.SetDefault(User{}, func() interface{} { return User{Name: "guest"} })
+// The default is returned instead of gorm.ErrRecordNotFound or an empty struct
+// and cached like a database result; only single-record destinations are affected
+func (p *CachePlugin) SetDefault(model interface{}, fn func() interface{}) *CachePlugin {
+	p.defaultsMu.Lock()
+	defer p.defaultsMu.Unlock()
+
+	if p.defaults == nil {
+		p.defaults = make(map[reflect.Type]func() interface{})
+	}
+	p.defaults[indirectType(reflect.TypeOf(model))] = fn
+	return p
+}
+
+// applyDefault sets the registered default as the result of a query that
+// found no record; it returns false if no default applies
+func (p *CachePlugin) applyDefault(db *gorm.DB) bool {
+	if db.RowsAffected != 0 || db.Statement.Dest == nil {
+		return false
+	}
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		return false
+	}
+
+	p.defaultsMu.RLock()
+	fn, ok := p.defaults[indirectType(reflect.TypeOf(db.Statement.Dest))]
+	p.defaultsMu.RUnlock()
+	if !ok {
+		return false
+	}
+
+	if err := assignResult(db.Statement.Dest, fn()); err != nil {
+		return false
+	}
+
+	db.Error = nil
+	db.RowsAffected = 1
+	return true
+}
+
+// indirectType returns t with all pointer indirections removed
+func indirectType(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
@@ -0,0 +1,79 @@
+package gormcache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func TestSetDefault(t *testing.T) {
+	db := setupTestDB(t)
+
+	cachePlugin := New(Config{
+		Adapter: NewMemoryAdapter(),
+		TTL:     5 * time.Minute,
+	})
+	cachePlugin.SetDefault(TestUser{}, func() interface{} {
+		return TestUser{Name: "Guest"}
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	queries := countQueries(t, db)
+
+	var user TestUser
+	if err := db.First(&user, 404).Error; err != nil {
+		t.Fatalf("expected the default instead of an error, got %v", err)
+	}
+	if user.Name != "Guest" {
+		t.Errorf("expected the default user, got %+v", user)
+	}
+
+	// The default is cached like a database result
+	var cached TestUser
+	result := db.First(&cached, 404)
+	if result.Error != nil || cached.Name != "Guest" {
+		t.Errorf("expected the cached default, got %+v (error: %v)", cached, result.Error)
+	}
+	if *queries != 1 {
+		t.Errorf("expected the default to be served from cache, got %d database queries", *queries)
+	}
+	if result.RowsAffected != 1 {
+		t.Errorf("expected RowsAffected 1, got %d", result.RowsAffected)
+	}
+
+	// Lists are not affected
+	var users []TestUser
+	db.Find(&users)
+	if len(users) != 0 {
+		t.Errorf("expected an empty list, got %v", users)
+	}
+}
+
+func TestSetDefaultSkippedQueries(t *testing.T) {
+	db := setupTestDB(t)
+
+	cachePlugin := New(Config{Adapter: NewMemoryAdapter(), TTL: 5 * time.Minute})
+	cachePlugin.SetDefault(&TestUser{}, func() interface{} {
+		return &TestUser{Name: "Guest"}
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	var user TestUser
+	err := db.Scopes(SkipCache()).First(&user, 404).Error
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected queries skipping the cache to return ErrRecordNotFound, got %v", err)
+	}
+
+	var other TestUser
+	if err := db.First(&other, 404).Error; err != nil || other.Name != "Guest" {
+		t.Errorf("expected a pointer default to be assigned, got %+v (error: %v)", other, err)
+	}
+}
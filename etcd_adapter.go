@@ -0,0 +1,133 @@
+package gormcache
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// etcdKeyPrefix is prepended to every key stored by EtcdAdapter, so Clear only
+// removes cached data and never the other data of the etcd cluster (e.g. the
+// state of a Kubernetes cluster)
+const etcdKeyPrefix = "/gorm-cache/"
+
+// etcdMaxTxnOps is the default limit of operations in one etcd transaction
+const etcdMaxTxnOps = 128
+
+// EtcdAdapter is a distributed cache implementation backed by etcd
+// Every Set with a TTL grants its own lease, so it suits small result sets
+// better than write-heavy workloads
+type EtcdAdapter struct {
+	client *clientv3.Client
+}
+
+// NewEtcdAdapter connects to the etcd cluster at endpoints
+func NewEtcdAdapter(endpoints []string, dialTimeout time.Duration) (*EtcdAdapter, error) {
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   endpoints,
+		DialTimeout: dialTimeout,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("gorm:cache: connect to etcd %v: %w", endpoints, err)
+	}
+	return &EtcdAdapter{client: client}, nil
+}
+
+// NewEtcdAdapterWithClient creates a new etcd adapter with existing client
+func NewEtcdAdapterWithClient(client *clientv3.Client) *EtcdAdapter {
+	return &EtcdAdapter{client: client}
+}
+
+// Get retrieves a value from etcd cache
+func (e *EtcdAdapter) Get(ctx context.Context, key string) ([]byte, error) {
+	resp, err := e.client.Get(ctx, etcdKeyPrefix+key)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, fmt.Errorf("%w: key not found", ErrCacheMiss)
+	}
+	return resp.Kvs[0].Value, nil
+}
+
+// Set stores a value in etcd cache, attached to a lease expiring after ttl
+func (e *EtcdAdapter) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	var opts []clientv3.OpOption
+	if ttl > 0 {
+		// lease 的 TTL 以秒为单位，向上取整以免提前过期
+		seconds := int64((ttl + time.Second - 1) / time.Second)
+		lease, err := e.client.Grant(ctx, seconds)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, clientv3.WithLease(lease.ID))
+	}
+
+	_, err := e.client.Put(ctx, etcdKeyPrefix+key, string(value), opts...)
+	return err
+}
+
+// Delete removes a value from etcd cache
+func (e *EtcdAdapter) Delete(ctx context.Context, key string) error {
+	_, err := e.client.Delete(ctx, etcdKeyPrefix+key)
+	return err
+}
+
+// DeletePattern removes all keys matching the pattern, in transactions of at
+// most 128 deletions
+func (e *EtcdAdapter) DeletePattern(ctx context.Context, pattern string) error {
+	keys, err := e.Scan(ctx, pattern)
+	if err != nil {
+		return err
+	}
+
+	for len(keys) > 0 {
+		n := min(len(keys), etcdMaxTxnOps)
+		ops := make([]clientv3.Op, n)
+		for i, key := range keys[:n] {
+			ops[i] = clientv3.OpDelete(etcdKeyPrefix + key)
+		}
+		if _, err := e.client.Txn(ctx).Then(ops...).Commit(); err != nil {
+			return err
+		}
+		keys = keys[n:]
+	}
+	return nil
+}
+
+// Scan returns the keys matching the pattern, listing the keys sharing the
+// literal prefix of the pattern
+func (e *EtcdAdapter) Scan(ctx context.Context, pattern string) ([]string, error) {
+	prefix := pattern
+	if i := strings.IndexAny(pattern, "*?["); i >= 0 {
+		prefix = pattern[:i]
+	}
+
+	resp, err := e.client.Get(ctx, etcdKeyPrefix+prefix, clientv3.WithPrefix(), clientv3.WithKeysOnly())
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for _, kv := range resp.Kvs {
+		key := strings.TrimPrefix(string(kv.Key), etcdKeyPrefix)
+		if matchPattern(pattern, key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+// Clear removes all cached data, i.e. every key under the adapter prefix
+func (e *EtcdAdapter) Clear(ctx context.Context) error {
+	_, err := e.client.Delete(ctx, etcdKeyPrefix, clientv3.WithPrefix())
+	return err
+}
+
+// Close closes the etcd client
+func (e *EtcdAdapter) Close() error {
+	return e.client.Close()
+}
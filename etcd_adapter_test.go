@@ -0,0 +1,95 @@
+package gormcache
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+var _ Adapter = (*EtcdAdapter)(nil)
+
+func newTestEtcdAdapter(t *testing.T) *EtcdAdapter {
+	t.Helper()
+	endpoints := os.Getenv("ETCD_ENDPOINTS")
+	if endpoints == "" {
+		t.Skip("ETCD_ENDPOINTS is not set")
+	}
+
+	adapter, err := NewEtcdAdapter(strings.Split(endpoints, ","), 5*time.Second)
+	if err != nil {
+		t.Fatalf("failed to connect to etcd: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = adapter.Clear(context.Background())
+		_ = adapter.Close()
+	})
+	return adapter
+}
+
+func TestEtcdAdapterSetGetDelete(t *testing.T) {
+	adapter := newTestEtcdAdapter(t)
+	ctx := context.Background()
+
+	if _, err := adapter.Get(ctx, "missing"); !errors.Is(err, ErrCacheMiss) {
+		t.Errorf("expected ErrCacheMiss, got %v", err)
+	}
+
+	if err := adapter.Set(ctx, "key1", []byte("value1"), time.Minute); err != nil {
+		t.Fatalf("failed to set: %v", err)
+	}
+	value, err := adapter.Get(ctx, "key1")
+	if err != nil || string(value) != "value1" {
+		t.Fatalf("expected value1, got %q (%v)", value, err)
+	}
+
+	if err := adapter.Delete(ctx, "key1"); err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+	if _, err := adapter.Get(ctx, "key1"); err == nil {
+		t.Error("expected key1 to be deleted")
+	}
+}
+
+func TestEtcdAdapterExpiration(t *testing.T) {
+	adapter := newTestEtcdAdapter(t)
+	ctx := context.Background()
+
+	// etcd lease 的 TTL 精确到秒
+	adapter.Set(ctx, "short", []byte("value"), time.Second)
+	time.Sleep(3 * time.Second)
+
+	if _, err := adapter.Get(ctx, "short"); !errors.Is(err, ErrCacheMiss) {
+		t.Errorf("expected the entry to expire, got %v", err)
+	}
+}
+
+func TestEtcdAdapterDeletePatternAndClear(t *testing.T) {
+	adapter := newTestEtcdAdapter(t)
+	ctx := context.Background()
+
+	for _, key := range []string{"gorm:cache:users:1", "gorm:cache:users:2", "gorm:cache:orders:1"} {
+		adapter.Set(ctx, key, []byte("value"), time.Minute)
+	}
+
+	if err := adapter.DeletePattern(ctx, "gorm:cache:users:*"); err != nil {
+		t.Fatalf("failed to delete pattern: %v", err)
+	}
+	for _, key := range []string{"gorm:cache:users:1", "gorm:cache:users:2"} {
+		if _, err := adapter.Get(ctx, key); err == nil {
+			t.Errorf("expected %s to be deleted", key)
+		}
+	}
+	if _, err := adapter.Get(ctx, "gorm:cache:orders:1"); err != nil {
+		t.Errorf("expected gorm:cache:orders:1 to be kept: %v", err)
+	}
+
+	if err := adapter.Clear(ctx); err != nil {
+		t.Fatalf("failed to clear: %v", err)
+	}
+	if _, err := adapter.Get(ctx, "gorm:cache:orders:1"); err == nil {
+		t.Error("expected Clear to remove all entries")
+	}
+}
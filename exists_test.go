@@ -0,0 +1,73 @@
+package gormcache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func testExistsAdapter(t *testing.T, adapter interface {
+	Adapter
+	ExistsAdapter
+}) {
+	ctx := context.Background()
+
+	if exists, err := adapter.Exists(ctx, "key"); err != nil || exists {
+		t.Fatalf("expected missing key, got exists=%v err=%v", exists, err)
+	}
+
+	adapter.Set(ctx, "key", []byte("value"), time.Minute)
+	if exists, err := adapter.Exists(ctx, "key"); err != nil || !exists {
+		t.Fatalf("expected cached key, got exists=%v err=%v", exists, err)
+	}
+
+	adapter.Delete(ctx, "key")
+	if exists, err := adapter.Exists(ctx, "key"); err != nil || exists {
+		t.Fatalf("expected deleted key, got exists=%v err=%v", exists, err)
+	}
+}
+
+func TestExistsAdapter(t *testing.T) {
+	t.Run("memory", func(t *testing.T) {
+		adapter := NewMemoryAdapter()
+		defer adapter.Close()
+		testExistsAdapter(t, adapter)
+	})
+
+	t.Run("memory sync.Map", func(t *testing.T) {
+		adapter := NewMemoryAdapterWithSyncMap()
+		defer adapter.Close()
+		testExistsAdapter(t, adapter)
+	})
+
+	t.Run("redis", func(t *testing.T) {
+		mr := miniredis.RunT(t)
+		adapter := NewRedisAdapter(RedisAdapterConfig{Addr: mr.Addr()})
+		defer adapter.Close()
+		testExistsAdapter(t, adapter)
+	})
+
+	t.Run("badger", func(t *testing.T) {
+		adapter, err := NewBadgerAdapter(":memory:")
+		if err != nil {
+			t.Fatalf("NewBadgerAdapter failed: %v", err)
+		}
+		defer adapter.Close()
+		testExistsAdapter(t, adapter)
+	})
+}
+
+func TestMemoryAdapterExistsExpired(t *testing.T) {
+	adapter := NewMemoryAdapter()
+	defer adapter.Close()
+
+	ctx := context.Background()
+	adapter.Set(ctx, "short", []byte("value"), 50*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+
+	if exists, _ := adapter.Exists(ctx, "short"); exists {
+		t.Error("expected expired key to be reported missing")
+	}
+}
@@ -0,0 +1,99 @@
+package gormcache
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// cacheTTL returns the TTL for caching the statement's result, and false if the
+// result must not be cached because its absolute expiry has already passed
+// A WithTTL override takes precedence over Config.ModelTTLs, CacheExpiryFunc
+// and Config.TTL, in that order; TTLJitter is added to all of them
+func (p *CachePlugin) cacheTTL(db *gorm.DB) (time.Duration, bool) {
+	ttl, ok := p.baseTTL(db)
+	if !ok {
+		return 0, false
+	}
+	if p.config.TTLJitter > 0 {
+		ttl += time.Duration(rand.Int63n(int64(p.config.TTLJitter)))
+	}
+	return ttl, true
+}
+
+// baseTTL returns the TTL of the statement's result before TTLJitter, see cacheTTL
+func (p *CachePlugin) baseTTL(db *gorm.DB) (time.Duration, bool) {
+	if v, ok := db.Statement.Settings.Load("gorm:cache:ttl"); ok {
+		if ttl, ok := v.(time.Duration); ok && ttl > 0 {
+			return ttl, true
+		}
+	}
+
+	if ttl, ok := p.config.ModelTTLs[statementTable(db)]; ok && ttl > 0 {
+		return ttl, true
+	}
+
+	if p.config.CacheExpiryFunc == nil {
+		return p.config.TTL, true
+	}
+
+	ttl := time.Until(p.config.CacheExpiryFunc(db))
+	return ttl, ttl > 0
+}
+
+// slideExpiration stores cachedData again under cacheKey, restarting its TTL
+// The time the result was read from the database is kept, so MaxQueryCacheAge
+// still bounds the age of entries that keep being hit
+func (p *CachePlugin) slideExpiration(ctx context.Context, db *gorm.DB, cacheKey string, cachedData []byte) {
+	ttl, ok := p.cacheTTL(db)
+	if !ok {
+		return
+	}
+
+	createdAt := time.Now()
+	if p.needsMetadata() {
+		if meta, err := p.loadMetadata(ctx, cacheKey); err == nil {
+			createdAt = meta.createdAt()
+		}
+	}
+	if err := p.setCachedAt(ctx, cacheKey, cachedData, ttl, createdAt); err != nil {
+		p.stats.errors.Add(1)
+		p.onError(err)
+	}
+}
+
+// revalidateIfExpiring starts a background refresh of a cache entry expiring
+// within StaleWhileRevalidate, so it is renewed before callers have to wait
+// for the database
+func (p *CachePlugin) revalidateIfExpiring(ctx context.Context, db *gorm.DB, cacheKey string) {
+	meta, err := p.loadMetadata(ctx, cacheKey)
+	if err != nil || meta.TTL <= 0 {
+		return
+	}
+	if time.Until(meta.SetAt.Add(meta.TTL)) > p.config.StaleWhileRevalidate {
+		return
+	}
+
+	p.refreshInBackground(db, cacheKey, nil)
+}
+
+// MidnightExpiry returns a CacheExpiryFunc expiring entries at the next midnight in loc
+// If loc is nil, time.Local is used
+func MidnightExpiry(loc *time.Location) func(*gorm.DB) time.Time {
+	if loc == nil {
+		loc = time.Local
+	}
+	return func(*gorm.DB) time.Time {
+		now := time.Now().In(loc)
+		return time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, loc)
+	}
+}
+
+// EndOfHourExpiry returns a CacheExpiryFunc expiring entries at the start of the next hour
+func EndOfHourExpiry() func(*gorm.DB) time.Time {
+	return func(*gorm.DB) time.Time {
+		return time.Now().Truncate(time.Hour).Add(time.Hour)
+	}
+}
@@ -0,0 +1,84 @@
+package gormcache
+
+import (
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func TestCacheExpiryFunc(t *testing.T) {
+	db := setupTestDB(t)
+
+	loc, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+
+	adapter := NewMemoryAdapter()
+	cachePlugin := New(Config{
+		Adapter:         adapter,
+		TTL:             5 * time.Minute,
+		CacheExpiryFunc: MidnightExpiry(loc),
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	user := TestUser{Name: "Promo"}
+	db.Create(&user)
+
+	var result TestUser
+	db.First(&result, user.ID)
+
+	now := time.Now().In(loc)
+	midnight := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, loc)
+
+	if len(adapter.store) != 1 {
+		t.Fatalf("expected 1 cached entry, got %d", len(adapter.store))
+	}
+	for key, item := range adapter.store {
+		if diff := item.expiration.Sub(midnight); diff < -time.Second || diff > time.Second {
+			t.Errorf("expected %q to expire at %v, expires at %v", key, midnight, item.expiration)
+		}
+	}
+}
+
+func TestCacheExpiryFuncPast(t *testing.T) {
+	db := setupTestDB(t)
+
+	adapter := NewMemoryAdapter()
+	cachePlugin := New(Config{
+		Adapter: adapter,
+		TTL:     5 * time.Minute,
+		CacheExpiryFunc: func(*gorm.DB) time.Time {
+			return time.Now().Add(-time.Minute)
+		},
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	user := TestUser{Name: "Expired Promo"}
+	db.Create(&user)
+
+	var result TestUser
+	db.First(&result, user.ID)
+
+	if len(adapter.store) != 0 {
+		t.Errorf("expected nothing cached for a past expiry, got %d entries", len(adapter.store))
+	}
+}
+
+func TestEndOfHourExpiry(t *testing.T) {
+	expiry := EndOfHourExpiry()(nil)
+
+	if expiry.Minute() != 0 || expiry.Second() != 0 || expiry.Nanosecond() != 0 {
+		t.Errorf("expected expiry on the hour, got %v", expiry)
+	}
+	if ttl := time.Until(expiry); ttl <= 0 || ttl > time.Hour {
+		t.Errorf("expected expiry within the next hour, got %v", ttl)
+	}
+}
@@ -0,0 +1,89 @@
+package gormcache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// exportedEntry is one line of the newline-delimited JSON stream written by
+// Export; Value is base64 encoded by encoding/json
+type exportedEntry struct {
+	Key   string        `json:"key"`
+	Value []byte        `json:"value_base64"`
+	TTL   time.Duration `json:"ttl_remaining_ns"`
+}
+
+// Export writes every entry of the cache to w as newline-delimited JSON, for
+// Import to restore later
+// The adapter must implement ScannableAdapter; entries of adapters not
+// implementing TTLAdapter are exported with the configured TTL
+func (p *CachePlugin) Export(ctx context.Context, w io.Writer) error {
+	keys, err := scanKeys(ctx, p.adapter(), p.config.keyPrefix()+"*")
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	for _, key := range keys {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		value, err := p.adapter().Get(ctx, key)
+		if errors.Is(err, ErrCacheMiss) {
+			// 扫描之后过期或被删除的条目直接跳过
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("gorm:cache: export %q: %w", key, err)
+		}
+
+		ttl, err := p.remainingTTL(ctx, key)
+		if errors.Is(err, ErrCacheMiss) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("gorm:cache: export %q: %w", key, err)
+		}
+
+		if err := enc.Encode(exportedEntry{Key: key, Value: value, TTL: ttl}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Import stores the entries written by Export, restoring their remaining TTL
+func (p *CachePlugin) Import(ctx context.Context, r io.Reader) error {
+	dec := json.NewDecoder(r)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var entry exportedEntry
+		if err := dec.Decode(&entry); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return fmt.Errorf("gorm:cache: import: %w", err)
+		}
+
+		if err := p.adapter().Set(ctx, entry.Key, entry.Value, entry.TTL); err != nil {
+			return fmt.Errorf("gorm:cache: import %q: %w", entry.Key, err)
+		}
+	}
+}
+
+// remainingTTL returns the remaining lifetime of key, falling back to the
+// configured TTL when the adapter cannot report it
+func (p *CachePlugin) remainingTTL(ctx context.Context, key string) (time.Duration, error) {
+	if a, ok := p.adapter().(TTLAdapter); ok {
+		return a.TTL(ctx, key)
+	}
+	return p.config.TTL, nil
+}
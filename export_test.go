@@ -0,0 +1,54 @@
+package gormcache
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestExportImport(t *testing.T) {
+	ctx := context.Background()
+
+	source := NewMemoryAdapter()
+	defer source.Close()
+	plugin := New(Config{Adapter: source, TTL: time.Minute, KeyPrefix: "gorm:cache:"})
+
+	source.Set(ctx, "gorm:cache:users:1", []byte("alice"), time.Minute)
+	source.Set(ctx, "gorm:cache:users:2", []byte("bob"), time.Hour)
+	source.Set(ctx, "other:key", []byte("ignored"), time.Minute)
+
+	var buf bytes.Buffer
+	if err := plugin.Export(ctx, &buf); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	target := NewMemoryAdapter()
+	defer target.Close()
+	restored := New(Config{Adapter: target, TTL: time.Minute, KeyPrefix: "gorm:cache:"})
+	if err := restored.Import(ctx, &buf); err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+
+	for key, want := range map[string]string{"gorm:cache:users:1": "alice", "gorm:cache:users:2": "bob"} {
+		value, err := target.Get(ctx, key)
+		if err != nil {
+			t.Fatalf("Get %q failed: %v", key, err)
+		}
+		if string(value) != want {
+			t.Errorf("expected %q for %q, got %q", want, key, value)
+		}
+	}
+
+	if _, err := target.Get(ctx, "other:key"); err == nil {
+		t.Error("expected keys outside the prefix not to be exported")
+	}
+
+	ttl, err := target.TTL(ctx, "gorm:cache:users:2")
+	if err != nil {
+		t.Fatalf("TTL failed: %v", err)
+	}
+	if ttl <= time.Minute || ttl > time.Hour {
+		t.Errorf("expected the remaining TTL to be restored, got %v", ttl)
+	}
+}
@@ -0,0 +1,202 @@
+package gormcache
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+const (
+	// fingerprintKeyPrefix is the prefix of the keys holding deduplicated content;
+	// it lies outside the plugin key prefix so model invalidation leaves it alone
+	fingerprintKeyPrefix = "gorm:fingerprint:"
+
+	// fingerprintRefPrefix marks a value stored as a reference to a content key
+	fingerprintRefPrefix = "type:ref,key:"
+
+	// fingerprintPruneInterval is the minimum period between two scans for
+	// expired references
+	fingerprintPruneInterval = time.Minute
+)
+
+// FingerprintAdapter wraps an adapter and stores identical values only once
+// Values are stored under a key derived from their content hash, and cache keys
+// hold a small reference to it; the content is deleted once no key references it
+// Reference counts are kept in process memory: the adapter must be the only
+// writer of its keys, so it cannot be shared by several instances through a
+// distributed backend
+type FingerprintAdapter struct {
+	inner Adapter
+
+	mu          sync.Mutex
+	keys        map[string]fingerprintRef // cache key -> referenced content
+	refs        map[string]int            // content key -> number of referencing cache keys
+	expirations map[string]time.Time      // content key -> expiration, zero if it never expires
+	prunedAt    time.Time
+}
+
+// fingerprintRef is the content referenced by a cache key
+type fingerprintRef struct {
+	contentKey string
+	expiration time.Time // zero if it never expires
+}
+
+// NewFingerprintAdapter creates a new deduplicating adapter on top of inner
+func NewFingerprintAdapter(inner Adapter) *FingerprintAdapter {
+	return &FingerprintAdapter{
+		inner:       inner,
+		keys:        make(map[string]fingerprintRef),
+		refs:        make(map[string]int),
+		expirations: make(map[string]time.Time),
+		prunedAt:    time.Now(),
+	}
+}
+
+// Get retrieves a value from the inner adapter, following references
+func (a *FingerprintAdapter) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := a.inner.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if contentKey, ok := bytes.CutPrefix(value, []byte(fingerprintRefPrefix)); ok {
+		return a.inner.Get(ctx, string(contentKey))
+	}
+	return value, nil
+}
+
+// Set stores a reference to the value, storing the value itself only if no
+// other key holds the same content
+func (a *FingerprintAdapter) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	sum := sha256.Sum256(value)
+	contentKey := fingerprintKeyPrefix + hex.EncodeToString(sum[:])
+
+	now := time.Now()
+	var expiration time.Time
+	if ttl > 0 {
+		expiration = now.Add(ttl)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.pruneExpired(ctx, now)
+
+	// 内容已存在且不会比新 key 更早过期时，只需要写入引用
+	current, stored := a.expirations[contentKey]
+	if stored && !current.IsZero() && !current.After(now) {
+		stored = false
+	}
+	if !stored || (!current.IsZero() && (expiration.IsZero() || expiration.After(current))) {
+		if err := a.inner.Set(ctx, contentKey, value, ttl); err != nil {
+			return err
+		}
+		a.expirations[contentKey] = expiration
+	}
+
+	if err := a.inner.Set(ctx, key, []byte(fingerprintRefPrefix+contentKey), ttl); err != nil {
+		return err
+	}
+
+	previous, ok := a.keys[key]
+	a.keys[key] = fingerprintRef{contentKey: contentKey, expiration: expiration}
+	if ok {
+		if previous.contentKey == contentKey {
+			return nil
+		}
+		a.release(ctx, previous.contentKey)
+	}
+	a.refs[contentKey]++
+
+	return nil
+}
+
+// Delete removes a key, deleting its content once no other key references it
+func (a *FingerprintAdapter) Delete(ctx context.Context, key string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err := a.inner.Delete(ctx, key); err != nil {
+		return err
+	}
+
+	if ref, ok := a.keys[key]; ok {
+		delete(a.keys, key)
+		a.release(ctx, ref.contentKey)
+	}
+	return nil
+}
+
+// DeletePattern removes all keys matching the pattern, deleting content no
+// longer referenced
+func (a *FingerprintAdapter) DeletePattern(ctx context.Context, pattern string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err := a.inner.DeletePattern(ctx, pattern); err != nil {
+		return err
+	}
+
+	for key, ref := range a.keys {
+		if matchPattern(pattern, key) {
+			delete(a.keys, key)
+			a.release(ctx, ref.contentKey)
+		}
+	}
+	return nil
+}
+
+// Clear removes all cached data
+func (a *FingerprintAdapter) Clear(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.keys = make(map[string]fingerprintRef)
+	a.refs = make(map[string]int)
+	a.expirations = make(map[string]time.Time)
+
+	return a.inner.Clear(ctx)
+}
+
+// Close closes the inner adapter
+func (a *FingerprintAdapter) Close() error {
+	return a.inner.Close()
+}
+
+// Unwrap returns the inner adapter
+func (a *FingerprintAdapter) Unwrap() Adapter {
+	return a.inner
+}
+
+// release drops one reference to contentKey and deletes the content when it
+// was the last one; the caller must hold a.mu
+func (a *FingerprintAdapter) release(ctx context.Context, contentKey string) {
+	a.refs[contentKey]--
+	if a.refs[contentKey] > 0 {
+		return
+	}
+
+	delete(a.refs, contentKey)
+	delete(a.expirations, contentKey)
+	_ = a.inner.Delete(ctx, contentKey)
+}
+
+// pruneExpired drops the references of expired cache keys, at most once per
+// fingerprintPruneInterval, so keys that expire without being deleted do not
+// accumulate; the caller must hold a.mu
+func (a *FingerprintAdapter) pruneExpired(ctx context.Context, now time.Time) {
+	if now.Sub(a.prunedAt) < fingerprintPruneInterval {
+		return
+	}
+	a.prunedAt = now
+
+	for key, ref := range a.keys {
+		if !ref.expiration.IsZero() && !ref.expiration.After(now) {
+			delete(a.keys, key)
+			a.release(ctx, ref.contentKey)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package gormcache
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// storedBytes returns the number of value bytes held by a map-backed MemoryAdapter
+func storedBytes(adapter *MemoryAdapter) int {
+	adapter.mu.RLock()
+	defer adapter.mu.RUnlock()
+
+	n := 0
+	for _, item := range adapter.store {
+		n += len(item.value)
+	}
+	return n
+}
+
+func TestFingerprintAdapterDeduplicates(t *testing.T) {
+	inner := NewMemoryAdapter()
+	adapter := NewFingerprintAdapter(inner)
+	defer adapter.Close()
+
+	ctx := context.Background()
+	value := bytes.Repeat([]byte("x"), 4096)
+
+	// The same dataset cached under several keys, e.g. by ID and by email
+	for i := 0; i < 10; i++ {
+		if err := adapter.Set(ctx, fmt.Sprintf("user:%d", i), value, 1*time.Minute); err != nil {
+			t.Fatalf("failed to set: %v", err)
+		}
+	}
+
+	if n := storedBytes(inner); n >= 2*len(value) {
+		t.Errorf("expected deduplicated storage below %d bytes, got %d", 2*len(value), n)
+	}
+
+	for i := 0; i < 10; i++ {
+		got, err := adapter.Get(ctx, fmt.Sprintf("user:%d", i))
+		if err != nil || !bytes.Equal(got, value) {
+			t.Fatalf("expected user:%d to return the original value, got %d bytes, %v", i, len(got), err)
+		}
+	}
+}
+
+func TestFingerprintAdapterReferenceCounting(t *testing.T) {
+	inner := NewMemoryAdapter()
+	adapter := NewFingerprintAdapter(inner)
+	defer adapter.Close()
+
+	ctx := context.Background()
+
+	adapter.Set(ctx, "user:1", []byte("shared"), 1*time.Minute)
+	adapter.Set(ctx, "user:email:a", []byte("shared"), 1*time.Minute)
+
+	// Deleting one key keeps the content for the other
+	adapter.Delete(ctx, "user:1")
+	if _, err := adapter.Get(ctx, "user:1"); err == nil {
+		t.Error("expected user:1 to be deleted")
+	}
+	if value, err := adapter.Get(ctx, "user:email:a"); err != nil || string(value) != "shared" {
+		t.Fatalf("expected shared content to survive, got %q, %v", value, err)
+	}
+
+	// Deleting the last reference deletes the content
+	adapter.DeletePattern(ctx, "user:email:*")
+	if n := len(inner.store); n != 0 {
+		t.Errorf("expected content to be deleted with its last reference, got %d entries", n)
+	}
+
+	// Overwriting a key releases its previous content
+	adapter.Set(ctx, "user:2", []byte("old"), 1*time.Minute)
+	adapter.Set(ctx, "user:2", []byte("new"), 1*time.Minute)
+	if value, _ := adapter.Get(ctx, "user:2"); string(value) != "new" {
+		t.Errorf("expected 'new', got %q", value)
+	}
+	if n := len(inner.store); n != 2 {
+		t.Errorf("expected one reference and one content entry, got %d entries", n)
+	}
+}
+
+func TestFingerprintAdapterPrunesExpiredKeys(t *testing.T) {
+	adapter := NewFingerprintAdapter(NewMemoryAdapter())
+	defer adapter.Close()
+
+	ctx := context.Background()
+	for i := 0; i < 10; i++ {
+		adapter.Set(ctx, fmt.Sprintf("gorm:cache:users:%d", i), []byte(fmt.Sprint(i)), time.Millisecond)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	adapter.prunedAt = time.Now().Add(-fingerprintPruneInterval)
+	adapter.Set(ctx, "gorm:cache:users:live", []byte("live"), time.Minute)
+
+	if len(adapter.keys) != 1 || len(adapter.refs) != 1 || len(adapter.expirations) != 1 {
+		t.Errorf("expected only the live key to be tracked, got %d keys, %d refs, %d expirations",
+			len(adapter.keys), len(adapter.refs), len(adapter.expirations))
+	}
+}
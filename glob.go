@@ -0,0 +1,101 @@
+package gormcache
+
+// globMatch reports whether key matches the Redis style glob pattern, byte by
+// byte and independently of the platform path separator
+// A malformed [...] class never matches
+func globMatch(pattern, key string) bool {
+	px, kx := 0, 0
+	// 最近一个 * 的位置，匹配失败时让它多吞一个字节后重试
+	starPx, starKx := -1, -1
+
+	for px < len(pattern) || kx < len(key) {
+		if px < len(pattern) {
+			switch c := pattern[px]; c {
+			case '*':
+				starPx, starKx = px, kx+1
+				px++
+				continue
+			case '?':
+				if kx < len(key) {
+					px++
+					kx++
+					continue
+				}
+			case '[':
+				if kx < len(key) {
+					matched, width, ok := matchClass(pattern[px:], key[kx])
+					if !ok {
+						return false
+					}
+					if matched {
+						px += width
+						kx++
+						continue
+					}
+				}
+			case '\\':
+				if px+1 < len(pattern) {
+					c = pattern[px+1]
+					px++
+				}
+				fallthrough
+			default:
+				if kx < len(key) && key[kx] == c {
+					px++
+					kx++
+					continue
+				}
+			}
+		}
+		if starPx >= 0 && starKx <= len(key) {
+			px, kx = starPx+1, starKx
+			starKx++
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// matchClass matches c against the [...] class at the start of pattern,
+// returning the width of the class, or ok false if it is not terminated
+// Ranges (a-z), negation ([^...]) and \ escapes are supported
+func matchClass(pattern string, c byte) (matched bool, width int, ok bool) {
+	i := 1
+	negate := i < len(pattern) && pattern[i] == '^'
+	if negate {
+		i++
+	}
+
+	for {
+		if i >= len(pattern) {
+			return false, 0, false
+		}
+		if pattern[i] == ']' {
+			break
+		}
+
+		lo := pattern[i]
+		if lo == '\\' && i+1 < len(pattern) {
+			i++
+			lo = pattern[i]
+		}
+		hi := lo
+		if i+2 < len(pattern) && pattern[i+1] == '-' && pattern[i+2] != ']' {
+			i += 2
+			hi = pattern[i]
+			if hi == '\\' && i+1 < len(pattern) {
+				i++
+				hi = pattern[i]
+			}
+		}
+		if lo > hi {
+			lo, hi = hi, lo
+		}
+		if lo <= c && c <= hi {
+			matched = true
+		}
+		i++
+	}
+	return matched != negate, i + 1, true
+}
@@ -0,0 +1,127 @@
+package gormcache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// incrementer is implemented by adapters able to increment a counter atomically
+type incrementer interface {
+	Incr(ctx context.Context, key string) (int64, error)
+}
+
+// evictionExempter is implemented by adapters able to keep a key out of their
+// eviction policy
+type evictionExempter interface {
+	exemptFromEviction(key string)
+}
+
+// BumpVersion increments the cache version stored under Config.VersionKey,
+// making every cached entry unreachable without deleting anything; the old
+// entries expire via their TTL
+// The increment is atomic across processes for adapters implementing Incr
+// (RedisAdapter) and within the process otherwise; other processes see it
+// within Config.VersionRefreshInterval
+func (p *CachePlugin) BumpVersion(ctx context.Context) error {
+	if p.config.VersionKey == "" {
+		return errors.New("gorm:cache: BumpVersion requires Config.VersionKey")
+	}
+
+	if adapter, ok := p.adapter().(incrementer); ok {
+		version, err := adapter.Incr(ctx, p.config.VersionKey)
+		if err != nil {
+			return err
+		}
+		p.storeVersion(version)
+		return nil
+	}
+
+	p.versionMu.Lock()
+	defer p.versionMu.Unlock()
+
+	var version int64
+	if data, err := p.adapter().Get(ctx, p.config.VersionKey); err == nil {
+		version, _ = strconv.ParseInt(string(data), 10, 64)
+	}
+	if seen := p.seenVersion(); seen > version {
+		version = seen
+	}
+	version++
+	if err := p.adapter().Set(ctx, p.config.VersionKey, []byte(strconv.FormatInt(version, 10)), 0); err != nil {
+		return err
+	}
+	p.storeVersion(version)
+	return nil
+}
+
+// keyVersion returns the version segment of cache keys, combining the schema
+// version and the version bumped by BumpVersion
+func (p *CachePlugin) keyVersion(ctx context.Context) string {
+	version := p.schemaVersion()
+	if p.config.VersionKey == "" {
+		return version
+	}
+
+	// 从未递增过版本时不改变缓存键
+	global := p.globalVersion(ctx)
+	if global == 0 {
+		return version
+	}
+	if version != "" {
+		version += ":"
+	}
+	return version + "g" + strconv.FormatInt(global, 10)
+}
+
+// globalVersion returns the version bumped by BumpVersion, read from the
+// adapter at most once per Config.VersionRefreshInterval
+// Once a version has been seen it never goes back: a missing (evicted or
+// flushed) or lower stored version is rewritten with the last one seen, so
+// entries cached before a bump can never be served again
+func (p *CachePlugin) globalVersion(ctx context.Context) int64 {
+	p.versionReadMu.Lock()
+	defer p.versionReadMu.Unlock()
+
+	if !p.versionReadAt.IsZero() && time.Since(p.versionReadAt) < p.config.VersionRefreshInterval {
+		return p.version
+	}
+	p.versionReadAt = time.Now()
+
+	data, err := p.adapter().Get(ctx, p.config.VersionKey)
+	if err != nil && !errors.Is(err, ErrCacheMiss) {
+		// 后端不可用时沿用上次读取的版本
+		return p.version
+	}
+	stored, _ := strconv.ParseInt(string(data), 10, 64)
+	if stored >= p.version {
+		p.version = stored
+		return p.version
+	}
+
+	value := []byte(strconv.FormatInt(p.version, 10))
+	if err := p.adapter().Set(ctx, p.config.VersionKey, value, 0); err != nil {
+		p.onError(err)
+	}
+	return p.version
+}
+
+// seenVersion returns the last version read or bumped by this plugin
+func (p *CachePlugin) seenVersion() int64 {
+	p.versionReadMu.Lock()
+	defer p.versionReadMu.Unlock()
+	return p.version
+}
+
+// storeVersion records version as read from the adapter, unless a higher one
+// has already been seen
+func (p *CachePlugin) storeVersion(version int64) {
+	p.versionReadMu.Lock()
+	defer p.versionReadMu.Unlock()
+
+	if version > p.version {
+		p.version = version
+	}
+	p.versionReadAt = time.Now()
+}
@@ -0,0 +1,170 @@
+package gormcache
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func TestBumpVersion(t *testing.T) {
+	db := setupTestDB(t)
+
+	config := DefaultConfig()
+	adapter := NewMemoryAdapter()
+	config.Adapter = adapter
+	cachePlugin := New(config)
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	user := TestUser{Name: "Before"}
+	db.Create(&user)
+
+	var first TestUser
+	db.First(&first, user.ID)
+
+	// Change the row behind the plugin's back so a stale entry would be visible
+	db.Exec("UPDATE test_users SET name = ? WHERE id = ?", "After", user.ID)
+
+	var cached TestUser
+	db.First(&cached, user.ID)
+	if cached.Name != "Before" {
+		t.Fatalf("expected the cached value before the bump, got %q", cached.Name)
+	}
+
+	entries := len(adapter.store)
+	if err := cachePlugin.BumpVersion(context.Background()); err != nil {
+		t.Fatalf("BumpVersion failed: %v", err)
+	}
+	if len(adapter.store) != entries+1 {
+		t.Errorf("expected the bump to only add the version key, got %d keys", len(adapter.store))
+	}
+
+	var fresh TestUser
+	db.First(&fresh, user.ID)
+	if fresh.Name != "After" {
+		t.Errorf("expected the previously cached value not to be returned, got %q", fresh.Name)
+	}
+
+	if err := cachePlugin.BumpVersion(context.Background()); err != nil {
+		t.Fatalf("BumpVersion failed: %v", err)
+	}
+	if value, _ := adapter.Get(context.Background(), defaultVersionKey); string(value) != "2" {
+		t.Errorf("expected version 2, got %q", value)
+	}
+}
+
+func TestBumpVersionRedis(t *testing.T) {
+	mr := miniredis.RunT(t)
+	ctx := context.Background()
+
+	cachePlugin := New(Config{
+		Adapter:    NewRedisAdapter(RedisAdapterConfig{Addr: mr.Addr()}),
+		VersionKey: "app:cache:version",
+	})
+	defer cachePlugin.Close()
+
+	for i := 0; i < 3; i++ {
+		if err := cachePlugin.BumpVersion(ctx); err != nil {
+			t.Fatalf("BumpVersion failed: %v", err)
+		}
+	}
+	if value, _ := mr.Get("app:cache:version"); value != "3" {
+		t.Errorf("expected version 3 via INCR, got %q", value)
+	}
+	if version := cachePlugin.keyVersion(ctx); version != "g3" {
+		t.Errorf("expected key version g3, got %q", version)
+	}
+}
+
+func TestBumpVersionDisabled(t *testing.T) {
+	cachePlugin := New(Config{Adapter: NewMemoryAdapter(), TTL: time.Minute})
+	if err := cachePlugin.BumpVersion(context.Background()); err == nil {
+		t.Error("expected an error without VersionKey")
+	}
+}
+
+func TestVersionNeverFallsBack(t *testing.T) {
+	ctx := context.Background()
+	adapter := NewMemoryAdapter()
+	config := DefaultConfig()
+	config.Adapter = adapter
+	config.VersionRefreshInterval = time.Nanosecond
+	cachePlugin := New(config)
+	defer cachePlugin.Close()
+
+	if err := cachePlugin.BumpVersion(ctx); err != nil {
+		t.Fatalf("BumpVersion failed: %v", err)
+	}
+	if version := cachePlugin.keyVersion(ctx); version != "g1" {
+		t.Fatalf("expected key version g1, got %q", version)
+	}
+
+	// 版本键被删除后不能回到未递增的缓存键
+	adapter.Delete(ctx, defaultVersionKey)
+	if version := cachePlugin.keyVersion(ctx); version != "g1" {
+		t.Errorf("expected key version g1 after the version key was deleted, got %q", version)
+	}
+	if value, _ := adapter.Get(ctx, defaultVersionKey); string(value) != "1" {
+		t.Errorf("expected the version key to be restored, got %q", value)
+	}
+
+	if err := cachePlugin.BumpVersion(ctx); err != nil {
+		t.Fatalf("BumpVersion failed: %v", err)
+	}
+	if version := cachePlugin.keyVersion(ctx); version != "g2" {
+		t.Errorf("expected key version g2, got %q", version)
+	}
+}
+
+func TestVersionCachedLocally(t *testing.T) {
+	mr := miniredis.RunT(t)
+	ctx := context.Background()
+
+	cachePlugin := New(Config{
+		Adapter:                NewRedisAdapter(RedisAdapterConfig{Addr: mr.Addr()}),
+		VersionKey:             "app:cache:version",
+		VersionRefreshInterval: time.Hour,
+	})
+	defer cachePlugin.Close()
+
+	if err := cachePlugin.BumpVersion(ctx); err != nil {
+		t.Fatalf("BumpVersion failed: %v", err)
+	}
+
+	// 其他进程的递增在刷新间隔内不可见
+	mr.Set("app:cache:version", "5")
+	if version := cachePlugin.keyVersion(ctx); version != "g1" {
+		t.Errorf("expected the locally cached key version g1, got %q", version)
+	}
+
+	cachePlugin.versionReadMu.Lock()
+	cachePlugin.versionReadAt = time.Time{}
+	cachePlugin.versionReadMu.Unlock()
+	if version := cachePlugin.keyVersion(ctx); version != "g5" {
+		t.Errorf("expected key version g5 after the refresh interval, got %q", version)
+	}
+}
+
+func TestVersionKeyNotEvicted(t *testing.T) {
+	ctx := context.Background()
+	adapter := NewMemoryAdapterWithConfig(MemoryAdapterConfig{MaxEntries: 2})
+	config := DefaultConfig()
+	config.Adapter = adapter
+	cachePlugin := New(config)
+	defer cachePlugin.Close()
+
+	if err := cachePlugin.BumpVersion(ctx); err != nil {
+		t.Fatalf("BumpVersion failed: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		adapter.Set(ctx, "gorm:cache:test_users:"+strconv.Itoa(i), []byte("x"), time.Minute)
+	}
+	if value, err := adapter.Get(ctx, defaultVersionKey); err != nil || string(value) != "1" {
+		t.Errorf("expected the version key to survive eviction, got %q, %v", value, err)
+	}
+}
@@ -0,0 +1,94 @@
+package gormcache
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/zeebo/xxh3"
+)
+
+// HashAlgorithm selects the hash function used for the query part of cache keys
+type HashAlgorithm int
+
+const (
+	// HashMD5 hashes with crypto/md5 (default)
+	HashMD5 HashAlgorithm = iota
+	// HashXXH3 hashes with the 128-bit XXH3 non-cryptographic hash
+	HashXXH3
+	// HashFNV128 hashes with the 128-bit FNV-1a hash
+	HashFNV128
+)
+
+// newKeyHasher returns the function producing the hash of the cache key input
+// encoded with encoder (hex if nil), truncated to length characters if length > 0
+func newKeyHasher(algorithm HashAlgorithm, encoder func([]byte) string, length int) func([]byte) string {
+	if encoder == nil {
+		encoder = HexEncoder
+	}
+
+	var sum func([]byte) []byte
+	switch algorithm {
+	case HashXXH3:
+		sum = func(data []byte) []byte {
+			hash := xxh3.Hash128(data).Bytes()
+			return hash[:]
+		}
+	case HashFNV128:
+		sum = func(data []byte) []byte {
+			h := fnv.New128a()
+			h.Write(data)
+			return h.Sum(nil)
+		}
+	default:
+		sum = func(data []byte) []byte {
+			hash := md5.Sum(data)
+			return hash[:]
+		}
+	}
+
+	return truncateHasher(func(data []byte) string {
+		return encoder(sum(data))
+	}, length)
+}
+
+// HexEncoder is a Config.KeyEncoder producing lowercase hex, the default
+func HexEncoder(hash []byte) string {
+	return hex.EncodeToString(hash)
+}
+
+// Base64URLEncoder is a Config.KeyEncoder producing URL-safe base64, which is a
+// third shorter than hex (24 instead of 32 characters for MD5)
+func Base64URLEncoder(hash []byte) string {
+	return base64.URLEncoding.EncodeToString(hash)
+}
+
+// truncateHasher truncates the hashes of hasher to length characters if length > 0
+func truncateHasher(hasher func([]byte) string, length int) func([]byte) string {
+	if length <= 0 {
+		return hasher
+	}
+	return func(data []byte) string {
+		hash := hasher(data)
+		if length < len(hash) {
+			return hash[:length]
+		}
+		return hash
+	}
+}
+
+// MD5Hasher is a Config.KeyHasher producing the hex encoded MD5 hash of the
+// cache key input, as the default HashMD5 algorithm does
+func MD5Hasher(data []byte) string {
+	hash := md5.Sum(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// XXHashHasher is a Config.KeyHasher producing the hex encoded 64-bit xxHash
+// of the cache key input; it is much faster than MD5 but not cryptographic
+func XXHashHasher(data []byte) string {
+	return fmt.Sprintf("%016x", xxhash.Sum64(data))
+}
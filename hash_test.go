@@ -0,0 +1,208 @@
+package gormcache
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestKeyHashAlgorithms(t *testing.T) {
+	data := []byte(`{"SQL":"SELECT * FROM users WHERE id = ?","Vars":[1]}`)
+
+	tests := []struct {
+		algorithm HashAlgorithm
+		length    int
+		want      int
+	}{
+		{HashMD5, 0, 32},
+		{HashXXH3, 0, 32},
+		{HashFNV128, 0, 32},
+		{HashMD5, 16, 16},
+		{HashXXH3, 16, 16},
+		{HashFNV128, 64, 32},
+	}
+
+	seen := make(map[string]HashAlgorithm)
+	for _, tt := range tests {
+		hash := newKeyHasher(tt.algorithm, nil, tt.length)(data)
+		if len(hash) != tt.want {
+			t.Errorf("algorithm %d length %d: expected %d chars, got %d", tt.algorithm, tt.length, tt.want, len(hash))
+		}
+		if hash != newKeyHasher(tt.algorithm, nil, tt.length)(data) {
+			t.Errorf("algorithm %d: hash is not deterministic", tt.algorithm)
+		}
+		if tt.length == 0 {
+			if other, ok := seen[hash]; ok {
+				t.Errorf("algorithms %d and %d produced the same hash", other, tt.algorithm)
+			}
+			seen[hash] = tt.algorithm
+		}
+	}
+}
+
+func TestKeyHashAlgorithmCaching(t *testing.T) {
+	db := setupTestDB(t)
+
+	adapter := NewMemoryAdapter()
+	cachePlugin := New(Config{
+		Adapter:          adapter,
+		TTL:              5 * time.Minute,
+		KeyHashAlgorithm: HashXXH3,
+		KeyHashLength:    16,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	queries := countQueries(t, db)
+
+	user := TestUser{Name: "Test User"}
+	db.Create(&user)
+
+	var user1, user2 TestUser
+	db.First(&user1, user.ID)
+	db.First(&user2, user.ID)
+
+	if got := atomic.LoadInt64(queries); got != 1 {
+		t.Errorf("expected 1 database query, got %d", got)
+	}
+	for key := range adapter.store {
+		hash := key[strings.LastIndex(key, ":")+1:]
+		if len(hash) != 16 {
+			t.Errorf("expected 16 char hash in key %q", key)
+		}
+	}
+}
+
+func TestNamedKeyHashers(t *testing.T) {
+	data := []byte(`{"SQL":"SELECT * FROM users WHERE id = ?","Vars":[1]}`)
+
+	if got, want := MD5Hasher(data), newKeyHasher(HashMD5, nil, 0)(data); got != want {
+		t.Errorf("expected MD5Hasher to match HashMD5, got %s and %s", got, want)
+	}
+	if hash := XXHashHasher(data); len(hash) != 16 || hash != XXHashHasher(data) {
+		t.Errorf("expected a deterministic 16 char hash, got %q", hash)
+	}
+}
+
+func TestKeyHasher(t *testing.T) {
+	db := setupTestDB(t)
+
+	adapter := NewMemoryAdapter()
+	defer adapter.Close()
+	config := Config{
+		Adapter:   adapter,
+		TTL:       5 * time.Minute,
+		KeyHasher: XXHashHasher,
+	}
+	cachePlugin := New(config)
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+
+	user := TestUser{Name: "Test User"}
+	db.Create(&user)
+
+	queries := countQueries(t, db)
+
+	// The same SQL hashes to the same key on every call
+	for i := 0; i < 3; i++ {
+		var result TestUser
+		db.First(&result, user.ID)
+	}
+	if got := atomic.LoadInt64(queries); got != 1 {
+		t.Errorf("expected 1 database query, got %d", got)
+	}
+	for key := range adapter.store {
+		hash := key[strings.LastIndex(key, ":")+1:]
+		if len(hash) != 16 {
+			t.Errorf("expected 16 char xxHash in key %q", key)
+		}
+	}
+
+	// Switching hashers on the same adapter leaves the old keys unreachable
+	other := setupTestDB(t)
+	config.KeyHasher = MD5Hasher
+	if err := other.Use(New(config)); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	other.Create(&TestUser{Name: "Test User"})
+	queries = countQueries(t, other)
+
+	var result TestUser
+	other.First(&result, user.ID)
+	if got := atomic.LoadInt64(queries); got != 1 {
+		t.Errorf("expected the query to miss with the new hasher, got %d database queries", got)
+	}
+}
+
+func benchmarkKeyHasher(b *testing.B, algorithm HashAlgorithm) {
+	hashKey := newKeyHasher(algorithm, nil, 0)
+	data := []byte(fmt.Sprintf(`{"SQL":"SELECT * FROM %s WHERE %s","Vars":[1,"active"]}`,
+		"users", strings.Repeat("name = ? AND ", 8)+"status = ?"))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		hashKey(data)
+	}
+}
+
+func BenchmarkKeyHashMD5(b *testing.B) {
+	benchmarkKeyHasher(b, HashMD5)
+}
+
+func BenchmarkKeyHashXXH3(b *testing.B) {
+	benchmarkKeyHasher(b, HashXXH3)
+}
+
+func BenchmarkKeyHashFNV128(b *testing.B) {
+	benchmarkKeyHasher(b, HashFNV128)
+}
+
+func TestKeyEncoder(t *testing.T) {
+	hashOf := func(encoder func([]byte) string) string {
+		db := setupTestDB(t)
+		adapter := NewMemoryAdapter()
+		defer adapter.Close()
+		if err := db.Use(New(Config{Adapter: adapter, TTL: 5 * time.Minute, KeyEncoder: encoder})); err != nil {
+			t.Fatalf("failed to install plugin: %v", err)
+		}
+
+		user := TestUser{Name: "Test User"}
+		db.Create(&user)
+		queries := countQueries(t, db)
+
+		// The encoder is applied on both write and read, so repeated queries hit
+		for i := 0; i < 3; i++ {
+			var result TestUser
+			db.First(&result, user.ID)
+		}
+		if got := atomic.LoadInt64(queries); got != 1 {
+			t.Errorf("expected 1 database query, got %d", got)
+		}
+
+		for key := range adapter.store {
+			return key[strings.LastIndex(key, ":")+1:]
+		}
+		t.Fatal("expected the query to be cached")
+		return ""
+	}
+
+	hexHash := hashOf(HexEncoder)
+	if len(hexHash) != 32 || hexHash != hashOf(nil) {
+		t.Errorf("expected the 32 char hex hash of the default encoding, got %q", hexHash)
+	}
+
+	base64Hash := hashOf(Base64URLEncoder)
+	if len(base64Hash) != 24 {
+		t.Errorf("expected a 24 char base64 hash, got %q", base64Hash)
+	}
+	if decoded, err := base64.URLEncoding.DecodeString(base64Hash); err != nil || HexEncoder(decoded) != hexHash {
+		t.Errorf("expected %q to encode the same hash as %q", base64Hash, hexHash)
+	}
+}
@@ -0,0 +1,32 @@
+package gormcache
+
+import (
+	"context"
+	"sync/atomic"
+
+	"gorm.io/gorm"
+)
+
+// recordTableHit counts a cache hit for the table of the statement
+func (p *CachePlugin) recordTableHit(db *gorm.DB) {
+	table := statementTable(db)
+	if table == "" {
+		table = "unknown"
+	}
+
+	counter, ok := p.tableHits.Load(table)
+	if !ok {
+		counter, _ = p.tableHits.LoadOrStore(table, new(atomic.Int64))
+	}
+	counter.(*atomic.Int64).Add(1)
+}
+
+// HeatMap returns the number of cache hits per table since the plugin was created
+func (p *CachePlugin) HeatMap(ctx context.Context) (map[string]int, error) {
+	heatMap := make(map[string]int)
+	p.tableHits.Range(func(table, counter any) bool {
+		heatMap[table.(string)] = int(counter.(*atomic.Int64).Load())
+		return true
+	})
+	return heatMap, nil
+}
@@ -0,0 +1,57 @@
+package gormcache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type testOrder struct {
+	ID     uint
+	UserID uint
+}
+
+func TestHeatMap(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&testOrder{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	cachePlugin := New(Config{
+		Adapter: NewMemoryAdapter(),
+		TTL:     5 * time.Minute,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	user := TestUser{Name: "John"}
+	db.Create(&user)
+	order := testOrder{UserID: user.ID}
+	db.Create(&order)
+
+	// One miss followed by 5 hits for users, 3 hits for orders
+	for i := 0; i < 6; i++ {
+		var result TestUser
+		db.First(&result, user.ID)
+	}
+	for i := 0; i < 4; i++ {
+		var result testOrder
+		db.First(&result, order.ID)
+	}
+
+	heatMap, err := cachePlugin.HeatMap(context.Background())
+	if err != nil {
+		t.Fatalf("failed to get heat map: %v", err)
+	}
+	if heatMap["test_users"] != 5 {
+		t.Errorf("expected 5 hits for test_users, got %d", heatMap["test_users"])
+	}
+	if heatMap["test_orders"] != 3 {
+		t.Errorf("expected 3 hits for test_orders, got %d", heatMap["test_orders"])
+	}
+	if len(heatMap) != 2 {
+		t.Errorf("expected 2 tables, got %v", heatMap)
+	}
+}
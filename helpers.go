@@ -1,6 +1,11 @@
 package gormcache
 
-import "gorm.io/gorm"
+import (
+	"database/sql"
+	"time"
+
+	"gorm.io/gorm"
+)
 
 // SkipCache is a scope helper function to skip cache for a specific query
 // Usage: db.Scopes(gormcache.SkipCache()).Find(&users)
@@ -19,3 +24,77 @@ func EnableCache() func(*gorm.DB) *gorm.DB {
 		return db
 	}
 }
+
+// ForceCache is a scope helper function that caches a query even if
+// SkipCacheCondition, SkipCache or a skip set in the context would skip it
+// Locking reads, transactions skipped by config and disabled Count or Pluck
+// caching are still not cached, since serving them from cache is unsafe
+// Usage: db.Scopes(gormcache.ForceCache()).Find(&users)
+func ForceCache() func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db.Statement.Settings.Store("gorm:cache:force", true)
+		return db
+	}
+}
+
+// ReadThrough is a scope helper function that loads cache misses through loader
+// instead of the database; loader receives the primary key from the WHERE clause
+// Usage: db.Scopes(gormcache.ReadThrough(loadUser)).First(&user, 1)
+func ReadThrough(loader func(id uint) (interface{}, error)) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db.Statement.Settings.Store("gorm:cache:read_through", loader)
+		return db
+	}
+}
+
+// WithTTL is a scope helper function that caches the query result for ttl
+// instead of Config.TTL
+// Usage: db.Scopes(gormcache.WithTTL(30 * time.Second)).Find(&items)
+func WithTTL(ttl time.Duration) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db.Statement.Settings.Store("gorm:cache:ttl", ttl)
+		return db
+	}
+}
+
+// WithCacheTags is a scope helper function that tags the cached query result,
+// so it can be invalidated with CachePlugin.InvalidateTags
+// Usage: db.Scopes(gormcache.WithCacheTags("product-list")).Find(&products)
+func WithCacheTags(tags ...string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db.Statement.Settings.Store("gorm:cache:tags", tags)
+		return db
+	}
+}
+
+// RequireContext is a scope helper function that panics if the statement has no context
+// Usage: db.Scopes(gormcache.RequireContext()).Find(&users)
+func RequireContext() func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if db.Statement.Context == nil {
+			panic(errNilContext)
+		}
+		return db
+	}
+}
+
+// CacheFor is a scope helper function that caches the query in the namespace of model,
+// so it is invalidated whenever the model's table is written
+// Usage: db.Scopes(gormcache.CacheFor(User{})).Raw("SELECT ...").Find(&rows)
+func CacheFor(model interface{}) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db.Statement.Settings.Store("gorm:cache:model_override", model)
+		return db
+	}
+}
+
+// BeginTx starts a transaction and records its isolation level in the context,
+// so SkipCacheForIsolationLevel can recognize it
+// Usage: tx := gormcache.BeginTx(db, &sql.TxOptions{Isolation: sql.LevelSerializable})
+func BeginTx(db *gorm.DB, opts *sql.TxOptions) *gorm.DB {
+	if opts == nil {
+		return db.Begin()
+	}
+	ctx := WithIsolationLevel(db.Statement.Context, opts.Isolation)
+	return db.WithContext(ctx).Begin(opts)
+}
@@ -0,0 +1,67 @@
+package gormcache
+
+import (
+	"context"
+	"time"
+)
+
+// onError calls Config.OnError, if set
+func (p *CachePlugin) onError(err error) {
+	if p.config.OnError != nil {
+		p.config.OnError(err)
+	}
+}
+
+// onHit calls Config.OnHit, if set
+func (p *CachePlugin) onHit(key string) {
+	if p.config.OnHit != nil {
+		p.config.OnHit(key)
+	}
+}
+
+// onMiss calls Config.OnMiss, if set
+func (p *CachePlugin) onMiss(key string) {
+	if p.config.OnMiss != nil {
+		p.config.OnMiss(key)
+	}
+}
+
+// onStore calls Config.OnStore, if set
+func (p *CachePlugin) onStore(key string, ttl time.Duration) {
+	if p.config.OnStore != nil {
+		p.config.OnStore(key, ttl)
+	}
+}
+
+// onInvalidate calls Config.OnInvalidate, if set
+func (p *CachePlugin) onInvalidate(pattern string) {
+	if p.config.OnInvalidate != nil {
+		p.config.OnInvalidate(pattern)
+	}
+}
+
+// beforeGet calls Config.BeforeGet, if set, and reports whether to skip the read
+func (p *CachePlugin) beforeGet(ctx context.Context, key string) bool {
+	return p.config.BeforeGet != nil && p.config.BeforeGet(ctx, key)
+}
+
+// afterGet calls Config.AfterGet, if set
+func (p *CachePlugin) afterGet(ctx context.Context, key string, hit bool) {
+	if p.config.AfterGet != nil {
+		p.config.AfterGet(ctx, key, hit)
+	}
+}
+
+// storeCached stores a query result with setCached between the Config.BeforeSet
+// and Config.AfterSet hooks, and reports whether it was stored
+func (p *CachePlugin) storeCached(ctx context.Context, key string, data []byte, ttl time.Duration) (bool, error) {
+	if p.config.BeforeSet != nil && p.config.BeforeSet(ctx, key) {
+		return false, nil
+	}
+
+	err := p.setCached(ctx, key, data, ttl)
+	if p.config.AfterSet != nil {
+		p.config.AfterSet(ctx, key, err)
+	}
+	return err == nil, err
+}
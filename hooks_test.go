@@ -0,0 +1,133 @@
+package gormcache
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEventHooks(t *testing.T) {
+	db := setupTestDB(t)
+
+	var hits, misses, stores, invalidations int
+	var storedTTL time.Duration
+	cachePlugin := New(Config{
+		Adapter:            NewMemoryAdapter(),
+		TTL:                5 * time.Minute,
+		InvalidateOnCreate: true,
+		InvalidateOnUpdate: true,
+		OnHit:              func(string) { hits++ },
+		OnMiss:             func(string) { misses++ },
+		OnStore: func(_ string, ttl time.Duration) {
+			stores++
+			storedTTL = ttl
+		},
+		OnInvalidate: func(string) { invalidations++ },
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	user := TestUser{Name: "John"}
+	db.Create(&user)
+
+	var users []TestUser
+	db.Find(&users) // miss + store
+	db.Find(&users) // hit
+	db.Model(&user).Update("name", "Johnny")
+	db.Find(&users) // miss + store
+
+	if hits != 1 || misses != 2 || stores != 2 || invalidations != 2 {
+		t.Errorf("expected 1 hit, 2 misses, 2 stores and 2 invalidations, got %d, %d, %d and %d",
+			hits, misses, stores, invalidations)
+	}
+	if storedTTL != 5*time.Minute {
+		t.Errorf("expected OnStore to receive the TTL, got %v", storedTTL)
+	}
+}
+
+func TestBeforeGetSkipsBlockedKeys(t *testing.T) {
+	db := setupTestDB(t)
+
+	var blocking atomic.Bool
+	var reads []bool
+	cachePlugin := New(Config{
+		Adapter:           NewMemoryAdapter(),
+		TTL:               5 * time.Minute,
+		CacheCountQueries: true,
+		// 阻止读取 Count 查询的缓存
+		BeforeGet: func(_ context.Context, key string) bool {
+			return blocking.Load() && matchPattern("gorm:cache:test_users:count:*", key)
+		},
+		AfterGet: func(_ context.Context, _ string, hit bool) {
+			reads = append(reads, hit)
+		},
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	db.Create(&TestUser{Name: "John"})
+	var users []TestUser
+	var count int64
+	db.Find(&users)
+	db.Model(&TestUser{}).Count(&count)
+
+	// Both results are cached now, but blocked keys are never read
+	blocking.Store(true)
+	reads = nil
+	queries := countQueries(t, db)
+	for i := 0; i < 3; i++ {
+		db.Find(&users)
+		db.Model(&TestUser{}).Count(&count)
+	}
+
+	if *queries != 3 {
+		t.Errorf("expected only the 3 blocked counts to query the database, got %d database queries", *queries)
+	}
+	if count != 1 || len(users) != 1 {
+		t.Errorf("expected 1 user, got count %d and %d users", count, len(users))
+	}
+	if len(reads) != 3 || !reads[0] || !reads[1] || !reads[2] {
+		t.Errorf("expected AfterGet to see 3 hits of the unblocked query, got %v", reads)
+	}
+}
+
+func TestBeforeSetSkipsStore(t *testing.T) {
+	db := setupTestDB(t)
+
+	adapter := NewMemoryAdapter()
+	var sets []error
+	cachePlugin := New(Config{
+		Adapter:   adapter,
+		TTL:       5 * time.Minute,
+		BeforeSet: func(_ context.Context, key string) bool { return matchPattern("gorm:cache:test_users:pluck:*", key) },
+		AfterSet:  func(_ context.Context, _ string, err error) { sets = append(sets, err) },
+		// 覆盖单飞加载路径
+		SingleflightEnabled: true,
+		CachePluckQueries:   true,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	db.Create(&TestUser{Name: "John"})
+	var users []TestUser
+	var names []string
+	db.Find(&users)
+	db.Model(&TestUser{}).Pluck("name", &names)
+
+	if len(adapter.store) != 1 {
+		t.Errorf("expected only the unblocked result to be stored, got %d entries", len(adapter.store))
+	}
+	if len(sets) != 1 || sets[0] != nil {
+		t.Errorf("expected AfterSet to see 1 successful store, got %v", sets)
+	}
+	if len(names) != 1 {
+		t.Errorf("expected the blocked query to return its result, got %v", names)
+	}
+}
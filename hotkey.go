@@ -0,0 +1,62 @@
+package gormcache
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// HotKeyDetector counts cache hits per key and reports keys whose hit count
+// reaches a threshold within a time window
+type HotKeyDetector struct {
+	threshold int64
+	window    time.Duration
+	handler   func(key string, hitCount int64)
+
+	// counts maps cache keys to *atomic.Int64 hit counters
+	counts      sync.Map
+	windowStart atomic.Int64
+}
+
+// NewHotKeyDetector creates a detector calling handler once per window for every
+// key hit at least threshold times within that window
+func NewHotKeyDetector(threshold int, window time.Duration, handler func(key string, hitCount int64)) *HotKeyDetector {
+	d := &HotKeyDetector{
+		threshold: int64(threshold),
+		window:    window,
+		handler:   handler,
+	}
+	d.windowStart.Store(time.Now().UnixNano())
+	return d
+}
+
+// RecordHit records a cache hit for key
+func (d *HotKeyDetector) RecordHit(key string) {
+	d.rotate(time.Now())
+
+	counter, ok := d.counts.Load(key)
+	if !ok {
+		counter, _ = d.counts.LoadOrStore(key, new(atomic.Int64))
+	}
+
+	// 只在刚好达到阈值时通知一次，避免同一窗口内重复触发
+	if hits := counter.(*atomic.Int64).Add(1); hits == d.threshold && d.handler != nil {
+		d.handler(key, hits)
+	}
+}
+
+// rotate resets all counters once the current window has elapsed
+func (d *HotKeyDetector) rotate(now time.Time) {
+	start := d.windowStart.Load()
+	if now.UnixNano()-start < int64(d.window) {
+		return
+	}
+	if !d.windowStart.CompareAndSwap(start, now.UnixNano()) {
+		return
+	}
+
+	d.counts.Range(func(key, _ any) bool {
+		d.counts.Delete(key)
+		return true
+	})
+}
@@ -0,0 +1,73 @@
+package gormcache
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHotKeyDetection(t *testing.T) {
+	db := setupTestDB(t)
+
+	var mu sync.Mutex
+	reported := map[string]int64{}
+	cachePlugin := New(Config{
+		Adapter:         NewMemoryAdapter(),
+		TTL:             5 * time.Minute,
+		HotKeyThreshold: 100,
+		HotKeyWindow:    time.Minute,
+		HotKeyHandler: func(key string, hitCount int64) {
+			mu.Lock()
+			defer mu.Unlock()
+			reported[key] = hitCount
+		},
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	user := TestUser{Name: "Hot"}
+	db.Create(&user)
+
+	for i := 0; i < 1000; i++ {
+		var result TestUser
+		db.First(&result, user.ID)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(reported) != 1 {
+		t.Fatalf("expected exactly one hot key, got %d", len(reported))
+	}
+	for _, hits := range reported {
+		if hits != 100 {
+			t.Errorf("expected handler to fire at 100 hits, got %d", hits)
+		}
+	}
+}
+
+func TestHotKeyDetectorWindow(t *testing.T) {
+	var fired int64
+	detector := NewHotKeyDetector(3, 20*time.Millisecond, func(key string, hitCount int64) {
+		atomic.AddInt64(&fired, 1)
+	})
+
+	// Hits spread over two windows never reach the threshold within one
+	detector.RecordHit("key")
+	detector.RecordHit("key")
+	time.Sleep(30 * time.Millisecond)
+	detector.RecordHit("key")
+	if n := atomic.LoadInt64(&fired); n != 0 {
+		t.Fatalf("expected no report across windows, got %d", n)
+	}
+
+	// Reaching the threshold in the new window reports the key once
+	for i := 0; i < 10; i++ {
+		detector.RecordHit("key")
+	}
+	if n := atomic.LoadInt64(&fired); n != 1 {
+		t.Errorf("expected one report, got %d", n)
+	}
+}
@@ -0,0 +1,29 @@
+package gormcache
+
+import "context"
+
+// InvalidateTable invalidates all cached queries of tableName, as a write to the
+// table through GORM would
+func (p *CachePlugin) InvalidateTable(ctx context.Context, tableName string) error {
+	return p.invalidateTable(ctx, tableName, p.config.tablePattern(ctx, tableName))
+}
+
+// invalidateTable invalidates the cached queries of tableName matching pattern,
+// recording the invalidation and publishing it to the other instances
+func (p *CachePlugin) invalidateTable(ctx context.Context, tableName, pattern string) error {
+	p.stats.invalidations.Add(1)
+	p.onInvalidate(pattern)
+	p.invalidateRequestCache(ctx, pattern)
+	p.logInvalidation(tableName, pattern)
+
+	err := p.invalidatePattern(ctx, pattern)
+	p.publishInvalidation(ctx, invalidationMessage{Pattern: pattern})
+	return err
+}
+
+// InvalidateAll removes all cached data from the adapter
+func (p *CachePlugin) InvalidateAll(ctx context.Context) error {
+	err := p.adapter().Clear(ctx)
+	p.publishInvalidation(ctx, invalidationMessage{Clear: true})
+	return err
+}
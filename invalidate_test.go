@@ -0,0 +1,108 @@
+package gormcache
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestInvalidateTable(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&testOrder{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	cachePlugin := New(Config{
+		Adapter: NewMemoryAdapter(),
+		TTL:     5 * time.Minute,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	db.Create(&TestUser{Name: "John"})
+	db.Create(&testOrder{UserID: 1})
+
+	queries := countQueries(t, db)
+	run := func() {
+		var users []TestUser
+		db.Find(&users)
+		var orders []testOrder
+		db.Find(&orders)
+	}
+
+	run()
+	run()
+	if *queries != 2 {
+		t.Fatalf("expected 2 database queries, got %d", *queries)
+	}
+
+	if err := cachePlugin.InvalidateTable(context.Background(), "test_users"); err != nil {
+		t.Fatalf("failed to invalidate table: %v", err)
+	}
+
+	// Only the users query reaches the database again
+	run()
+	if *queries != 3 {
+		t.Errorf("expected only the test_users query to be refetched, got %d database queries", *queries)
+	}
+
+	if err := cachePlugin.InvalidateAll(context.Background()); err != nil {
+		t.Fatalf("failed to invalidate all: %v", err)
+	}
+	run()
+	if *queries != 5 {
+		t.Errorf("expected both queries to be refetched, got %d database queries", *queries)
+	}
+}
+
+func TestInvalidateTableConcurrent(t *testing.T) {
+	adapter := NewMemoryAdapter()
+	cachePlugin := New(Config{Adapter: adapter, TTL: 5 * time.Minute})
+	defer cachePlugin.Close()
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			adapter.Set(ctx, "gorm:cache:test_users:key", []byte("value"), time.Minute)
+			_ = cachePlugin.InvalidateTable(ctx, "test_users")
+		}()
+		go func() {
+			defer wg.Done()
+			_ = cachePlugin.InvalidateAll(ctx)
+		}()
+	}
+	wg.Wait()
+
+	_ = cachePlugin.InvalidateTable(ctx, "test_users")
+	if _, err := adapter.Get(ctx, "gorm:cache:test_users:key"); err == nil {
+		t.Error("expected the table entries to be invalidated")
+	}
+}
+
+func TestInvalidateTableHooks(t *testing.T) {
+	var patterns []string
+	cachePlugin := New(Config{
+		Adapter:      NewMemoryAdapter(),
+		TTL:          5 * time.Minute,
+		OnInvalidate: func(pattern string) { patterns = append(patterns, pattern) },
+	})
+	defer cachePlugin.Close()
+
+	// A manual invalidation is recorded like a write through GORM
+	if err := cachePlugin.InvalidateTable(context.Background(), "test_users"); err != nil {
+		t.Fatalf("failed to invalidate table: %v", err)
+	}
+
+	if stats := cachePlugin.Stats(); stats.Invalidations != 1 {
+		t.Errorf("expected 1 invalidation, got %d", stats.Invalidations)
+	}
+	if len(patterns) != 1 || patterns[0] != "gorm:cache:test_users:*" {
+		t.Errorf("expected OnInvalidate to be called with the table pattern, got %v", patterns)
+	}
+}
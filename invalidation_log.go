@@ -0,0 +1,107 @@
+package gormcache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// defaultInvalidationLogSize is the number of entries kept by the invalidation
+// log when Config.InvalidationLogSize is not set
+const defaultInvalidationLogSize = 1000
+
+// InvalidationLogEntry records one invalidation, see Config.EnableInvalidationLog
+type InvalidationLogEntry struct {
+	Timestamp time.Time
+	TableName string
+	Pattern   string
+}
+
+// invalidationLog is a ring buffer of the most recent invalidations
+type invalidationLog struct {
+	mu      sync.Mutex
+	entries []InvalidationLogEntry
+	next    int
+	full    bool
+}
+
+// newInvalidationLog creates a log keeping the last size entries
+func newInvalidationLog(size int) *invalidationLog {
+	return &invalidationLog{entries: make([]InvalidationLogEntry, size)}
+}
+
+// record appends entry, overwriting the oldest one once the log is full
+func (l *invalidationLog) record(entry InvalidationLogEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries[l.next] = entry
+	l.next = (l.next + 1) % len(l.entries)
+	if l.next == 0 {
+		l.full = true
+	}
+}
+
+// last returns the last n entries, oldest first; n <= 0 returns all of them
+func (l *invalidationLog) last(n int) []InvalidationLogEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	count := l.next
+	if l.full {
+		count = len(l.entries)
+	}
+	if n <= 0 || n > count {
+		n = count
+	}
+
+	result := make([]InvalidationLogEntry, n)
+	start := l.next - n
+	for i := range result {
+		result[i] = l.entries[(start+i+len(l.entries))%len(l.entries)]
+	}
+	return result
+}
+
+// logInvalidation records the invalidation of pattern if EnableInvalidationLog is set
+func (p *CachePlugin) logInvalidation(tableName, pattern string) {
+	if p.invalidations == nil {
+		return
+	}
+	p.invalidations.record(InvalidationLogEntry{
+		Timestamp: time.Now(),
+		TableName: tableName,
+		Pattern:   pattern,
+	})
+}
+
+// InvalidationHistory returns the last n logged invalidations, oldest first;
+// n <= 0 returns the whole log
+// It returns nil unless Config.EnableInvalidationLog is set
+func (p *CachePlugin) InvalidationHistory(n int) []InvalidationLogEntry {
+	if p.invalidations == nil {
+		return nil
+	}
+	return p.invalidations.last(n)
+}
+
+// ReplayInvalidations invalidates the patterns of all logged invalidations
+// again, oldest first, e.g. after importing a snapshot taken before them
+// Replayed invalidations are not logged again
+func (p *CachePlugin) ReplayInvalidations(ctx context.Context) error {
+	if p.invalidations == nil {
+		return errors.New("gorm:cache: ReplayInvalidations requires Config.EnableInvalidationLog")
+	}
+
+	var errs []error
+	for _, entry := range p.invalidations.last(0) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := p.invalidatePattern(ctx, entry.Pattern); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
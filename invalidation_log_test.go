@@ -0,0 +1,94 @@
+package gormcache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestReplayInvalidations(t *testing.T) {
+	db := setupTestDB(t)
+
+	cachePlugin := New(Config{
+		Adapter:               NewMemoryAdapter(),
+		TTL:                   5 * time.Minute,
+		InvalidateOnCreate:    true,
+		EnableInvalidationLog: true,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	ctx := context.Background()
+	tables := []string{"test_users", "orders", "products", "invoices", "payments"}
+	db.Create(&TestUser{Name: "John"})
+	for _, table := range tables[1:] {
+		cachePlugin.InvalidateTable(ctx, table)
+	}
+
+	history := cachePlugin.InvalidationHistory(0)
+	if len(history) != len(tables) {
+		t.Fatalf("expected %d logged invalidations, got %d", len(tables), len(history))
+	}
+	for i, entry := range history {
+		if entry.TableName != tables[i] || entry.Pattern != "gorm:cache:"+tables[i]+":*" || entry.Timestamp.IsZero() {
+			t.Errorf("unexpected entry %d: %+v", i, entry)
+		}
+	}
+
+	// A fresh cache restored from a snapshot taken before the invalidations
+	restored := NewMemoryAdapter()
+	cachePlugin.SetAdapter(restored)
+	for _, table := range tables {
+		restored.Set(ctx, fmt.Sprintf("gorm:cache:%s:abc", table), []byte("stale"), time.Minute)
+	}
+	restored.Set(ctx, "gorm:cache:customers:abc", []byte("kept"), time.Minute)
+
+	if err := cachePlugin.ReplayInvalidations(ctx); err != nil {
+		t.Fatalf("ReplayInvalidations failed: %v", err)
+	}
+	for _, table := range tables {
+		if _, err := restored.Get(ctx, fmt.Sprintf("gorm:cache:%s:abc", table)); err == nil {
+			t.Errorf("expected the %s entry to be purged", table)
+		}
+	}
+	if _, err := restored.Get(ctx, "gorm:cache:customers:abc"); err != nil {
+		t.Errorf("expected entries of other tables to be kept: %v", err)
+	}
+
+	if got := len(cachePlugin.InvalidationHistory(0)); got != len(tables) {
+		t.Errorf("expected replayed invalidations not to be logged again, got %d entries", got)
+	}
+}
+
+func TestInvalidationLogSize(t *testing.T) {
+	cachePlugin := New(Config{EnableInvalidationLog: true, InvalidationLogSize: 3})
+	defer cachePlugin.Close()
+
+	for i := 1; i <= 5; i++ {
+		cachePlugin.InvalidateTable(context.Background(), fmt.Sprintf("table%d", i))
+	}
+
+	history := cachePlugin.InvalidationHistory(0)
+	if len(history) != 3 || history[0].TableName != "table3" || history[2].TableName != "table5" {
+		t.Errorf("expected the 3 most recent invalidations, got %+v", history)
+	}
+	if last := cachePlugin.InvalidationHistory(2); len(last) != 2 || last[0].TableName != "table4" {
+		t.Errorf("expected the last 2 invalidations, got %+v", last)
+	}
+}
+
+func TestInvalidationLogDisabled(t *testing.T) {
+	cachePlugin := New(Config{})
+	defer cachePlugin.Close()
+
+	cachePlugin.InvalidateTable(context.Background(), "users")
+	if history := cachePlugin.InvalidationHistory(0); history != nil {
+		t.Errorf("expected no history, got %+v", history)
+	}
+	if err := cachePlugin.ReplayInvalidations(context.Background()); err == nil {
+		t.Error("expected ReplayInvalidations to fail without EnableInvalidationLog")
+	}
+}
@@ -0,0 +1,49 @@
+package gormcache
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"gorm.io/gorm"
+)
+
+// parseInvalidationPatterns parses the ModelInvalidationPatterns templates
+// Templates failing to parse are left out of the result and reported in the error
+func parseInvalidationPatterns(patterns map[string]string) (map[string]*template.Template, error) {
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+
+	templates := make(map[string]*template.Template, len(patterns))
+	var errs []error
+	for table, pattern := range patterns {
+		tmpl, err := template.New(table).Parse(pattern)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("gorm:cache: invalid ModelInvalidationPatterns template for %q: %w", table, err))
+			continue
+		}
+		templates[table] = tmpl
+	}
+
+	return templates, errors.Join(errs...)
+}
+
+// renderInvalidationPattern renders the ModelInvalidationPatterns template of
+// tableName with db, reporting false if there is none, it fails to render or
+// it renders a missing value (e.g. a context value that is not set)
+func (c *Config) renderInvalidationPattern(db *gorm.DB, tableName string) (string, bool) {
+	tmpl, ok := c.invalidationTemplates[tableName]
+	if !ok {
+		return "", false
+	}
+
+	var pattern strings.Builder
+	// 渲染失败或取到空值时退回默认模式，宁可多删也不能漏删
+	if err := tmpl.Execute(&pattern, db); err != nil || pattern.Len() == 0 ||
+		strings.Contains(pattern.String(), "<no value>") {
+		return "", false
+	}
+	return c.keyPrefix() + c.keyNamespace(db.Statement.Context) + pattern.String(), true
+}
@@ -0,0 +1,111 @@
+package gormcache
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// withTenant returns a context carrying tenantID under the string key used by
+// the invalidation pattern template
+func withTenant(tenantID int) context.Context {
+	return context.WithValue(context.Background(), "tenant_id", tenantID)
+}
+
+func TestModelInvalidationPatterns(t *testing.T) {
+	db := setupTestDB(t)
+
+	cachePlugin := New(Config{
+		Adapter: NewMemoryAdapter(),
+		TTL:     5 * time.Minute,
+		CacheKeyGenerator: func(db *gorm.DB) string {
+			tenantID := db.Statement.Context.Value("tenant_id")
+			query := strings.ReplaceAll(db.Statement.SQL.String(), "/", "")
+			return fmt.Sprintf("tenant_%v:users:%s:%v", tenantID, query, db.Statement.Vars)
+		},
+		InvalidateOnUpdate: true,
+		ModelInvalidationPatterns: map[string]string{
+			"test_users": `tenant_{{.Statement.Context.Value "tenant_id"}}:users:*`,
+		},
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	db.Create(&TestUser{Name: "John"})
+
+	queries := countQueries(t, db)
+	run := func(tenantID int) {
+		var users []TestUser
+		db.WithContext(withTenant(tenantID)).Find(&users)
+	}
+
+	run(1)
+	run(2)
+	run(1)
+	run(2)
+	if *queries != 2 {
+		t.Fatalf("expected 2 database queries, got %d", *queries)
+	}
+
+	// Only the queries of tenant 1 are invalidated
+	db.WithContext(withTenant(1)).Model(&TestUser{}).Where("id = ?", 1).Update("name", "Jane")
+
+	run(1)
+	if *queries != 3 {
+		t.Errorf("expected the tenant 1 query to be refetched, got %d database queries", *queries)
+	}
+	run(2)
+	if *queries != 3 {
+		t.Errorf("expected the tenant 2 query to stay cached, got %d database queries", *queries)
+	}
+}
+
+func TestModelInvalidationPatternsFallback(t *testing.T) {
+	db := setupTestDB(t)
+
+	cachePlugin := New(Config{
+		Adapter:            NewMemoryAdapter(),
+		TTL:                5 * time.Minute,
+		InvalidateOnUpdate: true,
+		ModelInvalidationPatterns: map[string]string{
+			"test_users": `tenant_{{.Statement.Context.Value "tenant_id"}}:users:*`,
+		},
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	db.Create(&TestUser{Name: "John"})
+
+	queries := countQueries(t, db)
+	var users []TestUser
+	db.Find(&users)
+
+	// Without a tenant in the context, the default test_users pattern is used
+	db.Model(&TestUser{}).Where("id = ?", 1).Update("name", "Jane")
+
+	db.Find(&users)
+	if *queries != 2 {
+		t.Errorf("expected the query to be refetched, got %d database queries", *queries)
+	}
+}
+
+func TestModelInvalidationPatternsInvalidTemplate(t *testing.T) {
+	_, err := NewChecked(Config{
+		Adapter: NewMemoryAdapter(),
+		TTL:     5 * time.Minute,
+		ModelInvalidationPatterns: map[string]string{
+			"test_users": `tenant_{{.Statement.Context`,
+		},
+	})
+	if err == nil || !strings.Contains(err.Error(), "ModelInvalidationPatterns") {
+		t.Errorf("expected invalid template error, got %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package gormcache
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestSkipCacheForIsolationLevel(t *testing.T) {
+	db := setupTestDB(t)
+
+	// Keep the in-memory database on a single connection so transactions see it
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+
+	adapter := NewMemoryAdapter()
+	cachePlugin := New(Config{
+		Adapter:                    adapter,
+		TTL:                        5 * time.Minute,
+		SkipCacheForIsolationLevel: sql.LevelRepeatableRead,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	user := TestUser{Name: "Isolated"}
+	db.Create(&user)
+
+	queries := countQueries(t, db)
+
+	// Serializable transactions always reach the database
+	tx := BeginTx(db, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if tx.Error != nil {
+		t.Fatalf("failed to begin transaction: %v", tx.Error)
+	}
+	for i := 0; i < 2; i++ {
+		var result TestUser
+		if err := tx.First(&result, user.ID).Error; err != nil {
+			t.Fatalf("query failed: %v", err)
+		}
+	}
+	tx.Commit()
+
+	if *queries != 2 {
+		t.Errorf("expected 2 database queries, got %d", *queries)
+	}
+	if len(adapter.store) != 0 {
+		t.Errorf("expected no cached entries, got %d", len(adapter.store))
+	}
+
+	// Transactions below the threshold are cached as usual
+	tx = BeginTx(db, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if tx.Error != nil {
+		t.Fatalf("failed to begin transaction: %v", tx.Error)
+	}
+	for i := 0; i < 2; i++ {
+		var result TestUser
+		tx.First(&result, user.ID)
+	}
+	tx.Commit()
+
+	if *queries != 3 {
+		t.Errorf("expected the second read committed query to be cached, got %d database queries", *queries)
+	}
+}
+
+func TestSkipCacheInTransaction(t *testing.T) {
+	db := setupTestDB(t)
+
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+
+	cachePlugin := New(Config{
+		Adapter:                NewMemoryAdapter(),
+		TTL:                    5 * time.Minute,
+		SkipCacheInTransaction: true,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	db.Create(&TestUser{Name: "Committed"})
+
+	var cached []TestUser
+	db.Find(&cached)
+
+	queries := countQueries(t, db)
+
+	tx := db.Begin()
+	if tx.Error != nil {
+		t.Fatalf("failed to begin transaction: %v", tx.Error)
+	}
+	tx.Create(&TestUser{Name: "Uncommitted"})
+
+	// 事务内的查询不读取缓存，能看到本事务插入的数据
+	var users []TestUser
+	if err := tx.Find(&users).Error; err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if len(users) != 2 {
+		t.Errorf("expected the transaction to see 2 users, got %d", len(users))
+	}
+	if *queries != 1 {
+		t.Errorf("expected the query to reach the database, got %d database queries", *queries)
+	}
+	tx.Rollback()
+
+	// The transaction did not overwrite the cached entry with uncommitted data
+	var after []TestUser
+	db.Find(&after)
+	if *queries != 1 || len(after) != 1 {
+		t.Errorf("expected the entry cached before the transaction, got %d users and %d database queries", len(after), *queries)
+	}
+}
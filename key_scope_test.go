@@ -0,0 +1,77 @@
+package gormcache
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type tenantKey struct{}
+
+func TestCacheKeyScopeFunc(t *testing.T) {
+	db := setupTestDB(t)
+
+	adapter := NewMemoryAdapter()
+	cachePlugin := New(Config{
+		Adapter:            adapter,
+		TTL:                5 * time.Minute,
+		InvalidateOnUpdate: true,
+		CacheKey_ScopeFunc: func(db *gorm.DB) string {
+			if tenant, ok := db.Statement.Context.Value(tenantKey{}).(string); ok {
+				return "tenant:" + tenant
+			}
+			return ""
+		},
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	user := TestUser{Name: "Shared"}
+	db.Create(&user)
+
+	tenant1 := db.WithContext(context.WithValue(context.Background(), tenantKey{}, "1"))
+	tenant2 := db.WithContext(context.WithValue(context.Background(), tenantKey{}, "2"))
+
+	queries := countQueries(t, db)
+	first := func(tx *gorm.DB) {
+		var result TestUser
+		tx.First(&result, user.ID)
+	}
+
+	// Each tenant gets its own cache entry for the same query
+	first(tenant1)
+	first(tenant2)
+	first(tenant1)
+	first(tenant2)
+	if *queries != 2 {
+		t.Fatalf("expected 2 database queries, got %d", *queries)
+	}
+
+	var scopes []string
+	for key := range adapter.store {
+		if !strings.HasPrefix(key, "gorm:cache:test_users:") {
+			t.Errorf("expected default key generation to be kept, got %q", key)
+		}
+		scopes = append(scopes, key[strings.LastIndex(key, ":tenant:"):])
+	}
+	if len(scopes) != 2 {
+		t.Fatalf("expected 2 cached entries, got %d", len(scopes))
+	}
+
+	// A write by tenant 1 only invalidates tenant 1's entries
+	tenant1.Model(&user).Update("Name", "Tenant 1")
+
+	first(tenant1)
+	if *queries != 3 {
+		t.Errorf("expected tenant 1 cache to be invalidated, got %d database queries", *queries)
+	}
+	first(tenant2)
+	if *queries != 3 {
+		t.Errorf("expected tenant 2 cache to be kept, got %d database queries", *queries)
+	}
+}
@@ -0,0 +1,35 @@
+package gormcache
+
+import (
+	"context"
+	"fmt"
+)
+
+// Keys returns the keys of the cached queries of tableName
+// The adapter must implement ScannableAdapter
+func (p *CachePlugin) Keys(ctx context.Context, tableName string) ([]string, error) {
+	keys, err := scanKeys(ctx, p.adapter(), p.config.tablePattern(ctx, tableName))
+	if err != nil {
+		return nil, err
+	}
+
+	// 只返回查询结果的 key，不包含元数据等附属键
+	entries := keys[:0]
+	for _, key := range keys {
+		if !isSidecarKey(key) {
+			entries = append(entries, key)
+		}
+	}
+	return entries, nil
+}
+
+// scanKeys lists the keys of adapter matching pattern, looking through
+// wrapping adapters
+func scanKeys(ctx context.Context, adapter Adapter, pattern string) ([]string, error) {
+	for _, a := range adapterChain(adapter) {
+		if scanner, ok := a.(ScannableAdapter); ok {
+			return scanner.Scan(ctx, pattern)
+		}
+	}
+	return nil, fmt.Errorf("gorm:cache: adapter %T does not implement ScannableAdapter", adapter)
+}
@@ -0,0 +1,79 @@
+package gormcache
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"gorm.io/gorm"
+)
+
+func testPluginKeys(t *testing.T, adapter Adapter) {
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&testOrder{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	cachePlugin := New(Config{
+		Adapter:          adapter,
+		TTL:              5 * time.Minute,
+		MaxQueryCacheAge: time.Minute,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	db.Create(&TestUser{Name: "John"})
+	db.Create(&testOrder{UserID: 1})
+
+	var user TestUser
+	first := db.First(&user, 1)
+	var users []TestUser
+	all := db.Find(&users)
+	var orders []testOrder
+	db.Find(&orders)
+
+	var expected []string
+	for _, tx := range []*gorm.DB{first, all} {
+		key, _ := tx.Statement.Settings.Load("gorm:cache:key")
+		expected = append(expected, key.(string))
+	}
+
+	keys, err := cachePlugin.Keys(context.Background(), "test_users")
+	if err != nil {
+		t.Fatalf("failed to list keys: %v", err)
+	}
+
+	// 元数据附属键和其他表的 key 不在结果中
+	sort.Strings(keys)
+	sort.Strings(expected)
+	if !reflect.DeepEqual(keys, expected) {
+		t.Errorf("expected keys %v, got %v", expected, keys)
+	}
+}
+
+func TestPluginKeys(t *testing.T) {
+	t.Run("memory", func(t *testing.T) {
+		testPluginKeys(t, NewMemoryAdapter())
+	})
+	t.Run("redis", func(t *testing.T) {
+		mr := miniredis.RunT(t)
+		testPluginKeys(t, NewRedisAdapter(RedisAdapterConfig{Addr: mr.Addr()}))
+	})
+	t.Run("wrapped", func(t *testing.T) {
+		// 通过 Unwrap 穿过任意层包装找到可扫描的适配器
+		adapter := NewMetricsAdapter(NewFingerprintAdapter(NewMemoryAdapter()), "gormcache_test_keys")
+		testPluginKeys(t, adapter)
+	})
+}
+
+func TestPluginKeysUnsupportedAdapter(t *testing.T) {
+	cachePlugin := New(Config{Adapter: failingAdapter{}, TTL: time.Minute})
+	if _, err := cachePlugin.Keys(context.Background(), "test_users"); err == nil {
+		t.Error("expected an error for an adapter without Scan")
+	}
+}
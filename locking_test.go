@@ -0,0 +1,76 @@
+package gormcache
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+func TestLockingClausesSkipCache(t *testing.T) {
+	db := setupTestDB(t)
+
+	cachePlugin := New(Config{
+		Adapter:               NewMemoryAdapter(),
+		TTL:                   5 * time.Minute,
+		SkipForLockingClauses: true,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	queries := countQueries(t, db)
+
+	user := TestUser{Name: "Test User"}
+	db.Create(&user)
+
+	for _, locking := range []clause.Locking{{Strength: "UPDATE"}, {Strength: "SHARE"}} {
+		before := atomic.LoadInt64(queries)
+		for i := 0; i < 2; i++ {
+			var u TestUser
+			if err := db.Clauses(locking).First(&u, user.ID).Error; err != nil {
+				t.Fatalf("failed to query: %v", err)
+			}
+		}
+		if got := atomic.LoadInt64(queries) - before; got != 2 {
+			t.Errorf("FOR %s: expected 2 database queries, got %d", locking.Strength, got)
+		}
+	}
+
+	// Plain queries are still cached
+	before := atomic.LoadInt64(queries)
+	for i := 0; i < 2; i++ {
+		var u TestUser
+		db.First(&u, user.ID)
+	}
+	if got := atomic.LoadInt64(queries) - before; got != 1 {
+		t.Errorf("expected 1 database query without locking, got %d", got)
+	}
+}
+
+func TestLockingClausesNotCached(t *testing.T) {
+	db := setupTestDB(t)
+
+	adapter := NewMemoryAdapter()
+	cachePlugin := New(Config{
+		Adapter:               adapter,
+		TTL:                   5 * time.Minute,
+		SkipForLockingClauses: true,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	user := TestUser{Name: "Test User"}
+	db.Create(&user)
+
+	var u TestUser
+	db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&u, user.ID)
+
+	if n := len(adapter.store); n != 0 {
+		t.Errorf("expected nothing cached for SELECT FOR UPDATE, got %d entries", n)
+	}
+}
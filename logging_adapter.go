@@ -0,0 +1,98 @@
+package gormcache
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// LoggingAdapter wraps an adapter and logs every operation with slog
+// Operations are logged at DEBUG level with the operation, key and duration
+// fields; Get also logs cache_hit. Failed operations are logged at WARN level
+// with the error
+type LoggingAdapter struct {
+	inner  Adapter
+	logger *slog.Logger
+}
+
+// NewLoggingAdapter creates a new logging adapter writing to logger
+// A nil logger uses slog.Default()
+func NewLoggingAdapter(inner Adapter, logger *slog.Logger) *LoggingAdapter {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &LoggingAdapter{inner: inner, logger: logger}
+}
+
+// log records operation, started at start, with err and the extra attributes
+func (a *LoggingAdapter) log(ctx context.Context, operation, key string, start time.Time, err error, attrs ...slog.Attr) {
+	attrs = append([]slog.Attr{
+		slog.String("operation", operation),
+		slog.String("key", key),
+		slog.Duration("duration", time.Since(start)),
+	}, attrs...)
+
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+		a.logger.LogAttrs(ctx, slog.LevelWarn, "gorm:cache: operation failed", attrs...)
+		return
+	}
+	a.logger.LogAttrs(ctx, slog.LevelDebug, "gorm:cache: operation", attrs...)
+}
+
+// Get retrieves a value from the inner adapter
+func (a *LoggingAdapter) Get(ctx context.Context, key string) ([]byte, error) {
+	start := time.Now()
+	value, err := a.inner.Get(ctx, key)
+
+	// 未命中是正常结果，按 DEBUG 记录
+	logged := err
+	if errors.Is(err, ErrCacheMiss) {
+		logged = nil
+	}
+	a.log(ctx, "Get", key, start, logged, slog.Bool("cache_hit", err == nil))
+	return value, err
+}
+
+// Set stores a value in the inner adapter
+func (a *LoggingAdapter) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	start := time.Now()
+	err := a.inner.Set(ctx, key, value, ttl)
+	a.log(ctx, "Set", key, start, err, slog.Duration("ttl", ttl))
+	return err
+}
+
+// Delete removes a value from the inner adapter
+func (a *LoggingAdapter) Delete(ctx context.Context, key string) error {
+	start := time.Now()
+	err := a.inner.Delete(ctx, key)
+	a.log(ctx, "Delete", key, start, err)
+	return err
+}
+
+// DeletePattern removes all keys matching the pattern from the inner adapter
+func (a *LoggingAdapter) DeletePattern(ctx context.Context, pattern string) error {
+	start := time.Now()
+	err := a.inner.DeletePattern(ctx, pattern)
+	a.log(ctx, "DeletePattern", pattern, start, err)
+	return err
+}
+
+// Clear removes all cached data from the inner adapter
+func (a *LoggingAdapter) Clear(ctx context.Context) error {
+	start := time.Now()
+	err := a.inner.Clear(ctx)
+	a.log(ctx, "Clear", "", start, err)
+	return err
+}
+
+// Close closes the inner adapter
+func (a *LoggingAdapter) Close() error {
+	return a.inner.Close()
+}
+
+// Unwrap returns the inner adapter
+func (a *LoggingAdapter) Unwrap() Adapter {
+	return a.inner
+}
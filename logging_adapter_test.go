@@ -0,0 +1,80 @@
+package gormcache
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestLogger(buf *bytes.Buffer) *slog.Logger {
+	return slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+}
+
+func TestLoggingAdapter(t *testing.T) {
+	var buf bytes.Buffer
+	adapter := NewLoggingAdapter(NewMemoryAdapter(), newTestLogger(&buf))
+	defer adapter.Close()
+
+	ctx := context.Background()
+	adapter.Get(ctx, "users:1")
+	adapter.Set(ctx, "users:1", []byte("john"), time.Minute)
+	adapter.Get(ctx, "users:1")
+	adapter.Delete(ctx, "users:1")
+	adapter.DeletePattern(ctx, "users:*")
+	adapter.Clear(ctx)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 6 {
+		t.Fatalf("expected 6 log lines, got %d:\n%s", len(lines), buf.String())
+	}
+
+	expected := []string{
+		"operation=Get key=users:1",
+		"operation=Set key=users:1",
+		"operation=Get key=users:1",
+		"operation=Delete key=users:1",
+		"operation=DeletePattern key=users:*",
+		"operation=Clear",
+	}
+	for i, want := range expected {
+		if !strings.Contains(lines[i], "level=DEBUG") || !strings.Contains(lines[i], want) {
+			t.Errorf("line %d: expected DEBUG line containing %q, got %q", i, want, lines[i])
+		}
+		if !strings.Contains(lines[i], "duration=") {
+			t.Errorf("line %d: expected duration field, got %q", i, lines[i])
+		}
+	}
+
+	if !strings.Contains(lines[0], "cache_hit=false") {
+		t.Errorf("expected miss to log cache_hit=false, got %q", lines[0])
+	}
+	if !strings.Contains(lines[2], "cache_hit=true") {
+		t.Errorf("expected hit to log cache_hit=true, got %q", lines[2])
+	}
+}
+
+func TestLoggingAdapterErrors(t *testing.T) {
+	var buf bytes.Buffer
+	adapter := NewLoggingAdapter(failingAdapter{}, newTestLogger(&buf))
+
+	ctx := context.Background()
+	if _, err := adapter.Get(ctx, "users:1"); err == nil {
+		t.Fatal("expected Get to fail")
+	}
+	if err := adapter.Set(ctx, "users:1", []byte("john"), time.Minute); err == nil {
+		t.Fatal("expected Set to fail")
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 log lines, got %d:\n%s", len(lines), buf.String())
+	}
+	for i, line := range lines {
+		if !strings.Contains(line, "level=WARN") || !strings.Contains(line, "error=") {
+			t.Errorf("line %d: expected WARN line with the error, got %q", i, line)
+		}
+	}
+}
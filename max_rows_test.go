@@ -0,0 +1,92 @@
+package gormcache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func TestMaxCacheableRows(t *testing.T) {
+	db := setupTestDB(t)
+
+	cachePlugin := New(Config{
+		Adapter:          NewMemoryAdapter(),
+		TTL:              5 * time.Minute,
+		MaxCacheableRows: 2,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	for _, name := range []string{"John", "Jane", "Bob"} {
+		db.Create(&TestUser{Name: name})
+	}
+
+	queries := countQueries(t, db)
+
+	// Three rows exceed the limit and are not cached
+	var users []TestUser
+	db.Find(&users)
+	if len(users) != 3 {
+		t.Fatalf("expected 3 users, got %d", len(users))
+	}
+
+	// Invalidation is off, so only a result that was never cached reads the delete
+	db.Delete(&TestUser{}, users[2].ID)
+
+	users = nil
+	db.Find(&users)
+	if *queries != 2 {
+		t.Errorf("expected the second query to reach the database, got %d database queries", *queries)
+	}
+	if len(users) != 2 {
+		t.Errorf("expected 2 users after the delete, got %d", len(users))
+	}
+
+	// Two rows are within the limit and are cached
+	db.Find(&users)
+	if *queries != 2 {
+		t.Errorf("expected results within the limit to be cached, got %d database queries", *queries)
+	}
+}
+
+func TestCachePredicateFunc(t *testing.T) {
+	for _, singleflight := range []bool{false, true} {
+		t.Run(fmt.Sprintf("singleflight=%v", singleflight), func(t *testing.T) {
+			db := setupTestDB(t)
+
+			cachePlugin := New(Config{
+				Adapter:             NewMemoryAdapter(),
+				TTL:                 5 * time.Minute,
+				SingleflightEnabled: singleflight,
+				// 只缓存单行结果
+				CachePredicateFunc: func(db *gorm.DB, rowsAffected int64) bool {
+					return rowsAffected == 1
+				},
+			})
+			if err := db.Use(cachePlugin); err != nil {
+				t.Fatalf("failed to install plugin: %v", err)
+			}
+			defer cachePlugin.Close()
+
+			for _, name := range []string{"John", "Jane", "Jane"} {
+				db.Create(&TestUser{Name: name})
+			}
+			queries := countQueries(t, db)
+
+			for name, wantCached := range map[string]bool{"Nobody": false, "John": true, "Jane": false} {
+				var users []TestUser
+				db.Where("name = ?", name).Find(&users)
+				before := *queries
+
+				db.Where("name = ?", name).Find(&users)
+				if cached := *queries == before; cached != wantCached {
+					t.Errorf("%s: expected cached = %v with %d rows", name, wantCached, len(users))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,352 @@
+package gormcache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+const (
+	// memcachedKeyIndex is the prefix of the sentinel keys holding the keys
+	// stored through a MemcachedAdapter, used by DeletePattern; it lies outside
+	// the default KeyPrefix so invalidation patterns never match it
+	memcachedKeyIndex = "gorm:memcached:keys:"
+
+	// memcachedIndexBuckets is the number of index keys the stored keys are
+	// spread over, keeping each one small
+	memcachedIndexBuckets = 64
+
+	// memcachedCompactEvery is the number of appends to a bucket after which
+	// this process rewrites it without expired and duplicate entries
+	memcachedCompactEvery = 256
+
+	// memcachedMaxRelativeTTL is the longest expiration memcached accepts as a
+	// relative number of seconds; longer ones must be absolute Unix timestamps
+	memcachedMaxRelativeTTL = 30 * 24 * time.Hour
+
+	// memcachedIndexRetries bounds the compare-and-swap attempts of index rewrites
+	memcachedIndexRetries = 10
+)
+
+// memcacheClient is the subset of *memcache.Client used by MemcachedAdapter
+type memcacheClient interface {
+	Get(key string) (*memcache.Item, error)
+	GetMulti(keys []string) (map[string]*memcache.Item, error)
+	Set(item *memcache.Item) error
+	Add(item *memcache.Item) error
+	Append(item *memcache.Item) error
+	CompareAndSwap(item *memcache.Item) error
+	Delete(key string) error
+	FlushAll() error
+	Ping() error
+	Close() error
+}
+
+// MemcachedAdapter is a Memcached cache implementation
+// Memcached cannot list its keys, so the adapter records each key it stores,
+// with its expiration, in one of memcachedIndexBuckets index keys before
+// storing the value; DeletePattern scans them. Entries are appended, and
+// expired or duplicate ones are dropped when a bucket is rewritten
+type MemcachedAdapter struct {
+	client memcacheClient
+
+	// appends counts the appends to each bucket since it was last compacted
+	appends [memcachedIndexBuckets]atomic.Int64
+}
+
+// MemcachedAdapterConfig holds configuration for Memcached adapter
+type MemcachedAdapterConfig struct {
+	Servers      []string // Memcached server addresses (default: ["localhost:11211"])
+	MaxIdleConns int      // Maximum idle connections per server (default: 2)
+}
+
+// NewMemcachedAdapter creates a new Memcached cache adapter
+func NewMemcachedAdapter(config MemcachedAdapterConfig) *MemcachedAdapter {
+	if len(config.Servers) == 0 {
+		config.Servers = []string{"localhost:11211"}
+	}
+
+	client := memcache.New(config.Servers...)
+	client.MaxIdleConns = config.MaxIdleConns
+
+	return &MemcachedAdapter{
+		client: client,
+	}
+}
+
+// NewMemcachedAdapterWithClient creates a new Memcached adapter with existing client
+func NewMemcachedAdapterWithClient(client *memcache.Client) *MemcachedAdapter {
+	return &MemcachedAdapter{
+		client: client,
+	}
+}
+
+// Get retrieves a value from Memcached cache
+func (m *MemcachedAdapter) Get(ctx context.Context, key string) ([]byte, error) {
+	item, err := m.client.Get(key)
+	if errors.Is(err, memcache.ErrCacheMiss) {
+		return nil, fmt.Errorf("%w: %w", ErrCacheMiss, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return item.Value, nil
+}
+
+// Set stores a value in Memcached cache
+// The key is indexed first, so a value is never stored without being visible
+// to DeletePattern
+func (m *MemcachedAdapter) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := m.indexKey(key, ttl); err != nil {
+		return err
+	}
+
+	return m.client.Set(&memcache.Item{
+		Key:        key,
+		Value:      value,
+		Expiration: memcachedExpiration(ttl),
+	})
+}
+
+// Delete removes a value from Memcached cache
+// Its index entry is dropped the next time its bucket is rewritten
+func (m *MemcachedAdapter) Delete(ctx context.Context, key string) error {
+	if err := m.client.Delete(key); err != nil && !errors.Is(err, memcache.ErrCacheMiss) {
+		return err
+	}
+	return nil
+}
+
+// DeletePattern removes all indexed keys matching the pattern
+func (m *MemcachedAdapter) DeletePattern(ctx context.Context, pattern string) error {
+	buckets := make([]string, memcachedIndexBuckets)
+	for i := range buckets {
+		buckets[i] = memcachedBucketKey(i)
+	}
+	items, err := m.client.GetMulti(buckets)
+	if err != nil {
+		return err
+	}
+
+	for _, bucket := range buckets {
+		item, ok := items[bucket]
+		if !ok || !bucketMatches(item.Value, pattern) {
+			continue
+		}
+
+		var matched []string
+		err := m.rewriteBucket(bucket, func(entries []indexEntry) []indexEntry {
+			matched = matched[:0]
+			return removeEntries(entries, func(e indexEntry) bool {
+				if matchPattern(pattern, e.key) {
+					matched = append(matched, e.key)
+					return true
+				}
+				return false
+			})
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, key := range matched {
+			if err := m.client.Delete(key); err != nil && !errors.Is(err, memcache.ErrCacheMiss) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Clear removes all data from the Memcached servers
+func (m *MemcachedAdapter) Clear(ctx context.Context) error {
+	return m.client.FlushAll()
+}
+
+// Ping checks that all Memcached servers are reachable
+func (m *MemcachedAdapter) Ping(ctx context.Context) error {
+	return m.client.Ping()
+}
+
+// Close closes the Memcached connections
+func (m *MemcachedAdapter) Close() error {
+	return m.client.Close()
+}
+
+// indexEntry is an entry of a key index bucket
+type indexEntry struct {
+	key       string
+	expiresAt int64 // Unix seconds, 0 if the key never expires
+}
+
+// memcachedBucketKey returns the index key of bucket i
+func memcachedBucketKey(i int) string {
+	return memcachedKeyIndex + strconv.Itoa(i)
+}
+
+// memcachedBucket returns the index bucket of key
+func memcachedBucket(key string) int {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int(h.Sum32() % memcachedIndexBuckets)
+}
+
+// indexKey appends key to its index bucket, compacting the bucket every
+// memcachedCompactEvery appends or when it no longer accepts appends
+func (m *MemcachedAdapter) indexKey(key string, ttl time.Duration) error {
+	i := memcachedBucket(key)
+	bucket := memcachedBucketKey(i)
+
+	var expiresAt int64
+	if ttl > 0 {
+		// 向上取整，索引条目不早于值过期
+		expiresAt = time.Now().Add(ttl + time.Second - 1).Unix()
+	}
+	entry := encodeEntries([]indexEntry{{key: key, expiresAt: expiresAt}})
+
+	if m.appends[i].Add(1) >= memcachedCompactEvery {
+		m.appends[i].Store(0)
+		if err := m.rewriteBucket(bucket, nil); err != nil {
+			return err
+		}
+	}
+
+	err := m.appendEntry(bucket, entry)
+	if err == nil || errors.Is(err, memcache.ErrNotStored) {
+		return err
+	}
+
+	// 超过条目大小上限等错误时先压缩再重试一次
+	if cerr := m.rewriteBucket(bucket, nil); cerr != nil {
+		return errors.Join(err, cerr)
+	}
+	return m.appendEntry(bucket, entry)
+}
+
+// appendEntry appends entry to bucket, creating the bucket if needed
+func (m *MemcachedAdapter) appendEntry(bucket string, entry []byte) error {
+	for i := 0; i < memcachedIndexRetries; i++ {
+		err := m.client.Append(&memcache.Item{Key: bucket, Value: entry})
+		if !errors.Is(err, memcache.ErrNotStored) {
+			return err
+		}
+		// 桶不存在时 Append 失败，用 Add 创建；并发创建时重新追加
+		err = m.client.Add(&memcache.Item{Key: bucket, Value: entry})
+		if !errors.Is(err, memcache.ErrNotStored) {
+			return err
+		}
+	}
+	return memcache.ErrNotStored
+}
+
+// rewriteBucket rewrites bucket with compare-and-swap, without expired and
+// duplicate entries, and without those removed by fn if it is not nil, so
+// that concurrent writers (possibly in other processes) do not lose updates
+func (m *MemcachedAdapter) rewriteBucket(bucket string, fn func([]indexEntry) []indexEntry) error {
+	for i := 0; i < memcachedIndexRetries; i++ {
+		item, err := m.client.Get(bucket)
+		if errors.Is(err, memcache.ErrCacheMiss) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		entries := compactEntries(decodeEntries(item.Value), time.Now().Unix())
+		if fn != nil {
+			entries = fn(entries)
+		}
+		item.Value = encodeEntries(entries)
+		err = m.client.CompareAndSwap(item)
+		if errors.Is(err, memcache.ErrCASConflict) || errors.Is(err, memcache.ErrNotStored) {
+			continue
+		}
+		return err
+	}
+	return errors.New("gorm:cache: memcached key index is updated concurrently, giving up")
+}
+
+// memcachedExpiration converts ttl to a memcached expiration
+func memcachedExpiration(ttl time.Duration) int32 {
+	switch {
+	case ttl <= 0:
+		return 0
+	case ttl < time.Second:
+		// 0 表示永不过期，不足一秒按一秒处理
+		return 1
+	case ttl > memcachedMaxRelativeTTL:
+		return int32(time.Now().Add(ttl).Unix())
+	}
+	return int32(ttl / time.Second)
+}
+
+// bucketMatches reports whether the encoded bucket may hold a key matching
+// pattern, to skip rewriting buckets without any
+func bucketMatches(data []byte, pattern string) bool {
+	for _, entry := range decodeEntries(data) {
+		if matchPattern(pattern, entry.key) {
+			return true
+		}
+	}
+	return false
+}
+
+// compactEntries drops the entries expired at now and all but the last entry
+// of each key
+func compactEntries(entries []indexEntry, now int64) []indexEntry {
+	last := make(map[string]int, len(entries))
+	for i, e := range entries {
+		last[e.key] = i
+	}
+	return removeEntriesAt(entries, func(i int, e indexEntry) bool {
+		return last[e.key] != i || (e.expiresAt != 0 && e.expiresAt <= now)
+	})
+}
+
+// removeEntries returns entries without the ones for which remove returns true
+func removeEntries(entries []indexEntry, remove func(indexEntry) bool) []indexEntry {
+	return removeEntriesAt(entries, func(_ int, e indexEntry) bool { return remove(e) })
+}
+
+func removeEntriesAt(entries []indexEntry, remove func(int, indexEntry) bool) []indexEntry {
+	remaining := make([]indexEntry, 0, len(entries))
+	for i, e := range entries {
+		if !remove(i, e) {
+			remaining = append(remaining, e)
+		}
+	}
+	return remaining
+}
+
+// decodeEntries parses the "key\texpiresAt\n" lines of a bucket; memcached
+// keys cannot contain whitespace, so the separators never occur in keys
+func decodeEntries(data []byte) []indexEntry {
+	var entries []indexEntry
+	for _, line := range strings.Split(string(data), "\n") {
+		key, expiresAt, ok := strings.Cut(line, "\t")
+		if !ok || key == "" {
+			continue
+		}
+		at, _ := strconv.ParseInt(expiresAt, 10, 64)
+		entries = append(entries, indexEntry{key: key, expiresAt: at})
+	}
+	return entries
+}
+
+func encodeEntries(entries []indexEntry) []byte {
+	var b strings.Builder
+	for _, e := range entries {
+		b.WriteString(e.key)
+		b.WriteByte('\t')
+		b.WriteString(strconv.FormatInt(e.expiresAt, 10))
+		b.WriteByte('\n')
+	}
+	return []byte(b.String())
+}
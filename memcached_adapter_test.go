@@ -0,0 +1,355 @@
+package gormcache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+// fakeMemcache is an in-memory memcacheClient with compare-and-swap support
+type fakeMemcache struct {
+	mu       sync.Mutex
+	items    map[string][]byte
+	versions map[string]uint64
+	expiries map[string]int32
+
+	// fetched records the version of each item returned by Get, for CompareAndSwap
+	fetched map[*memcache.Item]uint64
+	closed  bool
+	flushes int
+
+	// maxItemSize makes Append fail beyond this many bytes, like the memcached
+	// item size limit; 0 disables it
+	maxItemSize int
+}
+
+// errItemTooLarge is returned by fakeMemcache.Append past maxItemSize
+var errItemTooLarge = errors.New("memcache: server error: object too large for cache")
+
+// indexedKeys returns the live keys of all index buckets of client
+func indexedKeys(client *fakeMemcache) []string {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	var keys []string
+	for i := 0; i < memcachedIndexBuckets; i++ {
+		for _, e := range compactEntries(decodeEntries(client.items[memcachedBucketKey(i)]), time.Now().Unix()) {
+			keys = append(keys, e.key)
+		}
+	}
+	return keys
+}
+
+func newFakeMemcache() *fakeMemcache {
+	return &fakeMemcache{
+		items:    make(map[string][]byte),
+		versions: make(map[string]uint64),
+		expiries: make(map[string]int32),
+		fetched:  make(map[*memcache.Item]uint64),
+	}
+}
+
+func (f *fakeMemcache) Get(key string) (*memcache.Item, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	value, ok := f.items[key]
+	if !ok {
+		return nil, memcache.ErrCacheMiss
+	}
+	item := &memcache.Item{Key: key, Value: append([]byte(nil), value...)}
+	f.fetched[item] = f.versions[key]
+	return item, nil
+}
+
+func (f *fakeMemcache) GetMulti(keys []string) (map[string]*memcache.Item, error) {
+	items := make(map[string]*memcache.Item)
+	for _, key := range keys {
+		if item, err := f.Get(key); err == nil {
+			items[key] = item
+		}
+	}
+	return items, nil
+}
+
+func (f *fakeMemcache) Append(item *memcache.Item) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	value, ok := f.items[item.Key]
+	if !ok {
+		return memcache.ErrNotStored
+	}
+	if f.maxItemSize > 0 && len(value)+len(item.Value) > f.maxItemSize {
+		return errItemTooLarge
+	}
+	f.items[item.Key] = append(value, item.Value...)
+	f.versions[item.Key]++
+	return nil
+}
+
+func (f *fakeMemcache) Set(item *memcache.Item) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.store(item)
+	return nil
+}
+
+func (f *fakeMemcache) Add(item *memcache.Item) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.items[item.Key]; ok {
+		return memcache.ErrNotStored
+	}
+	f.store(item)
+	return nil
+}
+
+func (f *fakeMemcache) CompareAndSwap(item *memcache.Item) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	version, ok := f.fetched[item]
+	if !ok {
+		return memcache.ErrNotStored
+	}
+	delete(f.fetched, item)
+	if _, exists := f.items[item.Key]; !exists {
+		return memcache.ErrNotStored
+	}
+	if f.versions[item.Key] != version {
+		return memcache.ErrCASConflict
+	}
+	f.store(item)
+	return nil
+}
+
+func (f *fakeMemcache) Delete(key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.items[key]; !ok {
+		return memcache.ErrCacheMiss
+	}
+	delete(f.items, key)
+	delete(f.expiries, key)
+	return nil
+}
+
+func (f *fakeMemcache) FlushAll() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.items = make(map[string][]byte)
+	f.expiries = make(map[string]int32)
+	f.flushes++
+	return nil
+}
+
+func (f *fakeMemcache) Ping() error { return nil }
+
+func (f *fakeMemcache) Close() error {
+	f.closed = true
+	return nil
+}
+
+func (f *fakeMemcache) store(item *memcache.Item) {
+	f.items[item.Key] = append([]byte(nil), item.Value...)
+	f.versions[item.Key]++
+	f.expiries[item.Key] = item.Expiration
+}
+
+func TestMemcachedAdapterBasic(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeMemcache()
+	adapter := &MemcachedAdapter{client: client}
+
+	if err := adapter.Set(ctx, "gorm:cache:users:1", []byte("alice"), 5*time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	value, err := adapter.Get(ctx, "gorm:cache:users:1")
+	if err != nil || string(value) != "alice" {
+		t.Fatalf("expected alice, got %q (error: %v)", value, err)
+	}
+	if client.expiries["gorm:cache:users:1"] != 300 {
+		t.Errorf("expected a 300s expiration, got %d", client.expiries["gorm:cache:users:1"])
+	}
+
+	if err := adapter.Delete(ctx, "gorm:cache:users:1"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := adapter.Get(ctx, "gorm:cache:users:1"); err == nil {
+		t.Error("expected a miss after Delete")
+	}
+	if err := adapter.Delete(ctx, "gorm:cache:users:1"); err != nil {
+		t.Errorf("expected deleting a missing key to succeed, got %v", err)
+	}
+
+	if err := adapter.Clear(ctx); err != nil || client.flushes != 1 {
+		t.Errorf("expected Clear to flush the servers, got %v", err)
+	}
+	if err := adapter.Close(); err != nil || !client.closed {
+		t.Errorf("expected Close to close the client, got %v", err)
+	}
+}
+
+func TestMemcachedAdapterDeletePattern(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeMemcache()
+	adapter := &MemcachedAdapter{client: client}
+
+	for _, key := range []string{"gorm:cache:users:1", "gorm:cache:users:2", "gorm:cache:orders:1"} {
+		if err := adapter.Set(ctx, key, []byte("value"), time.Minute); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+	}
+	// Setting a key again does not duplicate it in the index
+	_ = adapter.Set(ctx, "gorm:cache:users:1", []byte("value"), time.Minute)
+
+	if err := adapter.DeletePattern(ctx, "gorm:cache:users:*"); err != nil {
+		t.Fatalf("DeletePattern failed: %v", err)
+	}
+
+	for _, key := range []string{"gorm:cache:users:1", "gorm:cache:users:2"} {
+		if _, err := adapter.Get(ctx, key); err == nil {
+			t.Errorf("expected %s to be deleted", key)
+		}
+	}
+	if _, err := adapter.Get(ctx, "gorm:cache:orders:1"); err != nil {
+		t.Errorf("expected gorm:cache:orders:1 to be kept, got %v", err)
+	}
+	if keys := indexedKeys(client); len(keys) != 1 || keys[0] != "gorm:cache:orders:1" {
+		t.Errorf("expected only the remaining key in the index, got %q", keys)
+	}
+}
+
+func TestMemcachedAdapterConcurrentIndex(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeMemcache()
+	adapter := &MemcachedAdapter{client: client}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := "gorm:cache:users:" + string(rune('a'+i))
+			if err := adapter.Set(ctx, key, []byte("value"), time.Minute); err != nil {
+				t.Errorf("Set failed: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if keys := indexedKeys(client); len(keys) != 5 {
+		t.Errorf("expected 5 indexed keys, got %v", keys)
+	}
+}
+
+func TestMemcachedAdapterCompactsIndex(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeMemcache()
+	client.maxItemSize = 512
+	adapter := &MemcachedAdapter{client: client}
+
+	// 过期条目在桶写满时被清理，Set 不会因为索引过大而失败
+	for i := 0; i < 2000; i++ {
+		key := fmt.Sprintf("gorm:cache:users:%d", i)
+		bucket := memcachedBucketKey(memcachedBucket(key))
+		client.mu.Lock()
+		client.items[bucket] = append(client.items[bucket], encodeEntries([]indexEntry{{key: key, expiresAt: 1}})...)
+		client.mu.Unlock()
+	}
+
+	for i := 0; i < 200; i++ {
+		key := fmt.Sprintf("gorm:cache:orders:%d", i)
+		if err := adapter.Set(ctx, key, []byte("value"), time.Minute); err != nil {
+			t.Fatalf("Set %s failed: %v", key, err)
+		}
+	}
+	if keys := indexedKeys(client); len(keys) != 200 {
+		t.Errorf("expected the 200 live keys in the index, got %d", len(keys))
+	}
+
+	var raw int
+	for i := 0; i < memcachedIndexBuckets; i++ {
+		raw += len(decodeEntries(client.items[memcachedBucketKey(i)]))
+	}
+	if raw != 200 {
+		t.Errorf("expected the expired entries to be dropped from the buckets, got %d entries", raw)
+	}
+}
+
+func TestMemcachedAdapterIndexFailure(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeMemcache()
+	client.maxItemSize = 64
+	adapter := &MemcachedAdapter{client: client}
+
+	// 桶中的有效条目已达到大小上限，压缩后仍无法追加
+	key := "gorm:cache:users:1"
+	bucket := memcachedBucketKey(memcachedBucket(key))
+	client.items[bucket] = encodeEntries([]indexEntry{{key: "gorm:cache:users:live:0123456789012345678901234567890123456789"}})
+
+	if err := adapter.Set(ctx, key, []byte("value"), time.Minute); err == nil {
+		t.Fatal("expected Set to fail when the index cannot be updated")
+	}
+	// 索引写入失败时不能留下 DeletePattern 无法删除的值
+	if _, err := adapter.Get(ctx, key); err == nil {
+		t.Error("expected the value not to be stored without an index entry")
+	}
+}
+
+func TestMemcachedExpiration(t *testing.T) {
+	if got := memcachedExpiration(0); got != 0 {
+		t.Errorf("expected 0 for no TTL, got %d", got)
+	}
+	if got := memcachedExpiration(100 * time.Millisecond); got != 1 {
+		t.Errorf("expected sub-second TTLs to round up to 1, got %d", got)
+	}
+	if got := memcachedExpiration(time.Hour); got != 3600 {
+		t.Errorf("expected 3600, got %d", got)
+	}
+
+	// Longer than 30 days must be an absolute timestamp
+	ttl := 60 * 24 * time.Hour
+	if got := int64(memcachedExpiration(ttl)); got < time.Now().Add(ttl).Unix()-1 {
+		t.Errorf("expected an absolute timestamp, got %d", got)
+	}
+}
+
+func TestMemcachedAdapterWithPlugin(t *testing.T) {
+	db := setupTestDB(t)
+
+	cachePlugin := New(Config{
+		Adapter:            &MemcachedAdapter{client: newFakeMemcache()},
+		TTL:                5 * time.Minute,
+		InvalidateOnCreate: true,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+
+	db.Create(&TestUser{Name: "Memcached"})
+	queries := countQueries(t, db)
+
+	var users []TestUser
+	db.Find(&users)
+	db.Find(&users)
+	if *queries != 1 {
+		t.Errorf("expected the second query to be cached, got %d database queries", *queries)
+	}
+
+	db.Create(&TestUser{Name: "Invalidating"})
+	db.Find(&users)
+	if *queries != 2 || len(users) != 2 {
+		t.Errorf("expected the write to invalidate the cache, got %d queries and %d users", *queries, len(users))
+	}
+}
@@ -2,7 +2,7 @@ package gormcache
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"strings"
 	"sync"
 	"time"
@@ -13,20 +13,110 @@ type cacheItem struct {
 	expiration time.Time
 }
 
+// MemoryAdapterBackend selects the storage structure used by MemoryAdapter
+type MemoryAdapterBackend int
+
+const (
+	// BackendMap stores entries in a map guarded by a sync.RWMutex (default)
+	BackendMap MemoryAdapterBackend = iota
+	// BackendSyncMap stores entries in a sync.Map, which performs better
+	// under read-heavy concurrent workloads
+	BackendSyncMap
+)
+
+// MemoryAdapterConfig holds configuration for the in-memory adapter
+type MemoryAdapterConfig struct {
+	Backend         MemoryAdapterBackend // Storage backend (default: BackendMap)
+	MaxEntries      int                  // Maximum entries before eviction (default: 0, unlimited; BackendMap only)
+	Eviction        EvictionPolicy       // Entry evicted once MaxEntries is exceeded (default: EvictLRU)
+	CleanupInterval time.Duration        // Interval of expired entry removal (default: 1 minute)
+}
+
+// MemoryAdapterOption configures a MemoryAdapter created by NewMemoryAdapterWithOptions
+type MemoryAdapterOption func(*MemoryAdapterConfig)
+
+// WithMaxItems bounds the adapter to n entries, evicting the least recently
+// used entry (see EvictionPolicy) when a Set would exceed it; 0 means unlimited
+func WithMaxItems(n int) MemoryAdapterOption {
+	return func(config *MemoryAdapterConfig) {
+		config.MaxEntries = n
+	}
+}
+
+// WithCleanupInterval sets how often expired entries are removed
+func WithCleanupInterval(d time.Duration) MemoryAdapterOption {
+	return func(config *MemoryAdapterConfig) {
+		config.CleanupInterval = d
+	}
+}
+
 // MemoryAdapter is an in-memory cache implementation
 type MemoryAdapter struct {
 	store   map[string]*cacheItem
 	mu      sync.RWMutex
+	syncMap *syncMapAdapter
 	stopCh  chan struct{}
 	cleanUp bool
+
+	// cleanupInterval is the period of the expired entry cleanup
+	cleanupInterval time.Duration
+
+	// maxEntries bounds the store when evict is set
+	maxEntries int
+	evict      evictionIndex
+	policy     EvictionPolicy
+
+	// exempt holds the keys never evicted, such as the cache version key
+	exempt map[string]struct{}
 }
 
 // NewMemoryAdapter creates a new in-memory cache adapter
 func NewMemoryAdapter() *MemoryAdapter {
+	return NewMemoryAdapterWithConfig(MemoryAdapterConfig{})
+}
+
+// NewMemoryAdapterWithSyncMap creates a new in-memory cache adapter backed by sync.Map
+func NewMemoryAdapterWithSyncMap() *MemoryAdapter {
+	return NewMemoryAdapterWithConfig(MemoryAdapterConfig{Backend: BackendSyncMap})
+}
+
+// NewMemoryAdapterWithLFU creates a new in-memory cache adapter bounded to
+// maxItems entries, evicting the least frequently used entry when full
+func NewMemoryAdapterWithLFU(maxItems int) *MemoryAdapter {
+	return NewMemoryAdapterWithConfig(MemoryAdapterConfig{MaxEntries: maxItems, Eviction: EvictLFU})
+}
+
+// NewMemoryAdapterWithOptions creates a new in-memory cache adapter configured by opts
+// Usage: gormcache.NewMemoryAdapterWithOptions(gormcache.WithMaxItems(10000))
+func NewMemoryAdapterWithOptions(opts ...MemoryAdapterOption) *MemoryAdapter {
+	var config MemoryAdapterConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return NewMemoryAdapterWithConfig(config)
+}
+
+// NewMemoryAdapterWithConfig creates a new in-memory cache adapter with the given configuration
+func NewMemoryAdapterWithConfig(config MemoryAdapterConfig) *MemoryAdapter {
 	adapter := &MemoryAdapter{
-		store:   make(map[string]*cacheItem),
-		stopCh:  make(chan struct{}),
-		cleanUp: true,
+		stopCh:          make(chan struct{}),
+		cleanUp:         true,
+		cleanupInterval: config.CleanupInterval,
+		policy:          config.Eviction,
+	}
+	if adapter.cleanupInterval <= 0 {
+		adapter.cleanupInterval = time.Minute
+	}
+
+	switch config.Backend {
+	case BackendSyncMap:
+		adapter.syncMap = &syncMapAdapter{}
+	default:
+		adapter.store = make(map[string]*cacheItem)
+		if config.MaxEntries > 0 {
+			adapter.maxEntries = config.MaxEntries
+			adapter.evict = newEvictionIndex(adapter.policy)
+		}
 	}
 
 	// Start cleanup goroutine
@@ -37,79 +127,281 @@ func NewMemoryAdapter() *MemoryAdapter {
 
 // Get retrieves a value from memory cache
 func (m *MemoryAdapter) Get(ctx context.Context, key string) ([]byte, error) {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
+	if m.syncMap != nil {
+		return m.syncMap.Get(key)
+	}
 
+	m.mu.RLock()
 	item, exists := m.store[key]
+	tracked := m.evict != nil
+	m.mu.RUnlock()
+
 	if !exists {
-		return nil, errors.New("key not found")
+		return nil, fmt.Errorf("%w: key not found", ErrCacheMiss)
 	}
 
 	// Check if expired
-	if !item.expiration.IsZero() && time.Now().After(item.expiration) {
-		return nil, errors.New("key expired")
+	if item.expired(time.Now()) {
+		return nil, fmt.Errorf("%w: key expired", ErrCacheMiss)
+	}
+
+	// Record the read for eviction
+	if tracked && !isPeek(ctx) {
+		m.mu.Lock()
+		if _, ok := m.store[key]; ok {
+			m.track(key)
+		}
+		m.mu.Unlock()
 	}
 
 	return item.value, nil
 }
 
-// Set stores a value in memory cache
-func (m *MemoryAdapter) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+// Exists reports whether key is cached and not expired, without copying the
+// value or recording an access for eviction
+func (m *MemoryAdapter) Exists(ctx context.Context, key string) (bool, error) {
+	if m.syncMap != nil {
+		return m.syncMap.Exists(key), nil
+	}
+
+	m.mu.RLock()
+	item, exists := m.store[key]
+	m.mu.RUnlock()
+
+	return exists && !item.expired(time.Now()), nil
+}
+
+// TTL returns the remaining lifetime of key, 0 if it never expires
+func (m *MemoryAdapter) TTL(ctx context.Context, key string) (time.Duration, error) {
+	var item *cacheItem
+	if m.syncMap != nil {
+		v, ok := m.syncMap.store.Load(key)
+		if ok {
+			item = v.(*cacheItem)
+		}
+	} else {
+		m.mu.RLock()
+		item = m.store[key]
+		m.mu.RUnlock()
+	}
+
+	now := time.Now()
+	if item == nil || item.expired(now) {
+		return 0, fmt.Errorf("%w: key not found", ErrCacheMiss)
+	}
+	if item.expiration.IsZero() {
+		return 0, nil
+	}
+	return item.expiration.Sub(now), nil
+}
+
+// Touch resets the TTL of key without copying its value
+func (m *MemoryAdapter) Touch(ctx context.Context, key string, ttl time.Duration) error {
+	if m.syncMap != nil {
+		return m.syncMap.Touch(key, ttl)
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	item := &cacheItem{
-		value: value,
+	item, exists := m.store[key]
+	if !exists || item.expired(time.Now()) {
+		return fmt.Errorf("%w: key not found", ErrCacheMiss)
 	}
+	// Get 在释放锁后读取 expiration，替换条目而不是原地修改
+	m.store[key] = newCacheItem(item.value, ttl)
+	m.track(key)
+	return nil
+}
 
-	if ttl > 0 {
-		item.expiration = time.Now().Add(ttl)
+// Set stores a value in memory cache
+func (m *MemoryAdapter) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	item := newCacheItem(value, ttl)
+
+	if m.syncMap != nil {
+		m.syncMap.Set(key, item)
+		return nil
 	}
 
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	m.store[key] = item
+	m.track(key)
+	m.evictOverflow()
 	return nil
 }
 
 // Delete removes a value from memory cache
 func (m *MemoryAdapter) Delete(ctx context.Context, key string) error {
+	if m.syncMap != nil {
+		m.syncMap.Delete(key)
+		return nil
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	delete(m.store, key)
+	if m.evict != nil {
+		m.evict.remove(key)
+	}
+	return nil
+}
+
+// MGet retrieves the values of the given keys, holding the lock once
+func (m *MemoryAdapter) MGet(ctx context.Context, keys []string) (map[string][]byte, error) {
+	values := make(map[string][]byte, len(keys))
+
+	if m.syncMap != nil {
+		for _, key := range keys {
+			if value, err := m.syncMap.Get(key); err == nil {
+				values[key] = value
+			}
+		}
+		return values, nil
+	}
+
+	// 记录访问需要写锁
+	if m.evict != nil {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+	} else {
+		m.mu.RLock()
+		defer m.mu.RUnlock()
+	}
+
+	now := time.Now()
+	for _, key := range keys {
+		item, exists := m.store[key]
+		if !exists || item.expired(now) {
+			continue
+		}
+		m.track(key)
+		values[key] = item.value
+	}
+
+	return values, nil
+}
+
+// MSet stores all items with the given TTL, holding the lock once
+func (m *MemoryAdapter) MSet(ctx context.Context, items map[string][]byte, ttl time.Duration) error {
+	if m.syncMap != nil {
+		for key, value := range items {
+			m.syncMap.Set(key, newCacheItem(value, ttl))
+		}
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key, value := range items {
+		m.store[key] = newCacheItem(value, ttl)
+		m.track(key)
+	}
+	if m.evict != nil {
+		m.evictOverflow()
+	}
+	return nil
+}
+
+// MDelete removes the given keys, holding the lock once
+func (m *MemoryAdapter) MDelete(ctx context.Context, keys []string) error {
+	if m.syncMap != nil {
+		for _, key := range keys {
+			m.syncMap.Delete(key)
+		}
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, key := range keys {
+		delete(m.store, key)
+		if m.evict != nil {
+			m.evict.remove(key)
+		}
+	}
 	return nil
 }
 
 // DeletePattern removes all keys matching the pattern
 func (m *MemoryAdapter) DeletePattern(ctx context.Context, pattern string) error {
+	if m.syncMap != nil {
+		m.syncMap.DeletePattern(pattern)
+		return nil
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	// Simple pattern matching: replace * with any characters
-	prefix := strings.TrimSuffix(pattern, "*")
-
 	keysToDelete := make([]string, 0)
 	for key := range m.store {
-		if strings.HasPrefix(key, prefix) || pattern == "*" {
+		if matchPattern(pattern, key) {
 			keysToDelete = append(keysToDelete, key)
 		}
 	}
 
 	for _, key := range keysToDelete {
 		delete(m.store, key)
+		if m.evict != nil {
+			m.evict.remove(key)
+		}
 	}
 
 	return nil
 }
 
+// Scan returns the keys matching the pattern
+func (m *MemoryAdapter) Scan(ctx context.Context, pattern string) ([]string, error) {
+	if m.syncMap != nil {
+		return m.syncMap.Keys(pattern), nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	keys := make([]string, 0)
+	for key := range m.store {
+		if matchPattern(pattern, key) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys, nil
+}
+
 // Clear removes all cached data
 func (m *MemoryAdapter) Clear(ctx context.Context) error {
+	if m.syncMap != nil {
+		m.syncMap.Clear()
+		return nil
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	m.store = make(map[string]*cacheItem)
+	if m.evict != nil {
+		m.evict = newEvictionIndex(m.policy)
+	}
 	return nil
 }
 
+// Count returns the number of cached entries
+func (m *MemoryAdapter) Count(ctx context.Context) (int, error) {
+	if m.syncMap != nil {
+		return m.syncMap.Count(), nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return len(m.store), nil
+}
+
 // Close closes the adapter
 func (m *MemoryAdapter) Close() error {
 	m.cleanUp = false
@@ -119,7 +411,7 @@ func (m *MemoryAdapter) Close() error {
 
 // startCleanup periodically removes expired items
 func (m *MemoryAdapter) startCleanup() {
-	ticker := time.NewTicker(1 * time.Minute)
+	ticker := time.NewTicker(m.cleanupInterval)
 	defer ticker.Stop()
 
 	for {
@@ -136,13 +428,52 @@ func (m *MemoryAdapter) startCleanup() {
 }
 
 func (m *MemoryAdapter) cleanup() {
+	if m.syncMap != nil {
+		m.syncMap.cleanup()
+		return
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	now := time.Now()
 	for key, item := range m.store {
-		if !item.expiration.IsZero() && now.After(item.expiration) {
+		if item.expired(now) {
 			delete(m.store, key)
+			if m.evict != nil {
+				m.evict.remove(key)
+			}
 		}
 	}
 }
+
+// newCacheItem creates a cache item expiring after ttl (never if ttl <= 0)
+func newCacheItem(value []byte, ttl time.Duration) *cacheItem {
+	item := &cacheItem{
+		value: value,
+	}
+
+	if ttl > 0 {
+		item.expiration = time.Now().Add(ttl)
+	}
+
+	return item
+}
+
+// expired reports whether the item is expired at the given time
+func (i *cacheItem) expired(now time.Time) bool {
+	return !i.expiration.IsZero() && now.After(i.expiration)
+}
+
+// matchPattern reports whether key matches the glob pattern, supporting *, ?,
+// [...] and \ escapes like Redis SCAN patterns (* and ? match any byte,
+// including '/'); a pattern without wildcards matches keys it prefixes
+func matchPattern(pattern, key string) bool {
+	if pattern == "*" {
+		return true
+	}
+	if !strings.ContainsAny(pattern, "*?[") {
+		return strings.HasPrefix(key, pattern)
+	}
+	return globMatch(pattern, key)
+}
@@ -2,6 +2,7 @@ package gormcache
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 )
@@ -144,3 +145,283 @@ func TestMemoryAdapterExpiration(t *testing.T) {
 		t.Error("expected error for expired key, got nil")
 	}
 }
+
+func TestMemoryAdapterSyncMapBackend(t *testing.T) {
+	adapter := NewMemoryAdapterWithSyncMap()
+	defer adapter.Close()
+
+	ctx := context.Background()
+
+	adapter.Set(ctx, "user:1", []byte("value1"), 1*time.Minute)
+	adapter.Set(ctx, "user:2", []byte("value2"), 1*time.Minute)
+	adapter.Set(ctx, "order:1", []byte("value3"), 1*time.Minute)
+	adapter.Set(ctx, "short", []byte("value4"), 50*time.Millisecond)
+
+	result, err := adapter.Get(ctx, "user:1")
+	if err != nil {
+		t.Fatalf("failed to get: %v", err)
+	}
+	if string(result) != "value1" {
+		t.Errorf("expected 'value1', got '%s'", result)
+	}
+
+	if err := adapter.DeletePattern(ctx, "user:*"); err != nil {
+		t.Fatalf("failed to delete pattern: %v", err)
+	}
+	if _, err := adapter.Get(ctx, "user:2"); err == nil {
+		t.Error("expected user:2 to be deleted")
+	}
+	if _, err := adapter.Get(ctx, "order:1"); err != nil {
+		t.Error("expected order:1 to exist")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if _, err := adapter.Get(ctx, "short"); err == nil {
+		t.Error("expected error for expired key, got nil")
+	}
+
+	if err := adapter.Delete(ctx, "order:1"); err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+	if _, err := adapter.Get(ctx, "order:1"); err == nil {
+		t.Error("expected order:1 to be deleted")
+	}
+
+	adapter.Set(ctx, "key1", []byte("value1"), 1*time.Minute)
+	if err := adapter.Clear(ctx); err != nil {
+		t.Fatalf("failed to clear: %v", err)
+	}
+	if _, err := adapter.Get(ctx, "key1"); err == nil {
+		t.Error("expected key1 to be deleted")
+	}
+}
+
+// benchmarkReadHeavy runs a 95% read / 5% write workload against the adapter
+func benchmarkReadHeavy(b *testing.B, adapter *MemoryAdapter) {
+	defer adapter.Close()
+
+	ctx := context.Background()
+	const keys = 1024
+	for i := 0; i < keys; i++ {
+		adapter.Set(ctx, fmt.Sprintf("key:%d", i), []byte("value"), time.Minute)
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			key := fmt.Sprintf("key:%d", i%keys)
+			if i%20 == 0 {
+				adapter.Set(ctx, key, []byte("value"), time.Minute)
+			} else {
+				adapter.Get(ctx, key)
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkMemoryAdapterMapReadHeavy(b *testing.B) {
+	benchmarkReadHeavy(b, NewMemoryAdapter())
+}
+
+func BenchmarkMemoryAdapterSyncMapReadHeavy(b *testing.B) {
+	benchmarkReadHeavy(b, NewMemoryAdapterWithSyncMap())
+}
+
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		key     string
+		want    bool
+	}{
+		{"*", "anything", true},
+		{"users:*", "users:1", true},
+		{"users:*", "orders:1", false},
+		{"users:*:tenant:1", "users:abc:tenant:1", true},
+		{"users:*:tenant:1", "users:abc:tenant:12", false},
+		{"users:*:tenant:*", "users:abc:tenant:2", true},
+		{"users:", "users:1", true},
+		{"users:?", "users:1", true},
+		{"users:?", "users:12", false},
+		{"users:[abc]", "users:b", true},
+		{"users:[abc]", "users:d", false},
+		{"users:[", "users:[", false},
+		{"users:*", "users:a/b", true},
+		{"users:*:tenant:1", "users:a/b:tenant:1", true},
+		{"users:?", "users:/", true},
+		{"gorm:cache:*", "gorm:cache:/api/v1:hash", true},
+		{"users:[^abc]", "users:d", true},
+		{"users:[^abc]", "users:a", false},
+		{"users:[0-9]", "users:7", true},
+		{"users:[0-9]", "users:x", false},
+		{`users:\*`, "users:*", true},
+		{`users:\*`, "users:1", false},
+		{"*:tenant:*", "a:b:tenant:c", true},
+		{"*a*b", "xaxxb", true},
+		{"*a*b", "xaxxbc", false},
+	}
+
+	for _, tt := range tests {
+		if got := matchPattern(tt.pattern, tt.key); got != tt.want {
+			t.Errorf("matchPattern(%q, %q) = %v, want %v", tt.pattern, tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestMemoryAdapterDeleteGlobPattern(t *testing.T) {
+	adapter := NewMemoryAdapter()
+	defer adapter.Close()
+
+	ctx := context.Background()
+	for _, key := range []string{"user:a", "user:b", "user:d", "user:ab"} {
+		adapter.Set(ctx, key, []byte("value"), 1*time.Minute)
+	}
+
+	// [abc] matches one character of the set
+	adapter.DeletePattern(ctx, "user:[abc]")
+	for key, want := range map[string]bool{"user:a": false, "user:b": false, "user:d": true, "user:ab": true} {
+		if _, err := adapter.Get(ctx, key); (err == nil) != want {
+			t.Errorf("after user:[abc], expected %s present = %v", key, want)
+		}
+	}
+
+	// ? matches exactly one character
+	adapter.DeletePattern(ctx, "user:?")
+	if _, err := adapter.Get(ctx, "user:d"); err == nil {
+		t.Error("expected user:d to match user:?")
+	}
+	if _, err := adapter.Get(ctx, "user:ab"); err != nil {
+		t.Error("expected user:ab not to match user:?")
+	}
+}
+
+func TestMemoryAdapterMaxEntries(t *testing.T) {
+	adapter := NewMemoryAdapterWithConfig(MemoryAdapterConfig{MaxEntries: 2})
+	defer adapter.Close()
+
+	ctx := context.Background()
+
+	adapter.Set(ctx, "key1", []byte("value1"), 1*time.Minute)
+	adapter.Set(ctx, "key2", []byte("value2"), 1*time.Minute)
+	adapter.Get(ctx, "key1")
+	adapter.Set(ctx, "key3", []byte("value3"), 1*time.Minute)
+
+	// key2 is the least recently used entry
+	if _, err := adapter.Get(ctx, "key2"); err == nil {
+		t.Error("expected key2 to be evicted")
+	}
+	for _, key := range []string{"key1", "key3"} {
+		if _, err := adapter.Get(ctx, key); err != nil {
+			t.Errorf("expected %s to be kept", key)
+		}
+	}
+}
+
+func TestMemoryAdapterWithOptions(t *testing.T) {
+	adapter := NewMemoryAdapterWithOptions(WithMaxItems(3), WithCleanupInterval(10*time.Millisecond))
+	defer adapter.Close()
+
+	ctx := context.Background()
+
+	adapter.Set(ctx, "key1", []byte("value1"), 1*time.Minute)
+	adapter.Set(ctx, "key2", []byte("value2"), 1*time.Minute)
+	adapter.Set(ctx, "key3", []byte("value3"), 1*time.Minute)
+	adapter.Get(ctx, "key1")
+	adapter.Set(ctx, "key4", []byte("value4"), 1*time.Minute)
+
+	// key2 is the oldest unused entry, key1 was read recently
+	if _, err := adapter.Get(ctx, "key2"); err == nil {
+		t.Error("expected key2 to be evicted")
+	}
+	for _, key := range []string{"key1", "key3", "key4"} {
+		if _, err := adapter.Get(ctx, key); err != nil {
+			t.Errorf("expected %s to be kept", key)
+		}
+	}
+
+	// 过期条目由清理协程按配置的间隔移除
+	adapter.Set(ctx, "short", []byte("value"), 5*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	adapter.mu.RLock()
+	_, exists := adapter.store["short"]
+	adapter.mu.RUnlock()
+	if exists {
+		t.Error("expected the expired entry to be removed by the cleanup")
+	}
+}
+
+func TestMemoryAdapterLFU(t *testing.T) {
+	adapter := NewMemoryAdapterWithLFU(3)
+	defer adapter.Close()
+
+	ctx := context.Background()
+	adapter.Set(ctx, "key1", []byte("value1"), 1*time.Minute)
+	adapter.Set(ctx, "key2", []byte("value2"), 1*time.Minute)
+	for i := 0; i < 5; i++ {
+		adapter.Get(ctx, "key1")
+		adapter.Get(ctx, "key2")
+	}
+	for i := 3; i <= 5; i++ {
+		adapter.Set(ctx, fmt.Sprintf("key%d", i), []byte("value"), 1*time.Minute)
+	}
+	adapter.Set(ctx, "key6", []byte("value6"), 1*time.Minute)
+
+	if n, _ := adapter.Count(ctx); n != 3 {
+		t.Fatalf("expected 3 entries, got %d", n)
+	}
+	// The frequently read entries survive even though they are the oldest
+	for _, key := range []string{"key1", "key2", "key6"} {
+		if _, err := adapter.Get(ctx, key); err != nil {
+			t.Errorf("expected %s to be kept", key)
+		}
+	}
+	for _, key := range []string{"key3", "key4", "key5"} {
+		if _, err := adapter.Get(ctx, key); err == nil {
+			t.Errorf("expected %s to be evicted", key)
+		}
+	}
+}
+
+func TestMemoryAdapterResize(t *testing.T) {
+	adapter := NewMemoryAdapterWithConfig(MemoryAdapterConfig{MaxEntries: 100})
+	defer adapter.Close()
+
+	ctx := context.Background()
+	for i := 0; i < 100; i++ {
+		adapter.Set(ctx, fmt.Sprintf("key%d", i), []byte("value"), 1*time.Minute)
+	}
+
+	// Read the first half, leaving the second half least recently read
+	for i := 0; i < 50; i++ {
+		adapter.Get(ctx, fmt.Sprintf("key%d", i))
+	}
+
+	if err := adapter.Resize(50); err != nil {
+		t.Fatalf("failed to resize: %v", err)
+	}
+	if n, _ := adapter.Count(ctx); n != 50 {
+		t.Fatalf("expected 50 entries after resize, got %d", n)
+	}
+	for i := 0; i < 100; i++ {
+		_, err := adapter.Get(ctx, fmt.Sprintf("key%d", i))
+		if kept := err == nil; kept != (i < 50) {
+			t.Errorf("key%d: expected kept = %v", i, i < 50)
+		}
+	}
+
+	if err := adapter.Resize(-1); err == nil {
+		t.Error("expected an error for a negative size")
+	}
+
+	// 0 removes the limit
+	if err := adapter.Resize(0); err != nil {
+		t.Fatalf("failed to resize: %v", err)
+	}
+	for i := 100; i < 200; i++ {
+		adapter.Set(ctx, fmt.Sprintf("key%d", i), []byte("value"), 1*time.Minute)
+	}
+	if n, _ := adapter.Count(ctx); n != 150 {
+		t.Errorf("expected 150 entries without limit, got %d", n)
+	}
+}
@@ -0,0 +1,226 @@
+package gormcache
+
+import (
+	"container/list"
+	"errors"
+)
+
+// EvictionPolicy selects which entry a bounded MemoryAdapter evicts when full
+type EvictionPolicy int
+
+const (
+	// EvictLRU evicts the least recently used entry (default)
+	EvictLRU EvictionPolicy = iota
+	// EvictLFU evicts the least frequently used entry, the least recently used
+	// one among entries with the same frequency
+	EvictLFU
+)
+
+// evictionIndex records key accesses and picks the next key to evict; it is
+// guarded by the MemoryAdapter mutex
+type evictionIndex interface {
+	touch(key string)
+	remove(key string)
+	oldest() (string, bool)
+}
+
+// newEvictionIndex creates the index implementing policy
+func newEvictionIndex(policy EvictionPolicy) evictionIndex {
+	if policy == EvictLFU {
+		return newLFUIndex()
+	}
+	return newLRUIndex()
+}
+
+// lruIndex tracks how recently the keys of a MemoryAdapter were used, so the
+// least recently used ones can be evicted when MaxEntries is exceeded
+type lruIndex struct {
+	order *list.List // front is the most recently used key
+	elems map[string]*list.Element
+}
+
+func newLRUIndex() *lruIndex {
+	return &lruIndex{
+		order: list.New(),
+		elems: make(map[string]*list.Element),
+	}
+}
+
+// touch marks key as the most recently used
+func (l *lruIndex) touch(key string) {
+	if elem, ok := l.elems[key]; ok {
+		l.order.MoveToFront(elem)
+		return
+	}
+	l.elems[key] = l.order.PushFront(key)
+}
+
+// remove forgets key
+func (l *lruIndex) remove(key string) {
+	if elem, ok := l.elems[key]; ok {
+		l.order.Remove(elem)
+		delete(l.elems, key)
+	}
+}
+
+// oldest returns the least recently used key
+func (l *lruIndex) oldest() (string, bool) {
+	elem := l.order.Back()
+	if elem == nil {
+		return "", false
+	}
+	return elem.Value.(string), true
+}
+
+// lfuIndex counts how often the keys of a MemoryAdapter were used, so the
+// least frequently used ones can be evicted when MaxEntries is exceeded
+type lfuIndex struct {
+	freqMap map[string]int
+	// buckets holds the keys of each frequency, front is the most recently used
+	buckets map[int]*list.List
+	elems   map[string]*list.Element
+	minFreq int
+}
+
+func newLFUIndex() *lfuIndex {
+	return &lfuIndex{
+		freqMap: make(map[string]int),
+		buckets: make(map[int]*list.List),
+		elems:   make(map[string]*list.Element),
+	}
+}
+
+// touch increments the access count of key
+func (l *lfuIndex) touch(key string) {
+	freq := l.freqMap[key]
+	if freq > 0 {
+		l.unlink(key, freq)
+	}
+	freq++
+
+	bucket, ok := l.buckets[freq]
+	if !ok {
+		bucket = list.New()
+		l.buckets[freq] = bucket
+	}
+	l.freqMap[key] = freq
+	l.elems[key] = bucket.PushFront(key)
+
+	if freq == 1 || l.minFreq == 0 {
+		l.minFreq = freq
+	}
+}
+
+// remove forgets key
+func (l *lfuIndex) remove(key string) {
+	if freq, ok := l.freqMap[key]; ok {
+		l.unlink(key, freq)
+		delete(l.freqMap, key)
+		delete(l.elems, key)
+	}
+}
+
+// unlink takes key out of the bucket of freq, dropping the bucket once empty
+func (l *lfuIndex) unlink(key string, freq int) {
+	bucket := l.buckets[freq]
+	bucket.Remove(l.elems[key])
+	if bucket.Len() > 0 {
+		return
+	}
+	delete(l.buckets, freq)
+	if l.minFreq == freq {
+		// touch 会把键移到 freq+1，remove 之后由 oldest 重新计算
+		l.minFreq = freq + 1
+	}
+}
+
+// oldest returns the least recently used key among the least frequently used ones
+func (l *lfuIndex) oldest() (string, bool) {
+	if len(l.freqMap) == 0 {
+		return "", false
+	}
+	if _, ok := l.buckets[l.minFreq]; !ok {
+		l.minFreq = 0
+		for freq := range l.buckets {
+			if l.minFreq == 0 || freq < l.minFreq {
+				l.minFreq = freq
+			}
+		}
+	}
+	return l.buckets[l.minFreq].Back().Value.(string), true
+}
+
+// Resize changes the maximum number of entries at runtime, evicting entries
+// according to the eviction policy if the cache holds more than newMax
+// A newMax of 0 removes the limit
+func (m *MemoryAdapter) Resize(newMax int) error {
+	if newMax < 0 {
+		return errors.New("gorm:cache: max entries must not be negative")
+	}
+	if m.syncMap != nil {
+		return errors.New("gorm:cache: max entries are not supported by the sync.Map backend")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if newMax == 0 {
+		m.maxEntries = 0
+		m.evict = nil
+		return nil
+	}
+
+	if m.evict == nil {
+		// 之前没有限制时没有访问记录，现有条目按任意顺序加入
+		m.evict = newEvictionIndex(m.policy)
+		for key := range m.store {
+			m.track(key)
+		}
+	}
+	m.maxEntries = newMax
+	m.evictOverflow()
+
+	return nil
+}
+
+// evictOverflow evicts entries chosen by the eviction index until the store fits
+// maxEntries; the caller must hold m.mu
+func (m *MemoryAdapter) evictOverflow() {
+	if m.evict == nil {
+		return
+	}
+	for len(m.store) > m.maxEntries {
+		key, ok := m.evict.oldest()
+		if !ok {
+			return
+		}
+		m.evict.remove(key)
+		delete(m.store, key)
+	}
+}
+
+// track records an access to key for eviction, unless key is exempt
+func (m *MemoryAdapter) track(key string) {
+	if m.evict == nil {
+		return
+	}
+	if _, ok := m.exempt[key]; ok {
+		return
+	}
+	m.evict.touch(key)
+}
+
+// exemptFromEviction keeps key out of the eviction index, so a bounded
+// adapter never evicts it to make room for other entries
+func (m *MemoryAdapter) exemptFromEviction(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.exempt == nil {
+		m.exempt = make(map[string]struct{})
+	}
+	m.exempt[key] = struct{}{}
+	if m.evict != nil {
+		m.evict.remove(key)
+	}
+}
@@ -0,0 +1,105 @@
+package gormcache
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// syncMapAdapter is the sync.Map backed storage of MemoryAdapter
+type syncMapAdapter struct {
+	store sync.Map
+}
+
+// Get retrieves a value from the sync.Map store
+func (s *syncMapAdapter) Get(key string) ([]byte, error) {
+	v, ok := s.store.Load(key)
+	if !ok {
+		return nil, fmt.Errorf("%w: key not found", ErrCacheMiss)
+	}
+
+	item := v.(*cacheItem)
+	if item.expired(time.Now()) {
+		return nil, fmt.Errorf("%w: key expired", ErrCacheMiss)
+	}
+
+	return item.value, nil
+}
+
+// Exists reports whether key is stored and not expired
+func (s *syncMapAdapter) Exists(key string) bool {
+	v, ok := s.store.Load(key)
+	return ok && !v.(*cacheItem).expired(time.Now())
+}
+
+// Touch replaces the item of key with one expiring after ttl, unless the key
+// was set again concurrently
+func (s *syncMapAdapter) Touch(key string, ttl time.Duration) error {
+	v, ok := s.store.Load(key)
+	if !ok || v.(*cacheItem).expired(time.Now()) {
+		return fmt.Errorf("%w: key not found", ErrCacheMiss)
+	}
+	s.store.CompareAndSwap(key, v, newCacheItem(v.(*cacheItem).value, ttl))
+	return nil
+}
+
+// Set stores an item in the sync.Map store
+func (s *syncMapAdapter) Set(key string, item *cacheItem) {
+	s.store.Store(key, item)
+}
+
+// Delete removes a key from the sync.Map store
+func (s *syncMapAdapter) Delete(key string) {
+	s.store.Delete(key)
+}
+
+// DeletePattern removes all keys matching the pattern
+func (s *syncMapAdapter) DeletePattern(pattern string) {
+	s.store.Range(func(k, _ interface{}) bool {
+		if key := k.(string); matchPattern(pattern, key) {
+			s.store.Delete(key)
+		}
+		return true
+	})
+}
+
+// Keys returns the keys matching the pattern
+func (s *syncMapAdapter) Keys(pattern string) []string {
+	keys := make([]string, 0)
+	s.store.Range(func(k, _ interface{}) bool {
+		if key := k.(string); matchPattern(pattern, key) {
+			keys = append(keys, key)
+		}
+		return true
+	})
+	return keys
+}
+
+// Clear removes all entries
+func (s *syncMapAdapter) Clear() {
+	s.store.Range(func(k, _ interface{}) bool {
+		s.store.Delete(k)
+		return true
+	})
+}
+
+// Count returns the number of entries
+func (s *syncMapAdapter) Count() int {
+	n := 0
+	s.store.Range(func(_, _ interface{}) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+// cleanup removes expired entries
+func (s *syncMapAdapter) cleanup() {
+	now := time.Now()
+	s.store.Range(func(k, v interface{}) bool {
+		if v.(*cacheItem).expired(now) {
+			s.store.Delete(k)
+		}
+		return true
+	})
+}
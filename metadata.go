@@ -0,0 +1,145 @@
+package gormcache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// metadataSuffix is appended to a cache key to form the key of its metadata sidecar
+const metadataSuffix = ":meta"
+
+// sidecarSuffixes are the suffixes of the keys stored next to cache entries
+var sidecarSuffixes = []string{metadataSuffix, traceSuffix}
+
+// entryMetadata describes a cached query result
+// SetAt is when the TTL last started, which SlidingExpiration and Touch move
+// forward; CreatedAt is when the result was read from the database
+type entryMetadata struct {
+	SetAt     time.Time     `json:"set_at"`
+	TTL       time.Duration `json:"ttl"`
+	CreatedAt time.Time     `json:"created_at,omitempty"`
+}
+
+// createdAt returns when the result was read from the database, falling back to
+// SetAt for entries written before CreatedAt was recorded
+func (m *entryMetadata) createdAt() time.Time {
+	if m.CreatedAt.IsZero() {
+		return m.SetAt
+	}
+	return m.CreatedAt
+}
+
+// needsMetadata reports whether cached results need a metadata sidecar
+func (p *CachePlugin) needsMetadata() bool {
+	return p.config.MaxQueryCacheAge > 0 || p.config.StaleWhileRevalidate > 0 || p.config.RefreshThreshold > 0
+}
+
+// getCached retrieves and decompresses a cached query result, treating entries
+// older than MaxQueryCacheAge as misses and deleting them
+func (p *CachePlugin) getCached(ctx context.Context, cacheKey string) ([]byte, error) {
+	var cachedData []byte
+	start := time.Now()
+	err := p.withRetry(ctx, func() (err error) {
+		cachedData, err = p.adapter().Get(ctx, cacheKey)
+		return err
+	})
+	p.stats.observeLatency("get", start)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.config.MaxQueryCacheAge > 0 {
+		// 没有元数据的条目无法确认写入时间，按过期处理
+		meta, err := p.loadMetadata(ctx, cacheKey)
+		if err != nil || time.Since(meta.createdAt()) > p.config.MaxQueryCacheAge {
+			p.deleteEntry(ctx, cacheKey)
+			return nil, fmt.Errorf("%w: entry is older than MaxQueryCacheAge", ErrCacheMiss)
+		}
+	}
+
+	if p.config.codec != nil {
+		data, err := p.config.codec.decompress(cachedData)
+		if err != nil {
+			// 无法解压的条目（如启用压缩前写入的）按未命中处理
+			return nil, fmt.Errorf("%w: %w", ErrCacheMiss, err)
+		}
+		return data, nil
+	}
+	return cachedData, nil
+}
+
+// setCached compresses and stores a query result, along with its metadata
+// sidecar if needed
+func (p *CachePlugin) setCached(ctx context.Context, cacheKey string, cachedData []byte, ttl time.Duration) error {
+	return p.setCachedAt(ctx, cacheKey, cachedData, ttl, time.Now())
+}
+
+// setCachedAt is setCached for a result read from the database at createdAt
+func (p *CachePlugin) setCachedAt(ctx context.Context, cacheKey string, cachedData []byte, ttl time.Duration, createdAt time.Time) error {
+	if p.config.codec != nil {
+		compressed, err := p.config.codec.compress(cachedData)
+		if err != nil {
+			return err
+		}
+		cachedData = compressed
+	}
+
+	start := time.Now()
+	err := p.withRetry(ctx, func() error {
+		return p.adapter().Set(ctx, cacheKey, cachedData, ttl)
+	})
+	p.stats.observeLatency("set", start)
+	if err != nil {
+		return err
+	}
+	if !p.needsMetadata() {
+		return nil
+	}
+
+	return p.storeMetadata(ctx, cacheKey, entryMetadata{SetAt: time.Now(), TTL: ttl, CreatedAt: createdAt})
+}
+
+// storeMetadata stores the metadata sidecar of a cached query result, expiring
+// with the result
+func (p *CachePlugin) storeMetadata(ctx context.Context, cacheKey string, meta entryMetadata) error {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	return p.adapter().Set(ctx, cacheKey+metadataSuffix, data, meta.TTL)
+}
+
+// deleteEntry deletes a cached query result along with its sidecar keys
+func (p *CachePlugin) deleteEntry(ctx context.Context, cacheKey string) {
+	_ = p.adapter().Delete(ctx, cacheKey)
+	for _, suffix := range sidecarSuffixes {
+		_ = p.adapter().Delete(ctx, cacheKey+suffix)
+	}
+}
+
+// isSidecarKey reports whether key holds data about another cache entry
+func isSidecarKey(key string) bool {
+	for _, suffix := range sidecarSuffixes {
+		if strings.HasSuffix(key, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadMetadata retrieves the metadata sidecar of a cached query result
+func (p *CachePlugin) loadMetadata(ctx context.Context, cacheKey string) (*entryMetadata, error) {
+	data, err := p.adapter().Get(ctx, cacheKey+metadataSuffix)
+	if err != nil {
+		return nil, err
+	}
+
+	var meta entryMetadata
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
@@ -0,0 +1,83 @@
+package gormcache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMaxQueryCacheAge(t *testing.T) {
+	db := setupTestDB(t)
+
+	adapter := NewMemoryAdapter()
+	cachePlugin := New(Config{
+		Adapter:          adapter,
+		TTL:              24 * time.Hour,
+		MaxQueryCacheAge: 1 * time.Second,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	user := TestUser{Name: "Aging"}
+	db.Create(&user)
+
+	queries := countQueries(t, db)
+	first := func() {
+		var result TestUser
+		db.First(&result, user.ID)
+	}
+
+	first()
+	first()
+	if *queries != 1 {
+		t.Fatalf("expected the second query to be cached, got %d database queries", *queries)
+	}
+
+	// The entry and its metadata sidecar are stored together
+	if len(adapter.store) != 2 {
+		t.Fatalf("expected an entry and its metadata, got %d keys", len(adapter.store))
+	}
+
+	time.Sleep(2 * time.Second)
+
+	// The entry is older than MaxQueryCacheAge although its TTL has not expired
+	first()
+	if *queries != 2 {
+		t.Errorf("expected the aged entry to be treated as a miss, got %d database queries", *queries)
+	}
+}
+
+func TestMaxQueryCacheAgeSlidingExpiration(t *testing.T) {
+	db := setupTestDB(t)
+
+	cachePlugin := New(Config{
+		Adapter:           NewMemoryAdapter(),
+		TTL:               24 * time.Hour,
+		MaxQueryCacheAge:  300 * time.Millisecond,
+		SlidingExpiration: true,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	user := TestUser{Name: "Aging"}
+	db.Create(&user)
+
+	queries := countQueries(t, db)
+	first := func() {
+		var result TestUser
+		db.First(&result, user.ID)
+	}
+
+	// Hits slide the TTL but keep the time the result was read
+	first()
+	for i := 0; i < 4; i++ {
+		time.Sleep(100 * time.Millisecond)
+		first()
+	}
+	if *queries != 2 {
+		t.Errorf("expected hits not to reset the age of the entry, got %d database queries", *queries)
+	}
+}
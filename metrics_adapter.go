@@ -0,0 +1,107 @@
+package gormcache
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"sync"
+	"time"
+)
+
+// MetricsAdapter wraps an adapter and counts its operations in expvar
+// counters, published on /debug/vars by the expvar HTTP handler
+type MetricsAdapter struct {
+	inner Adapter
+
+	hits    *expvar.Int
+	misses  *expvar.Int
+	sets    *expvar.Int
+	deletes *expvar.Int
+	errors  *expvar.Int
+}
+
+// NewMetricsAdapter creates a new metrics adapter counting in the expvar
+// variables {prefix}.get_hits, {prefix}.get_misses, {prefix}.set_calls,
+// {prefix}.delete_calls and {prefix}.errors
+// Adapters created with the same prefix share their counters
+func NewMetricsAdapter(inner Adapter, prefix string) *MetricsAdapter {
+	return &MetricsAdapter{
+		inner:   inner,
+		hits:    expvarInt(prefix + ".get_hits"),
+		misses:  expvarInt(prefix + ".get_misses"),
+		sets:    expvarInt(prefix + ".set_calls"),
+		deletes: expvarInt(prefix + ".delete_calls"),
+		errors:  expvarInt(prefix + ".errors"),
+	}
+}
+
+// expvarMu serializes expvarInt, as expvar.NewInt panics on names already
+// published
+var expvarMu sync.Mutex
+
+// expvarInt returns the expvar.Int published as name, publishing it if needed
+func expvarInt(name string) *expvar.Int {
+	expvarMu.Lock()
+	defer expvarMu.Unlock()
+
+	if v, ok := expvar.Get(name).(*expvar.Int); ok {
+		return v
+	}
+	return expvar.NewInt(name)
+}
+
+// countError counts err unless it is nil
+func (a *MetricsAdapter) countError(err error) error {
+	if err != nil {
+		a.errors.Add(1)
+	}
+	return err
+}
+
+// Get retrieves a value from the inner adapter, counting a hit or a miss
+func (a *MetricsAdapter) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := a.inner.Get(ctx, key)
+	switch {
+	case err == nil:
+		a.hits.Add(1)
+	case errors.Is(err, ErrCacheMiss):
+		a.misses.Add(1)
+	default:
+		a.errors.Add(1)
+	}
+	return value, err
+}
+
+// Set stores a value in the inner adapter
+func (a *MetricsAdapter) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	a.sets.Add(1)
+	return a.countError(a.inner.Set(ctx, key, value, ttl))
+}
+
+// Delete removes a value from the inner adapter
+func (a *MetricsAdapter) Delete(ctx context.Context, key string) error {
+	a.deletes.Add(1)
+	return a.countError(a.inner.Delete(ctx, key))
+}
+
+// DeletePattern removes all keys matching the pattern from the inner adapter,
+// counted as one delete call
+func (a *MetricsAdapter) DeletePattern(ctx context.Context, pattern string) error {
+	a.deletes.Add(1)
+	return a.countError(a.inner.DeletePattern(ctx, pattern))
+}
+
+// Clear removes all cached data from the inner adapter
+func (a *MetricsAdapter) Clear(ctx context.Context) error {
+	return a.countError(a.inner.Clear(ctx))
+}
+
+// Close closes the inner adapter
+func (a *MetricsAdapter) Close() error {
+	return a.inner.Close()
+}
+
+// Unwrap returns the inner adapter
+func (a *MetricsAdapter) Unwrap() Adapter {
+	return a.inner
+}
@@ -0,0 +1,104 @@
+package gormcache
+
+import (
+	"context"
+	"expvar"
+	"sync"
+	"testing"
+	"time"
+)
+
+// expvarValue returns the value of the expvar.Int published as name, 0 if it
+// is not published yet
+func expvarValue(name string) int64 {
+	if v, ok := expvar.Get(name).(*expvar.Int); ok {
+		return v.Value()
+	}
+	return 0
+}
+
+// expvarValues returns the current value of each of names
+func expvarValues(names ...string) map[string]int64 {
+	values := make(map[string]int64, len(names))
+	for _, name := range names {
+		values[name] = expvarValue(name)
+	}
+	return values
+}
+
+func TestMetricsAdapter(t *testing.T) {
+	// 计数器是进程级的，-count=N 时会累加，只比较差值
+	before := expvarValues(
+		"gormcache_test.get_hits",
+		"gormcache_test.get_misses",
+		"gormcache_test.set_calls",
+		"gormcache_test.delete_calls",
+		"gormcache_test.errors",
+	)
+
+	adapter := NewMetricsAdapter(NewMemoryAdapter(), "gormcache_test")
+	defer adapter.Close()
+
+	ctx := context.Background()
+	adapter.Get(ctx, "missing")
+	adapter.Set(ctx, "users:1", []byte("john"), time.Minute)
+	adapter.Get(ctx, "users:1")
+	adapter.Delete(ctx, "users:1")
+
+	expected := map[string]int64{
+		"gormcache_test.get_hits":     1,
+		"gormcache_test.get_misses":   1,
+		"gormcache_test.set_calls":    1,
+		"gormcache_test.delete_calls": 1,
+		"gormcache_test.errors":       0,
+	}
+	for name, want := range expected {
+		if expvar.Get(name) == nil {
+			t.Errorf("expected expvar %s to be published", name)
+			continue
+		}
+		if got := expvarValue(name) - before[name]; got != want {
+			t.Errorf("expected %s to grow by %d, got %d", name, want, got)
+		}
+	}
+
+	// A second adapter with the same prefix shares the counters instead of panicking
+	other := NewMetricsAdapter(NewMemoryAdapter(), "gormcache_test")
+	defer other.Close()
+	other.Get(ctx, "missing")
+	if got := expvarValue("gormcache_test.get_misses") - before["gormcache_test.get_misses"]; got != 2 {
+		t.Errorf("expected shared counters, got %d misses", got)
+	}
+}
+
+func TestMetricsAdapterErrors(t *testing.T) {
+	before := expvarValues("gormcache_test_errors.errors", "gormcache_test_errors.get_misses")
+
+	adapter := NewMetricsAdapter(failingAdapter{}, "gormcache_test_errors")
+
+	ctx := context.Background()
+	adapter.Get(ctx, "key")
+	adapter.Set(ctx, "key", []byte("value"), time.Minute)
+
+	if got := expvarValue("gormcache_test_errors.errors") - before["gormcache_test_errors.errors"]; got != 2 {
+		t.Errorf("expected 2 errors, got %d", got)
+	}
+	if got := expvarValue("gormcache_test_errors.get_misses") - before["gormcache_test_errors.get_misses"]; got != 0 {
+		t.Errorf("expected failed reads not to count as misses, got %d", got)
+	}
+}
+
+func TestMetricsAdapterConcurrentPrefix(t *testing.T) {
+	prefix := "gormcache_test_concurrent_" + time.Now().Format("150405.000000000")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// 同名前缀并发创建不能因重复发布而 panic
+			NewMetricsAdapter(NewMemoryAdapter(), prefix).Close()
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,51 @@
+package gormcache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestModelTTLs(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&testOrder{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	cachePlugin := New(Config{
+		Adapter: NewMemoryAdapter(),
+		TTL:     5 * time.Minute,
+		ModelTTLs: map[string]time.Duration{
+			"test_users":  50 * time.Millisecond,
+			"test_orders": 10 * time.Second,
+		},
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	db.Create(&TestUser{Name: "Alice"})
+	db.Create(&testOrder{UserID: 1})
+
+	queries := countQueries(t, db)
+
+	var users []TestUser
+	db.Find(&users)
+	var orders []testOrder
+	db.Find(&orders)
+	if *queries != 2 {
+		t.Fatalf("expected 2 database queries, got %d", *queries)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+
+	db.Find(&users)
+	if *queries != 3 {
+		t.Errorf("expected the test_users entry to have expired, got %d database queries", *queries)
+	}
+
+	db.Find(&orders)
+	if *queries != 3 {
+		t.Errorf("expected the test_orders entry to still be cached, got %d database queries", *queries)
+	}
+}
@@ -0,0 +1,111 @@
+package gormcache
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCacheNamespace(t *testing.T) {
+	db := setupTestDB(t)
+
+	adapter := NewMemoryAdapter()
+	cachePlugin := New(Config{
+		Adapter:            adapter,
+		TTL:                5 * time.Minute,
+		InvalidateOnUpdate: true,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	user := TestUser{Name: "John"}
+	db.Create(&user)
+
+	tenantA := WithCacheNamespace(context.Background(), "tenant-a")
+	tenantB := WithCacheNamespace(context.Background(), "tenant-b")
+
+	queries := countQueries(t, db)
+	first := func(ctx context.Context) string {
+		var result TestUser
+		if err := db.WithContext(ctx).First(&result, user.ID).Error; err != nil {
+			t.Fatalf("query failed: %v", err)
+		}
+		return result.Name
+	}
+
+	// The same query is cached separately in each namespace
+	first(tenantA)
+	first(tenantB)
+	first(tenantA)
+	first(tenantB)
+	if *queries != 2 {
+		t.Fatalf("expected 2 database queries, got %d", *queries)
+	}
+
+	keys, _ := adapter.Scan(context.Background(), "gorm:cache:tenant-a:test_users:*")
+	if len(keys) != 1 {
+		t.Errorf("expected 1 key in the tenant-a namespace, got %v", keys)
+	}
+
+	// An update in tenant-a only invalidates the tenant-a cache
+	db.WithContext(tenantA).Model(&user).Update("Name", "Jane")
+
+	if name := first(tenantA); name != "Jane" {
+		t.Errorf("expected tenant-a to read 'Jane', got '%s'", name)
+	}
+	if *queries != 3 {
+		t.Errorf("expected the tenant-a query to be refetched, got %d database queries", *queries)
+	}
+	if name := first(tenantB); name != "John" {
+		t.Errorf("expected tenant-b to keep its cached 'John', got '%s'", name)
+	}
+	if *queries != 3 {
+		t.Errorf("expected the tenant-b query to stay cached, got %d database queries", *queries)
+	}
+}
+
+func TestNamespaceExtractor(t *testing.T) {
+	db := setupTestDB(t)
+
+	adapter := NewMemoryAdapter()
+	cachePlugin := New(Config{
+		Adapter: adapter,
+		TTL:     5 * time.Minute,
+		NamespaceExtractor: func(ctx context.Context) string {
+			tenant, _ := ctx.Value(tenantKey{}).(string)
+			return tenant
+		},
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	db.Create(&TestUser{Name: "John"})
+
+	var users []TestUser
+	db.WithContext(context.WithValue(context.Background(), tenantKey{}, "acme")).Find(&users)
+	// WithCacheNamespace takes precedence over the extractor
+	db.WithContext(WithCacheNamespace(context.WithValue(context.Background(), tenantKey{}, "acme"), "override")).Find(&users)
+	// Without a namespace, keys are not namespaced
+	db.Find(&users)
+
+	keys, _ := adapter.Scan(context.Background(), "*")
+	var acme, override, plain int
+	for _, key := range keys {
+		switch {
+		case strings.HasPrefix(key, "gorm:cache:acme:test_users:"):
+			acme++
+		case strings.HasPrefix(key, "gorm:cache:override:test_users:"):
+			override++
+		case strings.HasPrefix(key, "gorm:cache:test_users:"):
+			plain++
+		}
+	}
+	if acme != 1 || override != 1 || plain != 1 {
+		t.Errorf("expected one key per namespace, got %v", keys)
+	}
+}
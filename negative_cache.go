@@ -0,0 +1,51 @@
+package gormcache
+
+import (
+	"bytes"
+	"reflect"
+
+	"gorm.io/gorm"
+)
+
+// negativeEntry is the cached value recorded for a query that returned no rows
+// It is not valid output of any Serializer, so it never collides with a result
+var negativeEntry = []byte("\x00gorm:cache:negative")
+
+// isNegativeEntry reports whether data is a cached empty result
+func isNegativeEntry(data []byte) bool {
+	return bytes.Equal(data, negativeEntry)
+}
+
+// storeNegative caches the empty result of the current query for NegativeTTL
+func (p *CachePlugin) storeNegative(db *gorm.DB, cacheKey string) {
+	ctx := p.statementContext(db)
+	stored, err := p.storeCached(ctx, cacheKey, negativeEntry, p.config.NegativeTTL)
+	if err != nil {
+		p.stats.errors.Add(1)
+		p.onError(err)
+		return
+	}
+	if !stored {
+		return
+	}
+	p.stats.bytesStored.Add(int64(len(negativeEntry)))
+	p.onStore(cacheKey, p.config.NegativeTTL)
+}
+
+// serveNegative answers the current query with an empty result
+// First, Take and Last report gorm.ErrRecordNotFound like the database would
+func serveNegative(db *gorm.DB) {
+	if db.Statement.RaiseErrorOnNotFound {
+		db.Error = gorm.ErrRecordNotFound
+		return
+	}
+
+	// 清空目标切片，避免返回调用方传入的旧数据
+	if db.Statement.Dest != nil {
+		reflectValue := reflect.Indirect(reflect.ValueOf(db.Statement.Dest))
+		if reflectValue.Kind() == reflect.Slice && reflectValue.CanSet() {
+			reflectValue.Set(reflect.MakeSlice(reflectValue.Type(), 0, 0))
+		}
+	}
+	db.Error = &ErrCacheHit{RowsAffected: 0}
+}
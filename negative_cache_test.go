@@ -0,0 +1,147 @@
+package gormcache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func TestNegativeTTLCachesEmptyResults(t *testing.T) {
+	db := setupTestDB(t)
+
+	cachePlugin := New(Config{
+		Adapter:     NewMemoryAdapter(),
+		TTL:         5 * time.Minute,
+		NegativeTTL: time.Minute,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	queries := countQueries(t, db)
+
+	var first []TestUser
+	if err := db.Where("name = ?", "Nobody").Find(&first).Error; err != nil {
+		t.Fatalf("first query failed: %v", err)
+	}
+
+	// 第二次查询应由缓存的空结果返回，并清空传入的旧数据
+	second := []TestUser{{Name: "stale"}}
+	result := db.Where("name = ?", "Nobody").Find(&second)
+	if result.Error != nil {
+		t.Fatalf("second query failed: %v", result.Error)
+	}
+	if *queries != 1 {
+		t.Errorf("expected the empty result to be served from cache, got %d database queries", *queries)
+	}
+	if len(second) != 0 || result.RowsAffected != 0 {
+		t.Errorf("expected an empty result, got %d rows (RowsAffected %d)", len(second), result.RowsAffected)
+	}
+
+	// InvalidateOnCreate 未开启，插入后负缓存在过期前仍然有效
+	db.Create(&TestUser{Name: "Nobody"})
+
+	var third []TestUser
+	db.Where("name = ?", "Nobody").Find(&third)
+	if len(third) != 0 {
+		t.Errorf("expected the cached empty set before NegativeTTL expires, got %d rows", len(third))
+	}
+	if *queries != 1 {
+		t.Errorf("expected 1 database query, got %d", *queries)
+	}
+}
+
+func TestNegativeTTLExpires(t *testing.T) {
+	db := setupTestDB(t)
+
+	cachePlugin := New(Config{
+		Adapter:     NewMemoryAdapter(),
+		TTL:         5 * time.Minute,
+		NegativeTTL: 50 * time.Millisecond,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	var before []TestUser
+	db.Where("name = ?", "Late").Find(&before)
+	db.Create(&TestUser{Name: "Late"})
+
+	time.Sleep(100 * time.Millisecond)
+
+	var after []TestUser
+	db.Where("name = ?", "Late").Find(&after)
+	if len(after) != 1 {
+		t.Errorf("expected the negative entry to expire after NegativeTTL, got %d rows", len(after))
+	}
+}
+
+func TestNegativeTTLFirstReturnsRecordNotFound(t *testing.T) {
+	db := setupTestDB(t)
+
+	cachePlugin := New(Config{
+		Adapter:     NewMemoryAdapter(),
+		TTL:         5 * time.Minute,
+		NegativeTTL: time.Minute,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	queries := countQueries(t, db)
+
+	for i := 0; i < 2; i++ {
+		var user TestUser
+		err := db.First(&user, 42).Error
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Fatalf("query %d: expected gorm.ErrRecordNotFound, got %v", i+1, err)
+		}
+	}
+	if *queries != 1 {
+		t.Errorf("expected the missing record to be served from cache, got %d database queries", *queries)
+	}
+}
+
+func TestNegativeTTLSoftInvalidation(t *testing.T) {
+	db := setupTestDB(t)
+
+	// Keep the in-memory database on a single connection for background refreshes
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+
+	cachePlugin := New(Config{
+		Adapter:            NewMemoryAdapter(),
+		TTL:                5 * time.Minute,
+		NegativeTTL:        time.Minute,
+		InvalidateOnCreate: true,
+		SoftInvalidation:   true,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	var empty []TestUser
+	db.Where("name = ?", "Nobody").Find(&empty)
+
+	// 写入只给空结果打上过期标记，之后的命中必须触发刷新
+	db.Create(&TestUser{Name: "Nobody"})
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		var users []TestUser
+		db.Where("name = ?", "Nobody").Find(&users)
+		if len(users) == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the stale empty result to be refreshed after the insert")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
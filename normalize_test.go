@@ -0,0 +1,80 @@
+package gormcache
+
+import (
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// statementWithSQL returns a session whose statement holds the given SQL
+func statementWithSQL(db *gorm.DB, sql string, vars ...interface{}) *gorm.DB {
+	tx := db.Session(&gorm.Session{NewDB: true}).Model(&TestUser{})
+	tx.Statement.Parse(&TestUser{})
+	tx.Statement.SQL.WriteString(sql)
+	tx.Statement.Vars = vars
+	return tx
+}
+
+func TestNormalizePlaceholders(t *testing.T) {
+	db := setupTestDB(t)
+
+	config := Config{KeyPrefix: "gorm:cache:", NormalizePlaceholders: true}
+
+	questionMarks := statementWithSQL(db, "SELECT * FROM test_users WHERE id = ? AND name = ?", 1, "John")
+	numbered := statementWithSQL(db, "  SELECT *  FROM test_users\n\tWHERE id = $1 AND name = $2 ", 1, "John")
+
+	key1 := config.generateCacheKey(questionMarks, "")
+	key2 := config.generateCacheKey(numbered, "")
+	if key1 != key2 {
+		t.Errorf("expected both placeholder styles to produce the same key, got %q and %q", key1, key2)
+	}
+
+	// Different arguments still produce different keys
+	other := statementWithSQL(db, "SELECT * FROM test_users WHERE id = $1 AND name = $2", 2, "John")
+	if key := config.generateCacheKey(other, ""); key == key1 {
+		t.Error("expected different arguments to produce different keys")
+	}
+
+	// Without normalization the keys differ
+	config.NormalizePlaceholders = false
+	if config.generateCacheKey(questionMarks, "") == config.generateCacheKey(numbered, "") {
+		t.Error("expected different keys without NormalizePlaceholders")
+	}
+}
+
+func TestNormalizeSQL(t *testing.T) {
+	db := setupTestDB(t)
+
+	config := Config{KeyPrefix: "gorm:cache:", NormalizeSQL: true}
+
+	upper := statementWithSQL(db, "SELECT  id FROM users")
+	lower := statementWithSQL(db, "select id  from users")
+	if key1, key2 := config.generateCacheKey(upper, ""), config.generateCacheKey(lower, ""); key1 != key2 {
+		t.Errorf("expected differently formatted SQL to produce the same key, got %q and %q", key1, key2)
+	}
+
+	// Without normalization the keys differ
+	config.NormalizeSQL = false
+	if config.generateCacheKey(upper, "") == config.generateCacheKey(lower, "") {
+		t.Error("expected different keys without NormalizeSQL")
+	}
+}
+
+func TestCanonicalSQL(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{"SELECT  id FROM users", "select id from users"},
+		{"  SELECT *\n\tFROM `Users`  WHERE Name = ? ORDER BY Name DESC ", "select * from `Users` where Name = ? order by Name desc"},
+		{"SELECT * FROM users WHERE name = 'John  SMITH'", "select * from users where name = 'John  SMITH'"},
+		{"SELECT * FROM users WHERE name = 'it''s  OK' AND Id IN (1,2)", "select * from users where name = 'it''s  OK' and Id in (1,2)"},
+		{`SELECT "Select" FROM t`, `select "Select" from t`},
+	}
+
+	for _, tt := range tests {
+		if got := canonicalSQL(tt.query); got != tt.expected {
+			t.Errorf("canonicalSQL(%q) = %q, expected %q", tt.query, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package gormcache
+
+import (
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func TestIncludeOrderByInCacheKey(t *testing.T) {
+	db := setupTestDB(t)
+
+	config := Config{KeyPrefix: "gorm:cache:", IncludeOrderByInCacheKey: true}
+	key := func(order interface{}) string {
+		var users []TestUser
+		stmt := db.Session(&gorm.Session{DryRun: true}).Order(order).Find(&users)
+		return config.generateCacheKey(stmt, "")
+	}
+
+	if key("name ASC") == key("name DESC") {
+		t.Error("expected ASC and DESC to produce different keys")
+	}
+	if key("name, id") == key("id, name") {
+		t.Error("expected different column orders to produce different keys")
+	}
+	if key("name DESC") != key("name DESC") {
+		t.Error("expected identical orderings to produce the same key")
+	}
+}
+
+func TestOrderByKey(t *testing.T) {
+	db := setupTestDB(t)
+
+	orderBy := func(order interface{}) string {
+		var users []TestUser
+		return orderByKey(db.Session(&gorm.Session{DryRun: true}).Order(order).Find(&users))
+	}
+
+	tests := []struct {
+		order interface{}
+		want  string
+	}{
+		{"name", "name asc"},
+		{"NAME  ASC", "name asc"},
+		{"name desc", "name desc"},
+		{clause.OrderByColumn{Column: clause.Column{Name: "Name"}, Desc: true}, "name desc"},
+		{clause.OrderByColumn{Column: clause.Column{Table: "test_users", Name: "id"}}, "test_users.id asc"},
+	}
+
+	for _, tt := range tests {
+		if got := orderBy(tt.order); got != tt.want {
+			t.Errorf("orderByKey(%v) = %q, want %q", tt.order, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package gormcache
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// OverheadReport describes the latency impact of the cache plugin on a query
+type OverheadReport struct {
+	// AvgCacheHitLatency is the average latency of queries served from the cache
+	AvgCacheHitLatency time.Duration
+
+	// AvgCacheMissLatency is the average latency of queries missing the cache,
+	// including storing their results
+	AvgCacheMissLatency time.Duration
+
+	// AvgNoPluginLatency is the average latency of queries bypassing the cache
+	AvgNoPluginLatency time.Duration
+
+	// OverheadPercentage is the extra latency of a cache miss relative to
+	// bypassing the cache, in percent
+	OverheadPercentage float64
+}
+
+// MeasureOverhead measures the latency impact of the plugin on the query built
+// by db, which must set a model or table, e.g. db.Model(&User{}).Where("active = ?", true)
+// It runs n warmup queries to populate the cache, then n queries for each of
+// cache hits, cache misses and SkipCache; the cached entry of the query is
+// deleted before every miss, so run it against a representative, quiet table
+func (p *CachePlugin) MeasureOverhead(db *gorm.DB, n int) (OverheadReport, error) {
+	if n <= 0 {
+		return OverheadReport{}, errors.New("gorm:cache: MeasureOverhead requires n > 0")
+	}
+	if db.Statement.Model == nil && db.Statement.Table == "" {
+		return OverheadReport{}, errors.New("gorm:cache: MeasureOverhead requires db.Model or db.Table")
+	}
+
+	base := db.Session(&gorm.Session{})
+
+	var cacheKey string
+	for i := 0; i < n; i++ {
+		tx, _, err := timedFind(base)
+		if err != nil {
+			return OverheadReport{}, err
+		}
+		if key, ok := tx.Statement.Settings.Load("gorm:cache:key"); ok {
+			cacheKey, _ = key.(string)
+		}
+	}
+
+	if cacheKey == "" {
+		return OverheadReport{}, errors.New("gorm:cache: MeasureOverhead query is not cached")
+	}
+
+	ctx := p.statementContext(base)
+	skipped := base.Scopes(SkipCache()).Session(&gorm.Session{})
+
+	// 三种查询交替执行，避免 GC 或负载波动只影响其中一种
+	var hit, miss, noPlugin time.Duration
+	for i := 0; i < n; i++ {
+		_, elapsed, err := timedFind(base)
+		if err != nil {
+			return OverheadReport{}, err
+		}
+		hit += elapsed
+
+		// 删除缓存条目不计入耗时
+		if err := p.adapter().Delete(ctx, cacheKey); err != nil {
+			return OverheadReport{}, err
+		}
+		if _, elapsed, err = timedFind(base); err != nil {
+			return OverheadReport{}, err
+		}
+		miss += elapsed
+
+		if _, elapsed, err = timedFind(skipped); err != nil {
+			return OverheadReport{}, err
+		}
+		noPlugin += elapsed
+	}
+
+	report := OverheadReport{
+		AvgCacheHitLatency:  hit / time.Duration(n),
+		AvgCacheMissLatency: miss / time.Duration(n),
+		AvgNoPluginLatency:  noPlugin / time.Duration(n),
+	}
+	if report.AvgNoPluginLatency > 0 {
+		extra := report.AvgCacheMissLatency - report.AvgNoPluginLatency
+		report.OverheadPercentage = float64(extra) / float64(report.AvgNoPluginLatency) * 100
+	}
+
+	return report, nil
+}
+
+// timedFind runs the query of db into a generic destination and returns its latency
+func timedFind(db *gorm.DB) (*gorm.DB, time.Duration, error) {
+	var rows []map[string]interface{}
+
+	start := time.Now()
+	tx := db.Find(&rows)
+	return tx, time.Since(start), tx.Error
+}
@@ -0,0 +1,61 @@
+package gormcache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestMeasureOverhead(t *testing.T) {
+	db := setupTestDB(t)
+
+	cachePlugin := New(Config{
+		Adapter: NewMemoryAdapter(),
+		TTL:     5 * time.Minute,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	// Enough rows for storing the result to outweigh timing noise
+	users := make([]TestUser, 200)
+	for i := range users {
+		users[i].Name = fmt.Sprintf("user-%03d", i)
+	}
+	db.Create(&users)
+
+	report, err := cachePlugin.MeasureOverhead(db.Model(&TestUser{}), 200)
+	if err != nil {
+		t.Fatalf("MeasureOverhead failed: %v", err)
+	}
+
+	if report.AvgCacheHitLatency <= 0 {
+		t.Errorf("expected a positive cache hit latency, got %v", report.AvgCacheHitLatency)
+	}
+	if report.AvgCacheMissLatency <= 0 {
+		t.Errorf("expected a positive cache miss latency, got %v", report.AvgCacheMissLatency)
+	}
+	if report.AvgNoPluginLatency <= 0 {
+		t.Errorf("expected a positive no-plugin latency, got %v", report.AvgNoPluginLatency)
+	}
+	if report.OverheadPercentage <= 0 {
+		t.Errorf("expected a positive overhead percentage, got %v (report: %+v)", report.OverheadPercentage, report)
+	}
+}
+
+func TestMeasureOverheadRequiresModel(t *testing.T) {
+	db := setupTestDB(t)
+
+	cachePlugin := New(Config{Adapter: NewMemoryAdapter()})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+
+	if _, err := cachePlugin.MeasureOverhead(db, 10); err == nil {
+		t.Error("expected an error without a model or table")
+	}
+	if _, err := cachePlugin.MeasureOverhead(db.Model(&TestUser{}), 0); err == nil {
+		t.Error("expected an error for n = 0")
+	}
+}
@@ -0,0 +1,34 @@
+package gormcache
+
+import "context"
+
+// Peek returns the cached query result stored under key, decompressed but not
+// deserialized, without touching the entry: SlidingExpiration does not reset
+// its TTL and no hooks or statistics are recorded
+// It is meant for debugging tools; adapters see the read as a peek, so
+// MemoryAdapter does not count it for LRU eviction and TwoLevelAdapter does
+// not promote L2 hits into L1
+func (p *CachePlugin) Peek(ctx context.Context, key string) ([]byte, error) {
+	data, err := p.adapter().Get(withPeek(ctx), key)
+	if err != nil {
+		return nil, err
+	}
+	if p.config.codec != nil {
+		return p.config.codec.decompress(data)
+	}
+	return data, nil
+}
+
+// withPeek marks ctx as used by Peek, so adapters skip the bookkeeping of reads
+func withPeek(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextKeyPeek, true)
+}
+
+// isPeek reports whether ctx comes from Peek
+func isPeek(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	peek, _ := ctx.Value(contextKeyPeek).(bool)
+	return peek
+}
@@ -0,0 +1,80 @@
+package gormcache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPeek(t *testing.T) {
+	db := setupTestDB(t)
+
+	adapter := NewMemoryAdapter()
+	defer adapter.Close()
+	cachePlugin := New(Config{
+		Adapter:           adapter,
+		TTL:               time.Minute,
+		SlidingExpiration: true,
+		Compression:       CompressionGzip,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+
+	db.Create(&TestUser{Name: "John"})
+	var users []TestUser
+	db.Find(&users)
+
+	var key string
+	for k := range adapter.store {
+		key = k
+	}
+	ctx := context.Background()
+	before, err := adapter.TTL(ctx, key)
+	if err != nil {
+		t.Fatalf("TTL failed: %v", err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	data, err := cachePlugin.Peek(ctx, key)
+	if err != nil {
+		t.Fatalf("Peek failed: %v", err)
+	}
+	var peeked []TestUser
+	if err := json.Unmarshal(data, &peeked); err != nil || len(peeked) != 1 || peeked[0].Name != "John" {
+		t.Errorf("expected the decompressed cached users, got %q (%v)", data, err)
+	}
+
+	after, _ := adapter.TTL(ctx, key)
+	if after >= before {
+		t.Errorf("expected Peek to leave the TTL running, got %v before and %v after", before, after)
+	}
+
+	// A cache hit through GORM slides the expiration, unlike Peek
+	db.Find(&users)
+	if slid, _ := adapter.TTL(ctx, key); slid <= after {
+		t.Errorf("expected the cache hit to reset the TTL, got %v after %v", slid, after)
+	}
+
+	if _, err := cachePlugin.Peek(ctx, "missing"); !errors.Is(err, ErrCacheMiss) {
+		t.Errorf("expected ErrCacheMiss, got %v", err)
+	}
+}
+
+func TestPeekTwoLevelAdapter(t *testing.T) {
+	l1, l2 := NewMemoryAdapter(), NewMemoryAdapter()
+	cachePlugin := New(Config{Adapter: NewTwoLevelAdapter(l1, l2, time.Minute)})
+	defer cachePlugin.Close()
+
+	ctx := context.Background()
+	l2.Set(ctx, "key", []byte("value"), time.Minute)
+
+	if data, err := cachePlugin.Peek(ctx, "key"); err != nil || string(data) != "value" {
+		t.Fatalf("expected value, got %q (%v)", data, err)
+	}
+	if _, err := l1.Get(ctx, "key"); err == nil {
+		t.Error("expected Peek not to promote the L2 hit into L1")
+	}
+}
@@ -0,0 +1,37 @@
+package gormcache
+
+import "context"
+
+// pingAdapterTimeout pings adapter, giving up once ctx is done even if the
+// adapter does not honor context deadlines (go-redis ignores them by default)
+func pingAdapterTimeout(ctx context.Context, adapter Adapter) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- pingAdapter(ctx, adapter)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// pingAdapter checks that the backend of adapter is reachable, looking through
+// wrapping adapters
+// Adapters not implementing PingableAdapter are assumed to be reachable
+func pingAdapter(ctx context.Context, adapter Adapter) error {
+	for _, a := range adapterChain(adapter) {
+		// 本地 L1 同样需要可用，L2 由 Unwrap 继续检查
+		if twoLevel, ok := a.(*TwoLevelAdapter); ok {
+			if err := pingAdapter(ctx, twoLevel.l1); err != nil {
+				return err
+			}
+		}
+		if pinger, ok := a.(PingableAdapter); ok {
+			return pinger.Ping(ctx)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package gormcache
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func TestFailFastOnAdapterError(t *testing.T) {
+	// A listener that accepts connections but never answers simulates a hung Redis
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	db := setupTestDB(t)
+
+	adapter := NewRedisAdapter(RedisAdapterConfig{Addr: listener.Addr().String(), MaxRetries: -1})
+	defer adapter.Close()
+
+	cachePlugin := New(Config{
+		Adapter:                NewReadOnlyAdapter(adapter),
+		FailFastOnAdapterError: true,
+		AdapterInitTimeout:     200 * time.Millisecond,
+	})
+
+	start := time.Now()
+	err = db.Use(cachePlugin)
+	elapsed := time.Since(start)
+
+	if err == nil {
+		t.Fatal("expected Initialize to fail for an unreachable adapter")
+	}
+	if elapsed > 2*time.Second {
+		t.Errorf("expected the init timeout to be respected, took %v", elapsed)
+	}
+}
+
+func TestFailFastOnAdapterErrorReachable(t *testing.T) {
+	mr := miniredis.RunT(t)
+	db := setupTestDB(t)
+
+	cachePlugin := New(Config{
+		Adapter:                NewRedisAdapter(RedisAdapterConfig{Addr: mr.Addr()}),
+		FailFastOnAdapterError: true,
+	})
+	defer cachePlugin.Close()
+
+	if err := db.Use(cachePlugin); err != nil {
+		t.Errorf("expected a reachable adapter to initialize, got %v", err)
+	}
+}
+
+func TestFailFastOnAdapterErrorDisabled(t *testing.T) {
+	db := setupTestDB(t)
+
+	adapter := NewRedisAdapter(RedisAdapterConfig{Addr: "127.0.0.1:1", MaxRetries: -1})
+	cachePlugin := New(Config{Adapter: adapter})
+	defer cachePlugin.Close()
+
+	// Without FailFastOnAdapterError the adapter is not checked at startup
+	if err := db.Use(cachePlugin); err != nil {
+		t.Errorf("expected Initialize not to check the adapter, got %v", err)
+	}
+}
+
+func TestMustInitialize(t *testing.T) {
+	db := setupTestDB(t)
+
+	mr := miniredis.RunT(t)
+	adapter := NewRedisAdapter(RedisAdapterConfig{Addr: mr.Addr(), MaxRetries: -1})
+	defer adapter.Close()
+	mr.Close()
+
+	cachePlugin := New(Config{
+		Adapter:                adapter,
+		FailFastOnAdapterError: true,
+		AdapterInitTimeout:     200 * time.Millisecond,
+	})
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected MustInitialize to panic for an unreachable adapter")
+		}
+	}()
+	cachePlugin.MustInitialize(db)
+}
+
+func TestMustInitializeReachable(t *testing.T) {
+	db := setupTestDB(t)
+
+	cachePlugin := MustNew(Config{Adapter: NewMemoryAdapter(), TTL: 5 * time.Minute})
+	defer cachePlugin.Close()
+	cachePlugin.MustInitialize(db)
+	if _, ok := db.Config.Plugins[cachePlugin.Name()]; !ok {
+		t.Error("expected MustInitialize to register the plugin like db.Use")
+	}
+
+	db.Create(&TestUser{Name: "John"})
+	queries := countQueries(t, db)
+	var users []TestUser
+	db.Find(&users)
+	db.Find(&users)
+	if *queries != 1 {
+		t.Errorf("expected the callbacks to be registered, got %d database queries", *queries)
+	}
+}
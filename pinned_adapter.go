@@ -0,0 +1,334 @@
+package gormcache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// pinnedSetKey is the Redis set holding pinned keys when the inner adapter is a RedisAdapter
+	// It lies outside the plugin key prefix so CacheSize, Keys and model
+	// invalidation patterns never see it
+	pinnedSetKey = "gorm:pinned-keys"
+)
+
+// PinnedKeysAdapter wraps an adapter and prevents pinned keys from being
+// removed by Delete, DeletePattern or Clear
+// Inner adapters implementing ScannableAdapter have their unpinned keys
+// deleted one by one; others are wiped and the pinned entries written back
+type PinnedKeysAdapter struct {
+	inner Adapter
+	pins  pinSet
+	redis *redis.Client
+
+	// expirations holds the expiry of pinned keys of inner adapters without
+	// TTLAdapter, to restore them with their remaining TTL
+	mu          sync.Mutex
+	expirations map[string]time.Time
+}
+
+// pinSet stores the set of pinned keys
+type pinSet interface {
+	add(ctx context.Context, key string) error
+	remove(ctx context.Context, key string) error
+	contains(ctx context.Context, key string) (bool, error)
+	members(ctx context.Context) ([]string, error)
+}
+
+// NewPinnedKeysAdapter creates a new adapter protecting the given keys of inner
+// When inner is a RedisAdapter or RedisSentinelAdapter, the pinned set is kept in
+// Redis so it survives restarts
+func NewPinnedKeysAdapter(inner Adapter, pinnedKeys ...string) *PinnedKeysAdapter {
+	adapter := &PinnedKeysAdapter{
+		inner:       inner,
+		expirations: make(map[string]time.Time),
+	}
+
+	if client, ok := redisClientOf(inner); ok {
+		adapter.redis = client
+		adapter.pins = &redisPinSet{client: client}
+	} else {
+		adapter.pins = &memoryPinSet{keys: make(map[string]struct{})}
+	}
+
+	for _, key := range pinnedKeys {
+		_ = adapter.Pin(key)
+	}
+
+	return adapter
+}
+
+// Pin protects key from deletion
+func (a *PinnedKeysAdapter) Pin(key string) error {
+	return a.pins.add(context.Background(), key)
+}
+
+// Unpin removes the deletion protection of key
+func (a *PinnedKeysAdapter) Unpin(key string) error {
+	a.forgetExpiration(key)
+	return a.pins.remove(context.Background(), key)
+}
+
+// Get retrieves a value from the inner adapter
+func (a *PinnedKeysAdapter) Get(ctx context.Context, key string) ([]byte, error) {
+	return a.inner.Get(ctx, key)
+}
+
+// Set stores a value in the inner adapter
+func (a *PinnedKeysAdapter) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := a.inner.Set(ctx, key, value, ttl); err != nil {
+		return err
+	}
+
+	// Inner adapters reporting their TTL need no bookkeeping
+	if _, ok := a.inner.(TTLAdapter); ok || a.redis != nil {
+		return nil
+	}
+
+	// Remember the expiration of pinned keys so they can be restored with
+	// their remaining TTL if the inner adapter cannot delete around them
+	pinned, err := a.pins.contains(ctx, key)
+	if err != nil || !pinned {
+		return nil
+	}
+	a.mu.Lock()
+	if ttl > 0 {
+		a.expirations[key] = time.Now().Add(ttl)
+	} else {
+		delete(a.expirations, key)
+	}
+	a.mu.Unlock()
+
+	return nil
+}
+
+// Delete removes a value from the inner adapter unless the key is pinned
+func (a *PinnedKeysAdapter) Delete(ctx context.Context, key string) error {
+	pinned, err := a.pins.contains(ctx, key)
+	if err != nil {
+		return err
+	}
+	if pinned {
+		return nil
+	}
+	return a.inner.Delete(ctx, key)
+}
+
+// DeletePattern removes all keys matching the pattern except pinned ones
+func (a *PinnedKeysAdapter) DeletePattern(ctx context.Context, pattern string) error {
+	if _, ok := a.inner.(ScannableAdapter); ok {
+		return a.deleteUnpinned(ctx, pattern)
+	}
+	return a.preservePinned(ctx, pattern, func() error {
+		return a.inner.DeletePattern(ctx, pattern)
+	})
+}
+
+// Clear removes all cached data except pinned keys
+func (a *PinnedKeysAdapter) Clear(ctx context.Context) error {
+	if _, ok := a.inner.(ScannableAdapter); ok {
+		return a.deleteUnpinned(ctx, "*")
+	}
+	return a.preservePinned(ctx, "*", func() error {
+		return a.inner.Clear(ctx)
+	})
+}
+
+// Close closes the inner adapter
+func (a *PinnedKeysAdapter) Close() error {
+	return a.inner.Close()
+}
+
+// Unwrap returns the inner adapter
+func (a *PinnedKeysAdapter) Unwrap() Adapter {
+	return a.inner
+}
+
+// deleteUnpinned deletes the keys of a ScannableAdapter matching pattern,
+// skipping pinned keys so they are never removed, even briefly
+func (a *PinnedKeysAdapter) deleteUnpinned(ctx context.Context, pattern string) error {
+	keys, err := a.inner.(ScannableAdapter).Scan(ctx, pattern)
+	if err != nil {
+		return err
+	}
+	pinned, err := a.pins.members(ctx)
+	if err != nil {
+		return err
+	}
+
+	skip := make(map[string]struct{}, len(pinned)+1)
+	for _, key := range pinned {
+		skip[key] = struct{}{}
+	}
+	if a.redis != nil {
+		skip[pinnedSetKey] = struct{}{}
+	}
+
+	remove := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if _, ok := skip[key]; !ok {
+			remove = append(remove, key)
+		}
+	}
+	if len(remove) == 0 {
+		return nil
+	}
+
+	if batch, ok := a.inner.(BatchAdapter); ok {
+		return batch.MDelete(ctx, remove)
+	}
+	for _, key := range remove {
+		if err := a.inner.Delete(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// preservePinned runs remove and restores the pinned entries matching pattern
+// that it deleted, for inner adapters unable to list their keys
+func (a *PinnedKeysAdapter) preservePinned(ctx context.Context, pattern string, remove func() error) error {
+	keys, err := a.pins.members(ctx)
+	if err != nil {
+		return err
+	}
+
+	type pinnedEntry struct {
+		key   string
+		value []byte
+		ttl   time.Duration
+	}
+
+	entries := make([]pinnedEntry, 0, len(keys))
+	for _, key := range keys {
+		if !matchPattern(pattern, key) {
+			continue
+		}
+		if value, err := a.inner.Get(ctx, key); err == nil {
+			entries = append(entries, pinnedEntry{key: key, value: value, ttl: a.remainingTTL(ctx, key)})
+		} else {
+			a.forgetExpiration(key)
+		}
+	}
+
+	if err := remove(); err != nil {
+		return err
+	}
+
+	// Clear on Redis flushes the pinned set as well
+	for _, key := range keys {
+		if err := a.pins.add(ctx, key); err != nil {
+			return err
+		}
+	}
+
+	for _, entry := range entries {
+		if err := a.inner.Set(ctx, entry.key, entry.value, entry.ttl); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// remainingTTL returns the TTL left for key, or 0 if it does not expire
+func (a *PinnedKeysAdapter) remainingTTL(ctx context.Context, key string) time.Duration {
+	if a.redis != nil {
+		if ttl, err := a.redis.PTTL(ctx, key).Result(); err == nil && ttl > 0 {
+			return ttl
+		}
+		return 0
+	}
+	if adapter, ok := a.inner.(TTLAdapter); ok {
+		if ttl, err := adapter.TTL(ctx, key); err == nil {
+			return ttl
+		}
+		return 0
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	expiration, ok := a.expirations[key]
+	if !ok {
+		return 0
+	}
+	if ttl := time.Until(expiration); ttl > 0 {
+		return ttl
+	}
+	// About to expire anyway, keep it for the shortest possible time
+	delete(a.expirations, key)
+	return time.Millisecond
+}
+
+// forgetExpiration drops the recorded expiration of key
+func (a *PinnedKeysAdapter) forgetExpiration(key string) {
+	a.mu.Lock()
+	delete(a.expirations, key)
+	a.mu.Unlock()
+}
+
+// memoryPinSet keeps pinned keys in process memory
+type memoryPinSet struct {
+	mu   sync.RWMutex
+	keys map[string]struct{}
+}
+
+func (s *memoryPinSet) add(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keys[key] = struct{}{}
+	return nil
+}
+
+func (s *memoryPinSet) remove(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.keys, key)
+	return nil
+}
+
+func (s *memoryPinSet) contains(ctx context.Context, key string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, ok := s.keys[key]
+	return ok, nil
+}
+
+func (s *memoryPinSet) members(ctx context.Context) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]string, 0, len(s.keys))
+	for key := range s.keys {
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// redisPinSet keeps pinned keys in a Redis set
+type redisPinSet struct {
+	client *redis.Client
+}
+
+func (s *redisPinSet) add(ctx context.Context, key string) error {
+	return s.client.SAdd(ctx, pinnedSetKey, key).Err()
+}
+
+func (s *redisPinSet) remove(ctx context.Context, key string) error {
+	return s.client.SRem(ctx, pinnedSetKey, key).Err()
+}
+
+func (s *redisPinSet) contains(ctx context.Context, key string) (bool, error) {
+	return s.client.SIsMember(ctx, pinnedSetKey, key).Result()
+}
+
+func (s *redisPinSet) members(ctx context.Context) ([]string, error) {
+	return s.client.SMembers(ctx, pinnedSetKey).Result()
+}
@@ -0,0 +1,161 @@
+package gormcache
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func TestPinnedKeysAdapter(t *testing.T) {
+	adapter := NewPinnedKeysAdapter(NewMemoryAdapter(), "config:system")
+	defer adapter.Close()
+
+	ctx := context.Background()
+
+	adapter.Set(ctx, "config:system", []byte("pinned"), 1*time.Minute)
+	adapter.Set(ctx, "config:other", []byte("value1"), 1*time.Minute)
+	adapter.Set(ctx, "user:1", []byte("value2"), 1*time.Minute)
+
+	// Pattern deletion skips pinned keys
+	if err := adapter.DeletePattern(ctx, "config:*"); err != nil {
+		t.Fatalf("failed to delete pattern: %v", err)
+	}
+	if _, err := adapter.Get(ctx, "config:system"); err != nil {
+		t.Error("expected pinned key to survive pattern deletion")
+	}
+	if _, err := adapter.Get(ctx, "config:other"); err == nil {
+		t.Error("expected config:other to be deleted")
+	}
+
+	// Single key deletion skips pinned keys
+	adapter.Delete(ctx, "config:system")
+	if _, err := adapter.Get(ctx, "config:system"); err != nil {
+		t.Error("expected pinned key to survive Delete")
+	}
+
+	// Clear skips pinned keys
+	if err := adapter.Clear(ctx); err != nil {
+		t.Fatalf("failed to clear: %v", err)
+	}
+	if value, err := adapter.Get(ctx, "config:system"); err != nil || string(value) != "pinned" {
+		t.Errorf("expected pinned key to survive Clear, got %q, %v", value, err)
+	}
+	if _, err := adapter.Get(ctx, "user:1"); err == nil {
+		t.Error("expected user:1 to be cleared")
+	}
+
+	// Unpinned keys can be deleted again
+	adapter.Unpin("config:system")
+	adapter.Delete(ctx, "config:system")
+	if _, err := adapter.Get(ctx, "config:system"); err == nil {
+		t.Error("expected unpinned key to be deleted")
+	}
+}
+
+func TestPinnedKeysAdapterRedis(t *testing.T) {
+	mr := miniredis.RunT(t)
+	ctx := context.Background()
+
+	adapter := NewPinnedKeysAdapter(NewRedisAdapter(RedisAdapterConfig{Addr: mr.Addr()}), "config:system")
+	adapter.Set(ctx, "config:system", []byte("pinned"), 1*time.Minute)
+	adapter.Set(ctx, "user:1", []byte("value"), 1*time.Minute)
+	adapter.Close()
+
+	// The pinned set lives in Redis and survives a restart of the application
+	adapter = NewPinnedKeysAdapter(NewRedisAdapter(RedisAdapterConfig{Addr: mr.Addr()}))
+	defer adapter.Close()
+
+	if err := adapter.Clear(ctx); err != nil {
+		t.Fatalf("failed to clear: %v", err)
+	}
+	if value, err := adapter.Get(ctx, "config:system"); err != nil || string(value) != "pinned" {
+		t.Errorf("expected pinned key to survive Clear, got %q, %v", value, err)
+	}
+	if ttl := mr.TTL("config:system"); ttl <= 0 || ttl > time.Minute {
+		t.Errorf("expected pinned key to keep its TTL, got %v", ttl)
+	}
+	if _, err := adapter.Get(ctx, "user:1"); err == nil {
+		t.Error("expected user:1 to be cleared")
+	}
+
+	if err := adapter.DeletePattern(ctx, "config:*"); err != nil {
+		t.Fatalf("failed to delete pattern: %v", err)
+	}
+	if _, err := adapter.Get(ctx, "config:system"); err != nil {
+		t.Error("expected pinned key to survive pattern deletion")
+	}
+}
+
+func TestPinnedKeysAdapterSkipsPinnedKeys(t *testing.T) {
+	inner := NewMemoryAdapter()
+	adapter := NewPinnedKeysAdapter(inner, "config:system")
+	defer adapter.Close()
+
+	ctx := context.Background()
+	adapter.Set(ctx, "config:system", []byte("pinned"), time.Minute)
+	adapter.Set(ctx, "config:other", []byte("value"), time.Minute)
+	item := inner.store["config:system"]
+
+	if err := adapter.DeletePattern(ctx, "config:*"); err != nil {
+		t.Fatalf("failed to delete pattern: %v", err)
+	}
+	// 可扫描的适配器不会删除再写回固定的键
+	if inner.store["config:system"] != item {
+		t.Error("expected the pinned entry not to be deleted and restored")
+	}
+	if _, err := adapter.Get(ctx, "config:other"); err == nil {
+		t.Error("expected config:other to be deleted")
+	}
+	if len(adapter.expirations) != 0 {
+		t.Errorf("expected no expirations to be recorded for a TTLAdapter, got %d", len(adapter.expirations))
+	}
+}
+
+func TestPinnedKeysAdapterExpirations(t *testing.T) {
+	adapter := NewPinnedKeysAdapter(plainAdapter{NewMemoryAdapter()}, "config:system")
+	defer adapter.Close()
+
+	ctx := context.Background()
+	for i := 0; i < 10; i++ {
+		adapter.Set(ctx, "user:"+strconv.Itoa(i), []byte("value"), time.Minute)
+	}
+	if len(adapter.expirations) != 0 {
+		t.Errorf("expected no expirations for unpinned keys, got %d", len(adapter.expirations))
+	}
+
+	adapter.Set(ctx, "config:system", []byte("pinned"), time.Minute)
+	if err := adapter.Clear(ctx); err != nil {
+		t.Fatalf("failed to clear: %v", err)
+	}
+	if value, err := adapter.Get(ctx, "config:system"); err != nil || string(value) != "pinned" {
+		t.Errorf("expected pinned key to survive Clear, got %q, %v", value, err)
+	}
+	if len(adapter.expirations) != 1 {
+		t.Errorf("expected the expiration of the pinned key only, got %d", len(adapter.expirations))
+	}
+
+	adapter.Unpin("config:system")
+	if len(adapter.expirations) != 0 {
+		t.Errorf("expected Unpin to drop the expiration, got %d", len(adapter.expirations))
+	}
+}
+
+func TestPinnedKeysAdapterRedisCacheSize(t *testing.T) {
+	mr := miniredis.RunT(t)
+	ctx := context.Background()
+
+	adapter := NewPinnedKeysAdapter(NewRedisAdapter(RedisAdapterConfig{Addr: mr.Addr()}), "gorm:cache:test_users:1")
+	cachePlugin := New(Config{Adapter: adapter, TTL: time.Minute})
+	defer cachePlugin.Close()
+
+	adapter.Set(ctx, "gorm:cache:test_users:1", []byte("pinned"), time.Minute)
+	adapter.Set(ctx, "gorm:cache:test_users:2", []byte("value"), time.Minute)
+
+	// The set of pinned keys is not a cached entry
+	if size, err := cachePlugin.CacheSize(ctx); err != nil || size != 2 {
+		t.Errorf("expected 2 cached entries, got %d, %v", size, err)
+	}
+}
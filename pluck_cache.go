@@ -0,0 +1,62 @@
+package gormcache
+
+import (
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// pluckKeySegment is inserted after the table name in the cache keys of Pluck
+// queries, so a Pluck and a Find into models with the same SQL (e.g.
+// Select("name")) never read each other's cached results
+const pluckKeySegment = "pluck"
+
+// isPluckQuery reports whether the statement reads into a slice of scalars,
+// as db.Pluck() does; structs that are not the statement's model, such as
+// time.Time or sql.NullString, are scalars when a single column is selected
+func isPluckQuery(db *gorm.DB) bool {
+	if db.Statement.Dest == nil {
+		return false
+	}
+
+	destType := reflect.TypeOf(db.Statement.Dest)
+	if destType.Kind() != reflect.Ptr || destType.Elem().Kind() != reflect.Slice {
+		return false
+	}
+
+	elemType := destType.Elem().Elem()
+	for elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	switch elemType.Kind() {
+	case reflect.Map, reflect.Interface:
+		return false
+	case reflect.Struct:
+		// 结构体只有在不是语句模型且只选择一列时才是 Pluck 的标量
+		if db.Statement.Schema != nil && db.Statement.Schema.ModelType == elemType {
+			return false
+		}
+		return selectsSingleColumn(db)
+	}
+	return true
+}
+
+// selectsSingleColumn reports whether the statement selects exactly one column
+func selectsSingleColumn(db *gorm.DB) bool {
+	if len(db.Statement.Selects) == 1 {
+		return true
+	}
+	c, ok := db.Statement.Clauses["SELECT"]
+	if !ok {
+		return false
+	}
+	sel, ok := c.Expression.(clause.Select)
+	return ok && sel.Expression == nil && len(sel.Columns) == 1
+}
+
+// skipPluck reports whether the statement is a Pluck query that must not be
+// cached because CachePluckQueries is off
+func (c *Config) skipPluck(db *gorm.DB) bool {
+	return !c.CachePluckQueries && isPluckQuery(db)
+}
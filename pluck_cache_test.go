@@ -0,0 +1,119 @@
+package gormcache
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCachePluckQueries(t *testing.T) {
+	db := setupTestDB(t)
+
+	adapter := NewMemoryAdapter()
+	cachePlugin := New(Config{
+		Adapter:           adapter,
+		TTL:               5 * time.Minute,
+		CachePluckQueries: true,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	db.Create(&TestUser{Name: "John"})
+	db.Create(&TestUser{Name: "Jane"})
+
+	queries := countQueries(t, db)
+
+	var first, second []string
+	db.Model(&TestUser{}).Order("id").Pluck("name", &first)
+	db.Model(&TestUser{}).Order("id").Pluck("name", &second)
+	expected := []string{"John", "Jane"}
+	if !reflect.DeepEqual(first, expected) || !reflect.DeepEqual(second, expected) {
+		t.Errorf("expected %v, got %v and %v", expected, first, second)
+	}
+	if *queries != 1 {
+		t.Errorf("expected the second pluck to be served from cache, got %d database queries", *queries)
+	}
+
+	// A Find with the same SQL reads models, not the plucked names
+	keys, _ := adapter.Scan(context.Background(), "gorm:cache:test_users:pluck:*")
+	if len(keys) != 1 {
+		t.Errorf("expected 1 pluck key, got %v", keys)
+	}
+	var users []TestUser
+	db.Model(&TestUser{}).Select("name").Order("id").Find(&users)
+	if len(users) != 2 || users[0].Name != "John" {
+		t.Errorf("expected the 2 users, got %v", users)
+	}
+}
+
+func TestCachePluckQueriesDisabled(t *testing.T) {
+	db := setupTestDB(t)
+
+	cachePlugin := New(Config{Adapter: NewMemoryAdapter(), TTL: 5 * time.Minute})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	db.Create(&TestUser{Name: "John"})
+	queries := countQueries(t, db)
+
+	for i := 0; i < 2; i++ {
+		var names []string
+		db.Model(&TestUser{}).Pluck("name", &names)
+	}
+	if *queries != 2 {
+		t.Errorf("expected both plucks to query the database, got %d database queries", *queries)
+	}
+}
+
+// pluckEvent has a time column, plucked into []time.Time
+type pluckEvent struct {
+	ID uint
+	At time.Time
+}
+
+func TestCachePluckStructScalars(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&pluckEvent{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	adapter := NewMemoryAdapter()
+	cachePlugin := New(Config{
+		Adapter:           adapter,
+		TTL:               5 * time.Minute,
+		CachePluckQueries: true,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	db.Create(&pluckEvent{At: at})
+
+	var times []time.Time
+	if err := db.Model(&pluckEvent{}).Pluck("at", &times).Error; err != nil {
+		t.Fatalf("pluck failed: %v", err)
+	}
+	if len(times) != 1 || !times[0].Equal(at) {
+		t.Fatalf("expected %v, got %v", at, times)
+	}
+
+	// time.Time 是标量，Pluck 使用单独的键段，不与相同 SQL 的 Find 共享缓存
+	keys, _ := adapter.Scan(context.Background(), "gorm:cache:pluck_events:pluck:*")
+	if len(keys) != 1 {
+		t.Errorf("expected 1 pluck key, got %v", keys)
+	}
+	var events []pluckEvent
+	if err := db.Model(&pluckEvent{}).Select("at").Find(&events).Error; err != nil {
+		t.Fatalf("find failed: %v", err)
+	}
+	if len(events) != 1 || !events[0].At.Equal(at) {
+		t.Errorf("expected the event, got %v", events)
+	}
+}
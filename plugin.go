@@ -2,7 +2,12 @@ package gormcache
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/callbacks"
@@ -10,6 +15,26 @@ import (
 
 const (
 	pluginName = "gorm:cache"
+
+	errNilContext = "gorm:cache: db.Statement.Context is nil, pass a context with db.WithContext(ctx)"
+
+	defaultMaxConcurrentWarmups = 5
+
+	defaultHotKeyWindow = time.Minute
+
+	defaultWildcardPattern = "*"
+
+	defaultAdapterInitTimeout = 5 * time.Second
+
+	defaultSingleflightTimeout = 10 * time.Second
+
+	// defaultVersionKey lies outside the default KeyPrefix, so invalidation
+	// patterns never match it
+	defaultVersionKey = "gorm:cache-version"
+
+	defaultVersionRefreshInterval = time.Second
+
+	defaultPubSubChannel = "gorm:cache:invalidations"
 )
 
 // ErrCacheHit 是一个内部使用的 Error，用于在缓存命中时跳过数据库查询
@@ -24,7 +49,48 @@ func (e *ErrCacheHit) Error() string {
 
 // CachePlugin is a GORM plugin that provides caching functionality
 type CachePlugin struct {
-	config Config
+	config     Config
+	schemaHash atomic.Value
+	// schemaDirty is set by migrations until the schema hash is recomputed
+	schemaDirty atomic.Bool
+	schemaMu    sync.Mutex
+	callbacks  []string
+	hotKeys    *HotKeyDetector
+	refreshing sync.Map
+	tableHits  sync.Map
+	stats      cacheStats
+	flight     flightGroup
+	versionMu  sync.Mutex
+
+	// version is the last version of Config.VersionKey seen, read again once
+	// versionReadAt is older than Config.VersionRefreshInterval
+	version       int64
+	versionReadAt time.Time
+	versionReadMu sync.Mutex
+
+	// disabled is set by Disable and cleared by Enable
+	disabled atomic.Bool
+
+	// adapterMu guards config.Adapter, which SetAdapter replaces at runtime
+	adapterMu sync.RWMutex
+
+	// cacheTags maps model type names to Config.CacheTags
+	cacheTags map[string][]string
+	tagMu     sync.Mutex
+
+	// defaults maps model types to the factories registered with SetDefault
+	defaults   map[reflect.Type]func() interface{}
+	defaultsMu sync.RWMutex
+
+	// refreshAhead runs the refreshes of RefreshThreshold, nil if disabled
+	refreshAhead *refreshPool
+
+	// invalidations is the log of EnableInvalidationLog, nil if disabled
+	invalidations *invalidationLog
+
+	// broadcast is the subscription of BroadcastInvalidation, nil until Initialize
+	broadcast   *invalidationBroadcast
+	broadcastMu sync.Mutex
 }
 
 // New creates a new cache plugin with the given configuration
@@ -32,6 +98,11 @@ func New(config Config) *CachePlugin {
 	if config.Adapter == nil {
 		config.Adapter = NewMemoryAdapter()
 	}
+	if config.ReadOnly {
+		if _, ok := config.Adapter.(*ReadOnlyAdapter); !ok {
+			config.Adapter = NewReadOnlyAdapter(config.Adapter)
+		}
+	}
 	if config.TTL == 0 {
 		config.TTL = DefaultConfig().TTL
 	}
@@ -42,10 +113,60 @@ func New(config Config) *CachePlugin {
 	if config.Serializer == nil {
 		config.Serializer = &JSONSerializer{}
 	}
+	if config.MaxConcurrentWarmups <= 0 {
+		config.MaxConcurrentWarmups = defaultMaxConcurrentWarmups
+	}
+	if config.WildcardPattern == "" {
+		config.WildcardPattern = defaultWildcardPattern
+	}
+	if config.HotKeyWindow <= 0 {
+		config.HotKeyWindow = defaultHotKeyWindow
+	}
+	if config.AdapterInitTimeout <= 0 {
+		config.AdapterInitTimeout = defaultAdapterInitTimeout
+	}
+	if config.SingleflightTimeout <= 0 {
+		config.SingleflightTimeout = defaultSingleflightTimeout
+	}
+	if config.InvalidationLogSize <= 0 {
+		config.InvalidationLogSize = defaultInvalidationLogSize
+	}
+	if config.RefreshPoolSize <= 0 {
+		config.RefreshPoolSize = defaultRefreshPoolSize
+	}
+	if config.PubSubChannel == "" {
+		config.PubSubChannel = defaultPubSubChannel
+	}
+	if config.VersionRefreshInterval <= 0 {
+		config.VersionRefreshInterval = defaultVersionRefreshInterval
+	}
+	config.hashKey = config.newKeyHasher()
+	config.codec = newCodec(config.Compression, config.CompressionLevel)
+	config.invalidationTemplates, _ = parseInvalidationPatterns(config.ModelInvalidationPatterns)
 
-	return &CachePlugin{
+	p := &CachePlugin{
 		config: config,
 	}
+	if len(config.CacheTags) > 0 {
+		p.cacheTags = make(map[string][]string, len(config.CacheTags))
+		for model, tags := range config.CacheTags {
+			p.cacheTags[fmt.Sprintf("%T", model)] = tags
+		}
+	}
+	if config.EnableInvalidationLog {
+		p.invalidations = newInvalidationLog(config.InvalidationLogSize)
+	}
+	if config.RefreshThreshold > 0 {
+		p.refreshAhead = newRefreshPool(config.RefreshPoolSize)
+	}
+	if adapter, ok := config.Adapter.(evictionExempter); ok && config.VersionKey != "" {
+		adapter.exemptFromEviction(config.VersionKey)
+	}
+	if config.HotKeyThreshold > 0 && config.HotKeyHandler != nil {
+		p.hotKeys = NewHotKeyDetector(config.HotKeyThreshold, config.HotKeyWindow, config.HotKeyHandler)
+	}
+
+	return p
 }
 
 // Name returns the plugin name
@@ -55,17 +176,55 @@ func (p *CachePlugin) Name() string {
 
 // Initialize initializes the plugin with GORM
 func (p *CachePlugin) Initialize(db *gorm.DB) error {
+	// 启动时检查缓存后端是否可用，避免带着不可用的缓存运行
+	if p.config.FailFastOnAdapterError {
+		ctx, cancel := context.WithTimeout(context.Background(), p.config.AdapterInitTimeout)
+		defer cancel()
+
+		if err := pingAdapterTimeout(ctx, p.adapter()); err != nil {
+			return fmt.Errorf("gorm:cache: adapter is unreachable: %w", err)
+		}
+	}
+
+	if p.config.BroadcastInvalidation {
+		ctx, cancel := context.WithTimeout(context.Background(), p.config.AdapterInitTimeout)
+		defer cancel()
+
+		if err := p.startBroadcast(ctx); err != nil {
+			return err
+		}
+	}
+
 	// Register Query callback (for caching SELECT queries)
 	err := db.Callback().Query().Before("gorm:query").Register("gorm:cache:query", p.queryCallback)
 	if err != nil {
 		return err
 	}
+	p.callbacks = append(p.callbacks, "gorm:cache:query")
 
 	// Register After Query callback (for storing results in cache)
 	err = db.Callback().Query().After("gorm:query").Register("gorm:cache:after_query", p.afterQueryCallback)
 	if err != nil {
 		return err
 	}
+	p.callbacks = append(p.callbacks, "gorm:cache:after_query")
+
+	if p.config.InstrumentGORMLogger {
+		db.Config.Logger = newCacheStatusLogger(db.Config.Logger)
+	}
+
+	// Track schema migrations to version cache keys
+	if p.config.AutoVersionFromSchema {
+		if err := p.refreshSchemaVersion(db); err != nil {
+			return err
+		}
+
+		err = db.Callback().Raw().After("gorm:raw").Register("gorm:cache:after_migrate", p.migrateCallback)
+		if err != nil {
+			return err
+		}
+		p.callbacks = append(p.callbacks, "gorm:cache:after_migrate")
+	}
 
 	// Register Create callback (for invalidating cache)
 	if p.config.InvalidateOnCreate {
@@ -73,6 +232,7 @@ func (p *CachePlugin) Initialize(db *gorm.DB) error {
 		if err != nil {
 			return err
 		}
+		p.callbacks = append(p.callbacks, "gorm:cache:after_create")
 	}
 
 	// Register Update callback (for invalidating cache)
@@ -81,6 +241,7 @@ func (p *CachePlugin) Initialize(db *gorm.DB) error {
 		if err != nil {
 			return err
 		}
+		p.callbacks = append(p.callbacks, "gorm:cache:after_update")
 	}
 
 	// Register Delete callback (for invalidating cache)
@@ -89,20 +250,44 @@ func (p *CachePlugin) Initialize(db *gorm.DB) error {
 		if err != nil {
 			return err
 		}
+		p.callbacks = append(p.callbacks, "gorm:cache:after_delete")
+	}
+
+	// 软删除通过 UPDATE 设置 deleted_at，InvalidateOnUpdate 关闭时也需要失效
+	if p.config.InvalidateOnDelete && !p.config.InvalidateOnUpdate {
+		err = db.Callback().Update().After("gorm:update").Register("gorm:cache:after_soft_delete", p.softDeleteCallback)
+		if err != nil {
+			return err
+		}
+		p.callbacks = append(p.callbacks, "gorm:cache:after_soft_delete")
 	}
 
 	return nil
 }
 
+// MustInitialize installs the plugin with db.Use, but panics if the adapter
+// check or a callback registration fails, following the convention of Must
+// functions for setup that is not expected to fail
+// Usage: gormcache.MustNew(config).MustInitialize(db)
+func (p *CachePlugin) MustInitialize(db *gorm.DB) {
+	if err := db.Use(p); err != nil {
+		panic(err)
+	}
+}
+
 // queryCallback is executed before query to check cache
 func (p *CachePlugin) queryCallback(db *gorm.DB) {
+	// 复用的 Statement 可能带有上一次查询的缓存键和命中状态
+	db.Statement.Settings.Delete("gorm:cache:key")
+	db.Statement.Settings.Delete("gorm:cache:hit")
+
 	// 如果已经有错误，不处理缓存逻辑
 	if db.Error != nil {
 		return
 	}
 
 	// Skip if cache should be skipped
-	if p.config.shouldSkipCache(db) {
+	if p.shouldSkipCache(db) {
 		return
 	}
 
@@ -111,7 +296,12 @@ func (p *CachePlugin) queryCallback(db *gorm.DB) {
 		return
 	}
 
-	if db.Statement.SQL.Len() == 0 {
+	// Raw 查询在回调之前已经带有 SQL，只在显式开启时缓存
+	if db.Statement.SQL.Len() > 0 {
+		if !p.config.shouldCacheRaw(db) {
+			return
+		}
+	} else {
 		callbacks.BuildQuerySQL(db)
 	}
 
@@ -120,19 +310,62 @@ func (p *CachePlugin) queryCallback(db *gorm.DB) {
 		return
 	}
 
+	// Try to get from cache
+	ctx := p.statementContext(db)
+
 	// Generate cache key
-	cacheKey := p.config.generateCacheKey(db)
+	p.refreshDirtySchemaVersion(db)
+	cacheKey := p.config.generateCacheKey(db, p.keyVersion(ctx))
 
-	// Try to get from cache
-	ctx := db.Statement.Context
-	if ctx == nil {
-		ctx = context.Background()
+	// 记录缓存键，afterQueryCallback 在未命中时用它写入缓存
+	db.Statement.Settings.Store("gorm:cache:key", cacheKey)
+
+	// BeforeGet 可以跳过该键的缓存读取，结果仍按 BeforeSet 写入
+	if p.beforeGet(ctx, cacheKey) {
+		return
+	}
+
+	if p.config.RequestCacheEnabled && p.serveFromRequestCache(ctx, db, cacheKey) {
+		return
 	}
 
-	cachedData, err := p.config.Adapter.Get(ctx, cacheKey)
+	cachedData, err := p.getCached(ctx, cacheKey)
+	p.afterGet(ctx, cacheKey, err == nil)
+	db.Statement.Settings.Store("gorm:cache:hit", false)
 	if err != nil {
-		// Cache miss, continue with normal query
-		db.Statement.Settings.Store("gorm:cache:key", cacheKey)
+		if !errors.Is(err, ErrCacheMiss) {
+			p.onError(err)
+			if p.config.FailOnCacheErrors {
+				db.AddError(fmt.Errorf("gorm:cache: %w", err))
+				return
+			}
+		}
+
+		p.stats.misses.Add(1)
+		p.onMiss(cacheKey)
+
+		// Cache miss, load through the registered loader if any
+		if loader, ok := readThroughLoader(db); ok && p.loadThrough(db, cacheKey, loader) {
+			return
+		}
+
+		// Collapse concurrent misses of the same key into one database query
+		if p.config.SingleflightEnabled && p.loadShared(ctx, db, cacheKey) {
+			return
+		}
+
+		// Continue with normal query
+		return
+	}
+
+	// 缓存的空结果直接返回，不查询数据库
+	if isNegativeEntry(cachedData) {
+		serveNegative(db)
+		db.Statement.Settings.Store("gorm:cache:hit", true)
+		p.stats.hits.Add(1)
+		p.onHit(cacheKey)
+		p.recordTableHit(db)
+		p.maintainHit(ctx, db, cacheKey, cachedData)
 		return
 	}
 
@@ -145,12 +378,61 @@ func (p *CachePlugin) queryCallback(db *gorm.DB) {
 			// 设置特殊 Error 以跳过数据库查询
 			// 注意：此时 db.Error 保证为 nil（函数开头已检查）
 			db.Error = &ErrCacheHit{RowsAffected: rowsAffected}
+			db.Statement.Settings.Store("gorm:cache:hit", true)
+			p.stats.hits.Add(1)
+			p.onHit(cacheKey)
+
+			p.recordTableHit(db)
+			p.storeRequestCache(ctx, cacheKey, cachedData)
+			if p.hotKeys != nil {
+				p.hotKeys.RecordHit(cacheKey)
+			}
+
+			p.maintainHit(ctx, db, cacheKey, cachedData)
+		} else {
+			// 无法反序列化的缓存值按未命中处理，查询数据库后会被覆盖
+			p.stats.errors.Add(1)
+			p.stats.misses.Add(1)
+			p.onMiss(cacheKey)
 		}
 	}
 }
 
+// maintainHit extends, refreshes or revalidates the entry a query was served
+// from, cached empty results included
+// Empty results do not slide, so that NegativeTTL bounds how long they are served
+func (p *CachePlugin) maintainHit(ctx context.Context, db *gorm.DB, cacheKey string, cachedData []byte) {
+	if p.config.SlidingExpiration && !isNegativeEntry(cachedData) {
+		p.slideExpiration(ctx, db, cacheKey, cachedData)
+	}
+
+	if p.config.SoftInvalidation {
+		p.refreshIfStale(ctx, db, cacheKey)
+	}
+
+	if p.config.StaleWhileRevalidate > 0 {
+		p.revalidateIfExpiring(ctx, db, cacheKey)
+	}
+
+	if p.refreshAhead != nil {
+		p.refreshAheadIfExpiring(ctx, db, cacheKey)
+	}
+}
+
 // afterQueryCallback is executed after query to store results in cache
 func (p *CachePlugin) afterQueryCallback(db *gorm.DB) {
+	// 单飞的 leader 在所有返回路径上都必须结束调用，shared 为空时等待的查询自行查询
+	var shared []byte
+	defer func() {
+		if key, ok := db.Statement.Settings.Load("gorm:cache:key"); ok {
+			p.finishFlight(db, key.(string), shared)
+		}
+	}()
+
+	if p.config.InstrumentGORMLogger {
+		annotateCacheStatus(db)
+	}
+
 	// 首先检查是否是缓存命中的情况
 	if cacheHitErr, ok := db.Error.(*ErrCacheHit); ok {
 		// 设置 RowsAffected
@@ -162,13 +444,24 @@ func (p *CachePlugin) afterQueryCallback(db *gorm.DB) {
 		// Already got from cache, no need to store
 		return
 	}
+
+	// 缓存查询未找到记录时，使用 SetDefault 注册的默认值
+	if _, ok := db.Statement.Settings.Load("gorm:cache:key"); ok {
+		p.applyDefault(db)
+	}
+
 	// Skip if there was an error
-	if db.Error != nil {
+	// First, Take and Last report empty results as gorm.ErrRecordNotFound
+	// 命中的空结果同样带有该错误，不能重新写入而延长其过期时间
+	hit, _ := db.Statement.Settings.Load("gorm:cache:hit")
+	negative := p.config.NegativeTTL > 0 && db.RowsAffected == 0 && hit != true &&
+		(db.Error == nil || errors.Is(db.Error, gorm.ErrRecordNotFound))
+	if db.Error != nil && !negative {
 		return
 	}
 
 	// Skip if cache should be skipped
-	if p.config.shouldSkipCache(db) {
+	if p.shouldSkipCache(db) {
 		return
 	}
 
@@ -187,24 +480,66 @@ func (p *CachePlugin) afterQueryCallback(db *gorm.DB) {
 		return
 	}
 
-	// 不缓存空值结果
+	if !p.acceptsResult(db, db.RowsAffected) {
+		return
+	}
+
+	// 空结果只在配置了 NegativeTTL 时缓存
 	if db.RowsAffected == 0 {
+		if negative {
+			p.storeNegative(db, cacheKey)
+		}
 		return
 	}
 
+	if p.exceedsMaxRows(db.Statement.Dest) {
+		return
+	}
+
+	ttl, ok := p.cacheTTL(db)
+	if !ok {
+		return
+	}
+
+	ctx := p.statementContext(db)
+
+	// Let the application post-process the result before it is cached
+	if p.config.DBQueryHook != nil {
+		if err := p.config.DBQueryHook(ctx, db, db.Statement.Dest); err != nil {
+			return
+		}
+	}
+
 	// Serialize result using configured serializer
 	cachedData, err := p.config.Serializer.Marshal(db.Statement.Dest)
 	if err != nil {
+		p.stats.errors.Add(1)
 		return
 	}
 
+	p.storeRequestCache(ctx, cacheKey, cachedData)
+	shared = cachedData
+
 	// Store in cache
-	ctx := db.Statement.Context
-	if ctx == nil {
-		ctx = context.Background()
+	stored, err := p.storeCached(ctx, cacheKey, cachedData, ttl)
+	if err != nil {
+		p.stats.errors.Add(1)
+		p.onError(err)
+		return
+	}
+	if !stored {
+		return
 	}
+	p.stats.bytesStored.Add(int64(len(cachedData)))
+	p.onStore(cacheKey, ttl)
 
-	_ = p.config.Adapter.Set(ctx, cacheKey, cachedData, p.config.TTL)
+	if p.config.TraceQueries {
+		_ = p.storeTrace(ctx, cacheKey, ttl)
+	}
+
+	if tags := p.statementTags(db); len(tags) > 0 {
+		_ = p.tagKey(ctx, cacheKey, tags, ttl)
+	}
 }
 
 // invalidateCallback is executed after create/update/delete to invalidate cache
@@ -223,20 +558,61 @@ func (p *CachePlugin) invalidateCallback(db *gorm.DB) {
 	pattern := p.config.getModelPattern(db)
 
 	// Delete all cached queries for this model
-	ctx := db.Statement.Context
-	if ctx == nil {
-		ctx = context.Background()
+	if err := p.invalidateTable(p.statementContext(db), statementTable(db), pattern); err != nil {
+		p.onError(err)
+	}
+}
+
+// invalidatePattern invalidates the cached queries matching pattern, marking
+// them stale with SoftInvalidation and deleting them otherwise
+func (p *CachePlugin) invalidatePattern(ctx context.Context, pattern string) error {
+	if p.config.SoftInvalidation && p.softInvalidate(ctx, pattern) {
+		return nil
+	}
+
+	if p.config.AtomicInvalidation {
+		if adapter, ok := p.adapter().(atomicPatternDeleter); ok {
+			return adapter.DeletePatternAtomic(ctx, pattern)
+		}
 	}
 
-	_ = p.config.Adapter.DeletePattern(ctx, pattern)
+	return p.adapter().DeletePattern(ctx, pattern)
+}
+
+// statementContext returns the statement context, falling back to context.Background()
+// unless PanicOnNilContext is set
+func (p *CachePlugin) statementContext(db *gorm.DB) context.Context {
+	if db.Statement.Context == nil {
+		if p.config.PanicOnNilContext {
+			panic(errNilContext)
+		}
+		return context.Background()
+	}
+	return db.Statement.Context
 }
 
-// Close closes the cache adapter
+// Close closes the cache adapter and the subscription of BroadcastInvalidation,
+// and stops the refresh workers of RefreshThreshold
 func (p *CachePlugin) Close() error {
-	if p.config.Adapter != nil {
-		return p.config.Adapter.Close()
+	err := p.stopBroadcast()
+	if p.refreshAhead != nil {
+		p.refreshAhead.shutdown()
 	}
-	return nil
+	if adapter := p.adapter(); adapter != nil {
+		return errors.Join(err, adapter.Close())
+	}
+	return err
+}
+
+// acceptsResult reports whether CachePredicateFunc, if set, allows caching the
+// result of db
+func (p *CachePlugin) acceptsResult(db *gorm.DB, rowsAffected int64) bool {
+	return p.config.CachePredicateFunc == nil || p.config.CachePredicateFunc(db, rowsAffected)
+}
+
+// exceedsMaxRows reports whether dest holds more rows than MaxCacheableRows
+func (p *CachePlugin) exceedsMaxRows(dest any) bool {
+	return p.config.MaxCacheableRows > 0 && calculateRowsAffected(dest) > int64(p.config.MaxCacheableRows)
 }
 
 // calculateRowsAffected 计算从缓存恢复的数据的行数
@@ -252,6 +628,9 @@ func calculateRowsAffected(dest any) int64 {
 	case reflect.Struct:
 		// 单条记录，如果是有效的结构体则返回 1
 		return 1
+	case reflect.Int64:
+		// Count 的结果是单行
+		return 1
 	default:
 		return 0
 	}
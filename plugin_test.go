@@ -2,6 +2,8 @@ package gormcache
 
 import (
 	"context"
+	"reflect"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -28,6 +30,22 @@ func setupTestDB(t *testing.T) *gorm.DB {
 	return db
 }
 
+// countQueries counts the queries that actually reach the database, i.e. the
+// ones not answered by the cache plugin
+func countQueries(t *testing.T, db *gorm.DB) *int64 {
+	var count int64
+	err := db.Callback().Query().After("gorm:cache:query").Before("gorm:query").
+		Register("test:count_queries", func(db *gorm.DB) {
+			if db.Error == nil {
+				atomic.AddInt64(&count, 1)
+			}
+		})
+	if err != nil {
+		t.Fatalf("failed to register query counter: %v", err)
+	}
+	return &count
+}
+
 func TestBasicCaching(t *testing.T) {
 	db := setupTestDB(t)
 
@@ -161,6 +179,51 @@ func TestModelSelection(t *testing.T) {
 	}
 }
 
+func TestModelSelectionByType(t *testing.T) {
+	type otherModel struct{ ID uint }
+
+	tests := []struct {
+		name        string
+		cacheModels []interface{}
+		wantQueries int64
+	}{
+		{"instance", []interface{}{TestUser{}}, 1},
+		{"type", []interface{}{reflect.TypeOf(TestUser{})}, 1},
+		{"pointer type", []interface{}{reflect.TypeOf(&TestUser{})}, 1},
+		{"other type", []interface{}{reflect.TypeOf(otherModel{})}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := setupTestDB(t)
+
+			cachePlugin := New(Config{
+				Adapter:     NewMemoryAdapter(),
+				TTL:         5 * time.Minute,
+				CacheModels: tt.cacheModels,
+			})
+			if err := db.Use(cachePlugin); err != nil {
+				t.Fatalf("failed to install plugin: %v", err)
+			}
+			defer cachePlugin.Close()
+
+			db.Create(&TestUser{Name: "Test User"})
+			queries := countQueries(t, db)
+
+			for i := 0; i < 3; i++ {
+				var users []TestUser
+				db.Find(&users)
+				if len(users) != 1 {
+					t.Fatalf("expected 1 user, got %d", len(users))
+				}
+			}
+			if *queries != tt.wantQueries {
+				t.Errorf("expected %d database queries, got %d", tt.wantQueries, *queries)
+			}
+		})
+	}
+}
+
 func TestCustomSkipCondition(t *testing.T) {
 	db := setupTestDB(t)
 
@@ -203,3 +266,42 @@ func TestCustomSkipCondition(t *testing.T) {
 		t.Errorf("expected name 'Test User', got '%s'", user2.Name)
 	}
 }
+
+func TestForceCacheOverridesSkipCondition(t *testing.T) {
+	db := setupTestDB(t)
+
+	cachePlugin := New(Config{
+		Adapter:            NewMemoryAdapter(),
+		TTL:                5 * time.Minute,
+		SkipCacheCondition: func(db *gorm.DB) bool { return true },
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	db.Create(&TestUser{Name: "Test User"})
+	queries := countQueries(t, db)
+
+	var users []TestUser
+	db.Find(&users)
+	db.Find(&users)
+	if *queries != 2 {
+		t.Fatalf("expected SkipCacheCondition to skip the cache, got %d database queries", *queries)
+	}
+
+	// ForceCache 同时覆盖 SkipCache 和上下文中的跳过设置
+	ctx := WithSkipCache(context.Background(), true)
+	for i := 0; i < 3; i++ {
+		db.WithContext(ctx).Scopes(SkipCache(), ForceCache()).Find(&users)
+	}
+	if *queries != 3 {
+		t.Errorf("expected the forced query to hit the cache on later calls, got %d database queries", *queries)
+	}
+	if len(users) != 1 {
+		t.Errorf("expected 1 user, got %d", len(users))
+	}
+	if hits := cachePlugin.Stats().Hits; hits != 2 {
+		t.Errorf("expected 2 cache hits, got %d", hits)
+	}
+}
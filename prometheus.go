@@ -0,0 +1,72 @@
+package gormcache
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PrometheusCollector exports the cache counters of a CachePlugin as Prometheus
+// metrics, along with a histogram of adapter operation latencies
+// Usage: prometheus.MustRegister(gormcache.NewPrometheusCollector(cachePlugin, "myapp"))
+type PrometheusCollector struct {
+	plugin *CachePlugin
+
+	hits          *prometheus.Desc
+	misses        *prometheus.Desc
+	errors        *prometheus.Desc
+	invalidations *prometheus.Desc
+	latency       *prometheus.HistogramVec
+}
+
+// NewPrometheusCollector creates a collector for plugin with metric names
+// prefixed by namespace
+// The plugin reports adapter latencies to the most recently created collector
+func NewPrometheusCollector(plugin *CachePlugin, namespace string) *PrometheusCollector {
+	c := &PrometheusCollector{
+		plugin: plugin,
+		hits: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "cache_hits_total"),
+			"Number of queries served from the cache.", nil, nil),
+		misses: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "cache_misses_total"),
+			"Number of queries looked up in the cache and sent to the database.", nil, nil),
+		errors: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "cache_errors_total"),
+			"Number of cached values that could not be (de)serialized or stored.", nil, nil),
+		invalidations: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "cache_invalidations_total"),
+			"Number of writes that invalidated cached queries.", nil, nil),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "cache_latency_seconds",
+			Help:      "Latency of cache adapter operations.",
+			Buckets:   prometheus.ExponentialBuckets(0.0001, 4, 8),
+		}, []string{"operation"}),
+	}
+
+	observe := func(operation string, d time.Duration) {
+		c.latency.WithLabelValues(operation).Observe(d.Seconds())
+	}
+	plugin.stats.latency.Store(&observe)
+	return c
+}
+
+// Describe implements prometheus.Collector
+func (c *PrometheusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.hits
+	ch <- c.misses
+	ch <- c.errors
+	ch <- c.invalidations
+	c.latency.Describe(ch)
+}
+
+// Collect implements prometheus.Collector
+func (c *PrometheusCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.plugin.Stats()
+	ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(stats.Hits))
+	ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(stats.Misses))
+	ch <- prometheus.MustNewConstMetric(c.errors, prometheus.CounterValue, float64(stats.Errors))
+	ch <- prometheus.MustNewConstMetric(c.invalidations, prometheus.CounterValue, float64(stats.Invalidations))
+	c.latency.Collect(ch)
+}
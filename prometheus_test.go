@@ -0,0 +1,57 @@
+package gormcache
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestPrometheusCollector(t *testing.T) {
+	db := setupTestDB(t)
+
+	cachePlugin := New(Config{
+		Adapter: NewMemoryAdapter(),
+		TTL:     5 * time.Minute,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	collector := NewPrometheusCollector(cachePlugin, "myapp")
+
+	db.Create(&TestUser{Name: "John"})
+	var users []TestUser
+	db.Find(&users)
+	db.Find(&users)
+
+	// 4 个计数器 + 1 个直方图（get 与 set 两个标签值）
+	if n := testutil.CollectAndCount(collector); n != 6 {
+		t.Errorf("expected 6 metrics, got %d", n)
+	}
+
+	expected := `
+# HELP myapp_cache_hits_total Number of queries served from the cache.
+# TYPE myapp_cache_hits_total counter
+myapp_cache_hits_total 1
+# HELP myapp_cache_misses_total Number of queries looked up in the cache and sent to the database.
+# TYPE myapp_cache_misses_total counter
+myapp_cache_misses_total 1
+`
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"myapp_cache_hits_total", "myapp_cache_misses_total"); err != nil {
+		t.Error(err)
+	}
+
+	for _, name := range []string{
+		"myapp_cache_errors_total",
+		"myapp_cache_invalidations_total",
+		"myapp_cache_latency_seconds",
+	} {
+		if n := testutil.CollectAndCount(collector, name); n == 0 {
+			t.Errorf("expected metric family %s to be present", name)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package gormcache
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCacheRawQueries(t *testing.T) {
+	tests := []struct {
+		name            string
+		cacheRaw        bool
+		expectedQueries int64
+	}{
+		{"enabled", true, 1},
+		{"disabled", false, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := setupTestDB(t)
+			adapter := NewMemoryAdapter()
+			defer adapter.Close()
+			if err := db.Use(New(Config{Adapter: adapter, TTL: 5 * time.Minute, CacheRawQueries: tt.cacheRaw})); err != nil {
+				t.Fatalf("failed to install plugin: %v", err)
+			}
+
+			user := TestUser{Name: "Test User"}
+			db.Create(&user)
+			queries := countQueries(t, db)
+
+			for i := 0; i < 2; i++ {
+				var result TestUser
+				db.Raw("SELECT * FROM test_users WHERE id = ?", user.ID).Find(&result)
+				if result.Name != "Test User" {
+					t.Fatalf("expected 'Test User', got %q", result.Name)
+				}
+			}
+			if got := atomic.LoadInt64(queries); got != tt.expectedQueries {
+				t.Errorf("expected %d database queries, got %d", tt.expectedQueries, got)
+			}
+		})
+	}
+}
+
+func TestCacheRawQueriesInvalidation(t *testing.T) {
+	db := setupTestDB(t)
+	adapter := NewMemoryAdapter()
+	defer adapter.Close()
+	if err := db.Use(New(Config{Adapter: adapter, TTL: 5 * time.Minute, CacheRawQueries: true, InvalidateOnUpdate: true})); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+
+	user := TestUser{Name: "Original Name"}
+	db.Create(&user)
+
+	query := func() string {
+		var result TestUser
+		db.Raw("SELECT * FROM test_users WHERE id = ?", user.ID).Find(&result)
+		return result.Name
+	}
+	query()
+
+	// The raw query is cached in the table of its destination model
+	for key := range adapter.store {
+		if !strings.HasPrefix(key, "gorm:cache:test_users:") {
+			t.Errorf("expected key in test_users namespace, got %q", key)
+		}
+	}
+
+	db.Model(&user).Update("Name", "Updated Name")
+	if name := query(); name != "Updated Name" {
+		t.Errorf("expected 'Updated Name', got %q", name)
+	}
+}
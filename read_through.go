@@ -0,0 +1,128 @@
+package gormcache
+
+import (
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// readThroughLoader returns the loader registered by the ReadThrough scope
+func readThroughLoader(db *gorm.DB) (func(id uint) (interface{}, error), bool) {
+	v, ok := db.Statement.Settings.Load("gorm:cache:read_through")
+	if !ok {
+		return nil, false
+	}
+	loader, ok := v.(func(id uint) (interface{}, error))
+	return loader, ok && loader != nil
+}
+
+// loadThrough resolves a cache miss with the read-through loader and stores
+// the result in cache, reporting whether the query was answered
+func (p *CachePlugin) loadThrough(db *gorm.DB, cacheKey string, loader func(id uint) (interface{}, error)) bool {
+	id, ok := primaryKeyFromWhere(db)
+	if !ok || db.Statement.Dest == nil {
+		return false
+	}
+
+	result, err := loader(id)
+	if err != nil {
+		db.AddError(err)
+		return true
+	}
+
+	if err := assignResult(db.Statement.Dest, result); err != nil {
+		db.AddError(err)
+		return true
+	}
+
+	if ttl, ok := p.cacheTTL(db); ok {
+		if cachedData, err := p.config.Serializer.Marshal(db.Statement.Dest); err == nil {
+			_, _ = p.storeCached(p.statementContext(db), cacheKey, cachedData, ttl)
+		}
+	}
+
+	db.Error = &ErrCacheHit{RowsAffected: calculateRowsAffected(db.Statement.Dest)}
+	return true
+}
+
+// primaryKeyFromWhere extracts a single primary key value from the WHERE clause
+func primaryKeyFromWhere(db *gorm.DB) (uint, bool) {
+	c, ok := db.Statement.Clauses["WHERE"]
+	if !ok {
+		return 0, false
+	}
+	where, ok := c.Expression.(clause.Where)
+	if !ok {
+		return 0, false
+	}
+
+	for _, expr := range where.Exprs {
+		switch e := expr.(type) {
+		case clause.IN:
+			if len(e.Values) == 1 && isPrimaryKeyColumn(db, e.Column) {
+				return toUint(e.Values[0])
+			}
+		case clause.Eq:
+			if isPrimaryKeyColumn(db, e.Column) {
+				return toUint(e.Value)
+			}
+		}
+	}
+
+	return 0, false
+}
+
+// isPrimaryKeyColumn reports whether column refers to the primary key
+func isPrimaryKeyColumn(db *gorm.DB, column interface{}) bool {
+	var name string
+	switch c := column.(type) {
+	case clause.Column:
+		name = c.Name
+	case string:
+		name = c
+	default:
+		return false
+	}
+
+	if name == clause.PrimaryKey {
+		return true
+	}
+	if db.Statement.Schema != nil && db.Statement.Schema.PrioritizedPrimaryField != nil {
+		return name == db.Statement.Schema.PrioritizedPrimaryField.DBName
+	}
+	return false
+}
+
+// toUint converts an integer primary key value to uint
+func toUint(v interface{}) (uint, bool) {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if rv.Int() < 0 {
+			return 0, false
+		}
+		return uint(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return uint(rv.Uint()), true
+	default:
+		return 0, false
+	}
+}
+
+// assignResult copies a loader result into the query destination
+func assignResult(dest, result interface{}) error {
+	target := reflect.Indirect(reflect.ValueOf(dest))
+	value := reflect.ValueOf(result)
+	if value.Kind() == reflect.Ptr && value.Type().Elem() == target.Type() {
+		value = value.Elem()
+	}
+
+	if !value.IsValid() || !value.Type().AssignableTo(target.Type()) {
+		return fmt.Errorf("gorm:cache: read-through result of type %T cannot be assigned to %T", result, dest)
+	}
+
+	target.Set(value)
+	return nil
+}
@@ -0,0 +1,78 @@
+package gormcache
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestReadThrough(t *testing.T) {
+	db := setupTestDB(t)
+
+	cachePlugin := New(Config{
+		Adapter: NewMemoryAdapter(),
+		TTL:     5 * time.Minute,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	queries := countQueries(t, db)
+
+	var loads int
+	loader := func(id uint) (interface{}, error) {
+		loads++
+		return TestUser{ID: id, Name: "From Loader"}, nil
+	}
+
+	// The record does not exist in the database
+	var user1 TestUser
+	if err := db.Scopes(ReadThrough(loader)).First(&user1, 42).Error; err != nil {
+		t.Fatalf("failed to query: %v", err)
+	}
+	if user1.ID != 42 || user1.Name != "From Loader" {
+		t.Errorf("expected loader result, got %+v", user1)
+	}
+
+	// The loader result is cached
+	var user2 TestUser
+	if err := db.Scopes(ReadThrough(loader)).First(&user2, 42).Error; err != nil {
+		t.Fatalf("failed to query: %v", err)
+	}
+	if user2.Name != "From Loader" {
+		t.Errorf("expected cached loader result, got %+v", user2)
+	}
+
+	if loads != 1 {
+		t.Errorf("expected loader to be called once, got %d", loads)
+	}
+	if got := atomic.LoadInt64(queries); got != 0 {
+		t.Errorf("expected no database queries, got %d", got)
+	}
+}
+
+func TestReadThroughLoaderError(t *testing.T) {
+	db := setupTestDB(t)
+
+	cachePlugin := New(Config{
+		Adapter: NewMemoryAdapter(),
+		TTL:     5 * time.Minute,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	errLoader := errors.New("upstream unavailable")
+	loader := func(id uint) (interface{}, error) {
+		return nil, errLoader
+	}
+
+	var user TestUser
+	err := db.Scopes(ReadThrough(loader)).First(&user, 1).Error
+	if !errors.Is(err, errLoader) {
+		t.Errorf("expected loader error, got %v", err)
+	}
+}
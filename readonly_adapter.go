@@ -0,0 +1,52 @@
+package gormcache
+
+import (
+	"context"
+	"time"
+)
+
+// ReadOnlyAdapter wraps an adapter and turns all write operations into no-ops,
+// for deployments that serve cached data but must never modify it
+type ReadOnlyAdapter struct {
+	inner Adapter
+}
+
+// NewReadOnlyAdapter creates a new read-only view of inner
+func NewReadOnlyAdapter(inner Adapter) *ReadOnlyAdapter {
+	return &ReadOnlyAdapter{inner: inner}
+}
+
+// Get retrieves a value from the inner adapter
+func (a *ReadOnlyAdapter) Get(ctx context.Context, key string) ([]byte, error) {
+	return a.inner.Get(ctx, key)
+}
+
+// Set is a no-op
+func (a *ReadOnlyAdapter) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return nil
+}
+
+// Delete is a no-op
+func (a *ReadOnlyAdapter) Delete(ctx context.Context, key string) error {
+	return nil
+}
+
+// DeletePattern is a no-op
+func (a *ReadOnlyAdapter) DeletePattern(ctx context.Context, pattern string) error {
+	return nil
+}
+
+// Clear is a no-op
+func (a *ReadOnlyAdapter) Clear(ctx context.Context) error {
+	return nil
+}
+
+// Close closes the inner adapter
+func (a *ReadOnlyAdapter) Close() error {
+	return a.inner.Close()
+}
+
+// Unwrap returns the inner adapter
+func (a *ReadOnlyAdapter) Unwrap() Adapter {
+	return a.inner
+}
@@ -0,0 +1,76 @@
+package gormcache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestReadOnlyAdapter(t *testing.T) {
+	inner := NewMemoryAdapter()
+	adapter := NewReadOnlyAdapter(inner)
+	defer adapter.Close()
+
+	ctx := context.Background()
+
+	inner.Set(ctx, "user:1", []byte("value1"), 1*time.Minute)
+
+	// Reads are delegated to the inner adapter
+	if value, err := adapter.Get(ctx, "user:1"); err != nil || string(value) != "value1" {
+		t.Fatalf("expected value1, got %q, %v", value, err)
+	}
+
+	// Writes are silently ignored
+	if err := adapter.Set(ctx, "user:2", []byte("value2"), 1*time.Minute); err != nil {
+		t.Errorf("expected Set to return nil, got %v", err)
+	}
+	if _, err := inner.Get(ctx, "user:2"); err == nil {
+		t.Error("expected Set not to reach the inner adapter")
+	}
+
+	if err := adapter.Delete(ctx, "user:1"); err != nil {
+		t.Errorf("expected Delete to return nil, got %v", err)
+	}
+	if err := adapter.DeletePattern(ctx, "user:*"); err != nil {
+		t.Errorf("expected DeletePattern to return nil, got %v", err)
+	}
+	if err := adapter.Clear(ctx); err != nil {
+		t.Errorf("expected Clear to return nil, got %v", err)
+	}
+	if _, err := inner.Get(ctx, "user:1"); err != nil {
+		t.Error("expected user:1 to survive Delete, DeletePattern and Clear")
+	}
+}
+
+func TestReadOnlyConfig(t *testing.T) {
+	db := setupTestDB(t)
+
+	inner := NewMemoryAdapter()
+	cachePlugin := New(Config{
+		Adapter:  inner,
+		TTL:      5 * time.Minute,
+		ReadOnly: true,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	if _, ok := cachePlugin.config.Adapter.(*ReadOnlyAdapter); !ok {
+		t.Fatalf("expected adapter to be wrapped, got %T", cachePlugin.config.Adapter)
+	}
+
+	user := TestUser{Name: "Read Only"}
+	db.Create(&user)
+
+	var result TestUser
+	db.First(&result, user.ID)
+	if result.Name != "Read Only" {
+		t.Fatalf("expected 'Read Only', got '%s'", result.Name)
+	}
+
+	// Query results are never stored
+	if len(inner.store) != 0 {
+		t.Errorf("expected no cached entries, got %d", len(inner.store))
+	}
+}
@@ -2,11 +2,21 @@ package gormcache
 
 import (
 	"context"
+	"fmt"
+	"path"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
+const (
+	// snapshotPollInterval is how often Snapshot checks LASTSAVE
+	snapshotPollInterval = 100 * time.Millisecond
+
+	// defaultSnapshotTimeout bounds Snapshot when the context has no deadline
+	defaultSnapshotTimeout = 5 * time.Minute
+)
+
 // RedisAdapter is a Redis cache implementation
 type RedisAdapter struct {
 	client *redis.Client
@@ -17,6 +27,15 @@ type RedisAdapterConfig struct {
 	Addr     string // Redis server address (default: "localhost:6379")
 	Password string // Redis password (default: "")
 	DB       int    // Redis database (default: 0)
+
+	// Retries of commands failing with network or retryable server errors
+	MaxRetries      int           // Maximum retries (default: 3, -1 disables retries)
+	MinRetryBackoff time.Duration // Minimum backoff between retries (default: 8ms, -1 disables backoff)
+	MaxRetryBackoff time.Duration // Maximum backoff between retries (default: 512ms, -1 disables backoff)
+
+	DialTimeout  time.Duration // Timeout for establishing connections (default: 5s)
+	ReadTimeout  time.Duration // Timeout for socket reads (default: 3s, -1 disables the timeout)
+	WriteTimeout time.Duration // Timeout for socket writes (default: ReadTimeout)
 }
 
 // NewRedisAdapter creates a new Redis cache adapter
@@ -26,9 +45,15 @@ func NewRedisAdapter(config RedisAdapterConfig) *RedisAdapter {
 	}
 
 	client := redis.NewClient(&redis.Options{
-		Addr:     config.Addr,
-		Password: config.Password,
-		DB:       config.DB,
+		Addr:            config.Addr,
+		Password:        config.Password,
+		DB:              config.DB,
+		MaxRetries:      config.MaxRetries,
+		MinRetryBackoff: config.MinRetryBackoff,
+		MaxRetryBackoff: config.MaxRetryBackoff,
+		DialTimeout:     config.DialTimeout,
+		ReadTimeout:     config.ReadTimeout,
+		WriteTimeout:    config.WriteTimeout,
 	})
 
 	return &RedisAdapter{
@@ -43,11 +68,21 @@ func NewRedisAdapterWithClient(client *redis.Client) *RedisAdapter {
 	}
 }
 
+// Ping checks that the Redis server is reachable
+func (r *RedisAdapter) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
+
+// Incr atomically increments the integer stored at key and returns the new value
+func (r *RedisAdapter) Incr(ctx context.Context, key string) (int64, error) {
+	return r.client.Incr(ctx, key).Result()
+}
+
 // Get retrieves a value from Redis cache
 func (r *RedisAdapter) Get(ctx context.Context, key string) ([]byte, error) {
 	val, err := r.client.Get(ctx, key).Bytes()
 	if err == redis.Nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %w", ErrCacheMiss, err)
 	}
 	return val, err
 }
@@ -62,6 +97,84 @@ func (r *RedisAdapter) Delete(ctx context.Context, key string) error {
 	return r.client.Del(ctx, key).Err()
 }
 
+// Exists reports whether key is cached
+func (r *RedisAdapter) Exists(ctx context.Context, key string) (bool, error) {
+	n, err := r.client.Exists(ctx, key).Result()
+	return n > 0, err
+}
+
+// TTL returns the remaining lifetime of key, 0 if it never expires
+func (r *RedisAdapter) TTL(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := r.client.TTL(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	// -2 表示 key 不存在，-1 表示没有过期时间
+	switch ttl {
+	case -2:
+		return 0, fmt.Errorf("%w: key not found", ErrCacheMiss)
+	case -1:
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+// MGet retrieves the values of the given keys in a single pipeline
+func (r *RedisAdapter) MGet(ctx context.Context, keys []string) (map[string][]byte, error) {
+	values := make(map[string][]byte, len(keys))
+	if len(keys) == 0 {
+		return values, nil
+	}
+
+	cmds := make([]*redis.StringCmd, len(keys))
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, key := range keys {
+			cmds[i] = pipe.Get(ctx, key)
+		}
+		return nil
+	})
+	// Pipelined 返回第一个失败命令的错误，缺失的 key 也会返回 redis.Nil
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	for i, cmd := range cmds {
+		value, err := cmd.Bytes()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		values[keys[i]] = value
+	}
+
+	return values, nil
+}
+
+// MSet stores all items with the given TTL in a single pipeline
+func (r *RedisAdapter) MSet(ctx context.Context, items map[string][]byte, ttl time.Duration) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for key, value := range items {
+			pipe.Set(ctx, key, value, ttl)
+		}
+		return nil
+	})
+	return err
+}
+
+// MDelete removes the given keys with a single DEL
+func (r *RedisAdapter) MDelete(ctx context.Context, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return r.client.Del(ctx, keys...).Err()
+}
+
 // DeletePattern removes all keys matching the pattern
 func (r *RedisAdapter) DeletePattern(ctx context.Context, pattern string) error {
 	iter := r.client.Scan(ctx, 0, pattern, 0).Iterator()
@@ -79,11 +192,110 @@ func (r *RedisAdapter) DeletePattern(ctx context.Context, pattern string) error
 	return err
 }
 
+// Scan returns the keys matching the pattern, iterating with SCAN
+func (r *RedisAdapter) Scan(ctx context.Context, pattern string) ([]string, error) {
+	var keys []string
+	iter := r.client.Scan(ctx, 0, pattern, 0).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+
+	return keys, iter.Err()
+}
+
+// DeletePatternAtomic removes all keys matching the pattern in a single MULTI/EXEC
+// transaction, so an interrupted invalidation deletes either all of them or none
+func (r *RedisAdapter) DeletePatternAtomic(ctx context.Context, pattern string) error {
+	keys, err := r.Scan(ctx, pattern)
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range keys {
+			pipe.Del(ctx, key)
+		}
+		return nil
+	})
+	return err
+}
+
 // Clear removes all cached data in the current database
 func (r *RedisAdapter) Clear(ctx context.Context) error {
 	return r.client.FlushDB(ctx).Err()
 }
 
+// Count returns the number of keys in the current database
+func (r *RedisAdapter) Count(ctx context.Context) (int, error) {
+	n, err := r.client.DBSize(ctx).Result()
+	return int(n), err
+}
+
+// CountPattern returns the number of keys matching the pattern
+// Unlike Count, it only counts the keys of one namespace when the database is shared
+func (r *RedisAdapter) CountPattern(ctx context.Context, pattern string) (int, error) {
+	iter := r.client.Scan(ctx, 0, pattern, 0).Iterator()
+
+	n := 0
+	for iter.Next(ctx) {
+		n++
+	}
+
+	return n, iter.Err()
+}
+
+// Snapshot triggers a background save with BGSAVE, waits until LASTSAVE reports
+// it completed and returns the path of the RDB file on the Redis server
+// The wait is bounded by the context deadline, or 5 minutes if it has none
+func (r *RedisAdapter) Snapshot(ctx context.Context) (string, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultSnapshotTimeout)
+		defer cancel()
+	}
+
+	before, err := r.client.LastSave(ctx).Result()
+	if err != nil {
+		return "", err
+	}
+	if err := r.client.BgSave(ctx).Err(); err != nil {
+		return "", err
+	}
+
+	ticker := time.NewTicker(snapshotPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-ticker.C:
+		}
+
+		last, err := r.client.LastSave(ctx).Result()
+		if err != nil {
+			return "", err
+		}
+		if last != before {
+			break
+		}
+	}
+
+	dir, err := r.client.ConfigGet(ctx, "dir").Result()
+	if err != nil {
+		return "", err
+	}
+	dbfilename, err := r.client.ConfigGet(ctx, "dbfilename").Result()
+	if err != nil {
+		return "", err
+	}
+
+	return path.Join(dir["dir"], dbfilename["dbfilename"]), nil
+}
+
 // Close closes the Redis connection
 func (r *RedisAdapter) Close() error {
 	return r.client.Close()
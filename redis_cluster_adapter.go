@@ -0,0 +1,147 @@
+package gormcache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisClusterAdapter is a Redis Cluster cache implementation
+// Pattern operations scan every master node, as each node only holds the keys
+// of its own hash slots
+type RedisClusterAdapter struct {
+	client *redis.ClusterClient
+}
+
+// RedisClusterAdapterConfig holds configuration for Redis Cluster adapter
+type RedisClusterAdapterConfig struct {
+	Addrs    []string // Seed addresses of cluster nodes (default: ["localhost:6379"])
+	Password string   // Redis password (default: "")
+	PoolSize int      // Connections per node (default: 10 per CPU)
+}
+
+// NewRedisClusterAdapter creates a new Redis Cluster cache adapter
+func NewRedisClusterAdapter(config RedisClusterAdapterConfig) *RedisClusterAdapter {
+	if len(config.Addrs) == 0 {
+		config.Addrs = []string{"localhost:6379"}
+	}
+
+	client := redis.NewClusterClient(&redis.ClusterOptions{
+		Addrs:    config.Addrs,
+		Password: config.Password,
+		PoolSize: config.PoolSize,
+	})
+
+	return &RedisClusterAdapter{
+		client: client,
+	}
+}
+
+// NewRedisClusterAdapterWithClient creates a new Redis Cluster adapter with existing client
+func NewRedisClusterAdapterWithClient(client *redis.ClusterClient) *RedisClusterAdapter {
+	return &RedisClusterAdapter{
+		client: client,
+	}
+}
+
+// Ping checks that every master node of the cluster is reachable
+func (r *RedisClusterAdapter) Ping(ctx context.Context) error {
+	return r.client.ForEachMaster(ctx, func(ctx context.Context, shard *redis.Client) error {
+		return shard.Ping(ctx).Err()
+	})
+}
+
+// Incr atomically increments the integer stored at key and returns the new value
+func (r *RedisClusterAdapter) Incr(ctx context.Context, key string) (int64, error) {
+	return r.client.Incr(ctx, key).Result()
+}
+
+// Get retrieves a value from Redis Cluster cache
+func (r *RedisClusterAdapter) Get(ctx context.Context, key string) ([]byte, error) {
+	val, err := r.client.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		return nil, fmt.Errorf("%w: %w", ErrCacheMiss, err)
+	}
+	return val, err
+}
+
+// Exists reports whether key is cached
+func (r *RedisClusterAdapter) Exists(ctx context.Context, key string) (bool, error) {
+	n, err := r.client.Exists(ctx, key).Result()
+	return n > 0, err
+}
+
+// Set stores a value in Redis Cluster cache
+func (r *RedisClusterAdapter) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return r.client.Set(ctx, key, value, ttl).Err()
+}
+
+// Delete removes a value from Redis Cluster cache
+func (r *RedisClusterAdapter) Delete(ctx context.Context, key string) error {
+	return r.client.Del(ctx, key).Err()
+}
+
+// DeletePattern removes all keys matching the pattern from every master node
+func (r *RedisClusterAdapter) DeletePattern(ctx context.Context, pattern string) error {
+	return r.client.ForEachMaster(ctx, func(ctx context.Context, shard *redis.Client) error {
+		iter := shard.Scan(ctx, 0, pattern, 0).Iterator()
+
+		// 每个 DEL 只包含一个 key，不会跨 slot
+		pipe := shard.Pipeline()
+		for iter.Next(ctx) {
+			pipe.Del(ctx, iter.Val())
+		}
+
+		if err := iter.Err(); err != nil {
+			return err
+		}
+
+		_, err := pipe.Exec(ctx)
+		return err
+	})
+}
+
+// Scan returns the keys matching the pattern on every master node
+func (r *RedisClusterAdapter) Scan(ctx context.Context, pattern string) ([]string, error) {
+	var (
+		mu   sync.Mutex
+		keys []string
+	)
+	err := r.client.ForEachMaster(ctx, func(ctx context.Context, shard *redis.Client) error {
+		iter := shard.Scan(ctx, 0, pattern, 0).Iterator()
+		for iter.Next(ctx) {
+			mu.Lock()
+			keys = append(keys, iter.Val())
+			mu.Unlock()
+		}
+		return iter.Err()
+	})
+	return keys, err
+}
+
+// Clear removes all cached data from every master node
+func (r *RedisClusterAdapter) Clear(ctx context.Context) error {
+	return r.client.ForEachMaster(ctx, func(ctx context.Context, shard *redis.Client) error {
+		return shard.FlushDB(ctx).Err()
+	})
+}
+
+// Count returns the number of keys held by all master nodes
+func (r *RedisClusterAdapter) Count(ctx context.Context) (int, error) {
+	var total atomic.Int64
+	err := r.client.ForEachMaster(ctx, func(ctx context.Context, shard *redis.Client) error {
+		n, err := shard.DBSize(ctx).Result()
+		total.Add(n)
+		return err
+	})
+	return int(total.Load()), err
+}
+
+// Close closes the Redis Cluster connection
+func (r *RedisClusterAdapter) Close() error {
+	return r.client.Close()
+}
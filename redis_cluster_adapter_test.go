@@ -0,0 +1,55 @@
+package gormcache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+// miniredis 以单节点集群响应 CLUSTER 命令，持有全部 slot
+func TestRedisClusterAdapter(t *testing.T) {
+	mr := miniredis.RunT(t)
+
+	adapter := NewRedisClusterAdapter(RedisClusterAdapterConfig{Addrs: []string{mr.Addr()}})
+	defer adapter.Close()
+
+	ctx := context.Background()
+	if err := adapter.Ping(ctx); err != nil {
+		t.Fatalf("failed to ping: %v", err)
+	}
+
+	if _, err := adapter.Get(ctx, "missing"); !errors.Is(err, ErrCacheMiss) {
+		t.Errorf("expected ErrCacheMiss, got %v", err)
+	}
+
+	for _, key := range []string{"users:1", "users:2", "{orders}:1"} {
+		if err := adapter.Set(ctx, key, []byte("value"), time.Minute); err != nil {
+			t.Fatalf("failed to set %s: %v", key, err)
+		}
+	}
+	if value, err := adapter.Get(ctx, "users:1"); err != nil || string(value) != "value" {
+		t.Fatalf("expected value, got %q (%v)", value, err)
+	}
+
+	if err := adapter.DeletePattern(ctx, "users:*"); err != nil {
+		t.Fatalf("failed to delete pattern: %v", err)
+	}
+	for _, key := range []string{"users:1", "users:2"} {
+		if _, err := adapter.Get(ctx, key); err == nil {
+			t.Errorf("expected %s to be deleted", key)
+		}
+	}
+	if n, err := adapter.Count(ctx); err != nil || n != 1 {
+		t.Errorf("expected 1 remaining key, got %d (%v)", n, err)
+	}
+
+	if err := adapter.Clear(ctx); err != nil {
+		t.Fatalf("failed to clear: %v", err)
+	}
+	if n, _ := adapter.Count(ctx); n != 0 {
+		t.Errorf("expected no keys after Clear, got %d", n)
+	}
+}
@@ -0,0 +1,114 @@
+//go:build integration
+
+package gormcache
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/redis/go-redis/v9"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// clusterPorts are the ports of the three masters and three replicas of the
+// grokzen/redis-cluster image
+var clusterPorts = []string{"7000", "7001", "7002", "7003", "7004", "7005"}
+
+// setupRedisCluster starts a six node Redis Cluster container and returns an
+// adapter whose client maps the node addresses announced by the cluster to the
+// container's mapped ports; the test is skipped if the container cannot start
+func setupRedisCluster(t *testing.T) *RedisClusterAdapter {
+	t.Helper()
+	ctx := context.Background()
+
+	var exposed []string
+	for _, port := range clusterPorts {
+		exposed = append(exposed, port+"/tcp")
+	}
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        "grokzen/redis-cluster:7.0.10",
+			Env:          map[string]string{"IP": "0.0.0.0"},
+			ExposedPorts: exposed,
+			WaitingFor:   wait.ForLog("Cluster state changed: ok").WithStartupTimeout(2 * time.Minute),
+		},
+		Started: true,
+	})
+	if err != nil {
+		t.Skipf("redis cluster container is unavailable: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = container.Terminate(context.Background())
+	})
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		t.Fatalf("failed to get container host: %v", err)
+	}
+
+	client := redis.NewClusterClient(&redis.ClusterOptions{
+		Addrs: []string{"127.0.0.1:7000"},
+		Dialer: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			_, port, err := net.SplitHostPort(addr)
+			if err != nil {
+				return nil, err
+			}
+			mapped, err := container.MappedPort(ctx, nat.Port(port+"/tcp"))
+			if err != nil {
+				return nil, err
+			}
+			var d net.Dialer
+			return d.DialContext(ctx, network, net.JoinHostPort(host, mapped.Port()))
+		},
+	})
+
+	adapter := NewRedisClusterAdapterWithClient(client)
+	t.Cleanup(func() {
+		_ = adapter.Close()
+	})
+
+	return adapter
+}
+
+func TestRedisClusterIntegrationDeletePattern(t *testing.T) {
+	adapter := setupRedisCluster(t)
+	ctx := context.Background()
+
+	// 100 个 key 分布在三个 master 的不同 slot 上
+	for i := 0; i < 100; i++ {
+		if err := adapter.Set(ctx, fmt.Sprintf("users:%d", i), []byte("value"), time.Minute); err != nil {
+			t.Fatalf("failed to set: %v", err)
+		}
+	}
+	if err := adapter.Set(ctx, "orders:1", []byte("value"), time.Minute); err != nil {
+		t.Fatalf("failed to set: %v", err)
+	}
+
+	shards := 0
+	err := adapter.client.ForEachMaster(ctx, func(ctx context.Context, shard *redis.Client) error {
+		if n, err := shard.DBSize(ctx).Result(); err == nil && n > 0 {
+			shards++
+		}
+		return nil
+	})
+	if err != nil || shards < 2 {
+		t.Fatalf("expected keys on several shards, got %d (%v)", shards, err)
+	}
+
+	if err := adapter.DeletePattern(ctx, "users:*"); err != nil {
+		t.Fatalf("failed to delete pattern: %v", err)
+	}
+	for i := 0; i < 100; i++ {
+		if _, err := adapter.Get(ctx, fmt.Sprintf("users:%d", i)); err == nil {
+			t.Fatalf("expected users:%d to be deleted", i)
+		}
+	}
+	if _, err := adapter.Get(ctx, "orders:1"); err != nil {
+		t.Errorf("expected orders:1 to be kept: %v", err)
+	}
+}
@@ -0,0 +1,233 @@
+//go:build integration
+
+package gormcache
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/testcontainers/testcontainers-go"
+	tcredis "github.com/testcontainers/testcontainers-go/modules/redis"
+)
+
+// setupRedisContainer starts a Redis container and returns an adapter whose
+// client resolves the container endpoint on every dial, so it can reconnect
+// after the container is restarted on a different host port
+func setupRedisContainer(t *testing.T) (*tcredis.RedisContainer, *RedisAdapter) {
+	t.Helper()
+	ctx := context.Background()
+
+	container, err := tcredis.RunContainer(ctx, testcontainers.WithImage("redis:7-alpine"))
+	if err != nil {
+		t.Fatalf("failed to start redis container: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = container.Terminate(context.Background())
+	})
+
+	client := redis.NewClient(&redis.Options{
+		Dialer: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			endpoint, err := container.Endpoint(ctx, "")
+			if err != nil {
+				return nil, err
+			}
+			var d net.Dialer
+			return d.DialContext(ctx, network, endpoint)
+		},
+		MaxRetries:   5,
+		DialTimeout:  2 * time.Second,
+		ReadTimeout:  time.Second,
+		WriteTimeout: time.Second,
+	})
+
+	adapter := NewRedisAdapterWithClient(client)
+	t.Cleanup(func() {
+		_ = adapter.Close()
+	})
+
+	return container, adapter
+}
+
+func TestRedisIntegrationBasic(t *testing.T) {
+	_, adapter := setupRedisContainer(t)
+	ctx := context.Background()
+
+	if err := adapter.Set(ctx, "test:key", []byte("value"), time.Minute); err != nil {
+		t.Fatalf("failed to set: %v", err)
+	}
+
+	value, err := adapter.Get(ctx, "test:key")
+	if err != nil {
+		t.Fatalf("failed to get: %v", err)
+	}
+	if string(value) != "value" {
+		t.Errorf("expected 'value', got '%s'", value)
+	}
+
+	if err := adapter.Delete(ctx, "test:key"); err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+	if _, err := adapter.Get(ctx, "test:key"); err == nil {
+		t.Error("expected error for deleted key, got nil")
+	}
+}
+
+func TestRedisIntegrationDeletePattern(t *testing.T) {
+	_, adapter := setupRedisContainer(t)
+	ctx := context.Background()
+
+	for i := 0; i < 1000; i++ {
+		if err := adapter.Set(ctx, fmt.Sprintf("users:%d", i), []byte("value"), time.Minute); err != nil {
+			t.Fatalf("failed to set: %v", err)
+		}
+	}
+	adapter.Set(ctx, "orders:1", []byte("value"), time.Minute)
+
+	if err := adapter.DeletePattern(ctx, "users:*"); err != nil {
+		t.Fatalf("failed to delete pattern: %v", err)
+	}
+
+	keys, err := adapter.client.Keys(ctx, "users:*").Result()
+	if err != nil {
+		t.Fatalf("failed to list keys: %v", err)
+	}
+	if len(keys) != 0 {
+		t.Errorf("expected all 1000 user keys to be deleted, %d remain", len(keys))
+	}
+	if _, err := adapter.Get(ctx, "orders:1"); err != nil {
+		t.Error("expected orders:1 to exist")
+	}
+}
+
+func TestRedisIntegrationTTL(t *testing.T) {
+	_, adapter := setupRedisContainer(t)
+	ctx := context.Background()
+
+	adapter.Set(ctx, "test:ttl", []byte("value"), 500*time.Millisecond)
+	if _, err := adapter.Get(ctx, "test:ttl"); err != nil {
+		t.Fatalf("failed to get: %v", err)
+	}
+
+	time.Sleep(time.Second)
+	if _, err := adapter.Get(ctx, "test:ttl"); err == nil {
+		t.Error("expected error for expired key, got nil")
+	}
+}
+
+func TestRedisIntegrationConcurrency(t *testing.T) {
+	_, adapter := setupRedisContainer(t)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 50)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := fmt.Sprintf("concurrent:%d", i%10)
+			for j := 0; j < 20; j++ {
+				value := []byte(fmt.Sprintf("value:%d", i%10))
+				if err := adapter.Set(ctx, key, value, time.Minute); err != nil {
+					errs <- err
+					return
+				}
+				got, err := adapter.Get(ctx, key)
+				if err != nil {
+					errs <- err
+					return
+				}
+				if string(got) != string(value) {
+					errs <- fmt.Errorf("key %s: expected %s, got %s", key, value, got)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestRedisIntegrationPause(t *testing.T) {
+	container, adapter := setupRedisContainer(t)
+	ctx := context.Background()
+
+	adapter.Set(ctx, "test:key", []byte("value"), time.Minute)
+
+	docker, err := testcontainers.NewDockerClientWithOpts(ctx)
+	if err != nil {
+		t.Fatalf("failed to create docker client: %v", err)
+	}
+	defer docker.Close()
+
+	if err := docker.ContainerPause(ctx, container.GetContainerID()); err != nil {
+		t.Fatalf("failed to pause container: %v", err)
+	}
+
+	// Operations fail with a timeout while Redis is paused
+	opCtx, cancel := context.WithTimeout(ctx, 500*time.Millisecond)
+	_, err = adapter.Get(opCtx, "test:key")
+	cancel()
+	if err == nil {
+		t.Error("expected error while redis is paused, got nil")
+	}
+
+	if err := docker.ContainerUnpause(ctx, container.GetContainerID()); err != nil {
+		t.Fatalf("failed to unpause container: %v", err)
+	}
+
+	// The adapter recovers once Redis is back
+	value, err := adapter.Get(ctx, "test:key")
+	if err != nil {
+		t.Fatalf("failed to get after unpause: %v", err)
+	}
+	if string(value) != "value" {
+		t.Errorf("expected 'value', got '%s'", value)
+	}
+}
+
+func TestRedisIntegrationRestart(t *testing.T) {
+	container, adapter := setupRedisContainer(t)
+	ctx := context.Background()
+
+	if err := adapter.Set(ctx, "test:key", []byte("value"), time.Minute); err != nil {
+		t.Fatalf("failed to set: %v", err)
+	}
+
+	timeout := 5 * time.Second
+	if err := container.Stop(ctx, &timeout); err != nil {
+		t.Fatalf("failed to stop container: %v", err)
+	}
+	if err := container.Start(ctx); err != nil {
+		t.Fatalf("failed to start container: %v", err)
+	}
+
+	// Redis without persistence loses its data, but the adapter reconnects
+	var lastErr error
+	for i := 0; i < 20; i++ {
+		if lastErr = adapter.Set(ctx, "test:key", []byte("after restart"), time.Minute); lastErr == nil {
+			break
+		}
+		time.Sleep(250 * time.Millisecond)
+	}
+	if lastErr != nil {
+		t.Fatalf("adapter did not reconnect after restart: %v", lastErr)
+	}
+
+	value, err := adapter.Get(ctx, "test:key")
+	if err != nil {
+		t.Fatalf("failed to get after restart: %v", err)
+	}
+	if !strings.EqualFold(string(value), "after restart") {
+		t.Errorf("expected 'after restart', got '%s'", value)
+	}
+}
@@ -0,0 +1,117 @@
+package gormcache
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// brokenConn is a connection whose writes fail as if the server hung up
+type brokenConn struct {
+	net.Conn
+}
+
+func (c *brokenConn) Write([]byte) (int, error) {
+	return 0, io.EOF
+}
+
+// injectConnectionErrors breaks the first `failures` connections dialed by the
+// adapter and returns the number of dials
+func injectConnectionErrors(adapter *RedisAdapter, failures int64) *atomic.Int64 {
+	var dials atomic.Int64
+	adapter.client.AddHook(dialHook(func(next redis.DialHook) redis.DialHook {
+		return func(ctx context.Context, network, addr string) (net.Conn, error) {
+			conn, err := next(ctx, network, addr)
+			if err != nil {
+				return nil, err
+			}
+			if dials.Add(1) <= failures {
+				return &brokenConn{Conn: conn}, nil
+			}
+			return conn, nil
+		}
+	}))
+	return &dials
+}
+
+// dialHook is a redis.Hook only hooking dials
+type dialHook func(next redis.DialHook) redis.DialHook
+
+func (h dialHook) DialHook(next redis.DialHook) redis.DialHook { return h(next) }
+
+func (h dialHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook { return next }
+
+func (h dialHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+func TestRedisAdapterRetries(t *testing.T) {
+	mr := miniredis.RunT(t)
+
+	adapter := NewRedisAdapter(RedisAdapterConfig{
+		Addr:            mr.Addr(),
+		MaxRetries:      2,
+		MinRetryBackoff: time.Millisecond,
+		MaxRetryBackoff: 2 * time.Millisecond,
+	})
+	defer adapter.Close()
+
+	// Two failures are absorbed by two retries
+	dials := injectConnectionErrors(adapter, 2)
+	if err := adapter.Set(context.Background(), "key", []byte("value"), time.Minute); err != nil {
+		t.Fatalf("expected the retries to succeed, got %v", err)
+	}
+	if got := dials.Load(); got != 3 {
+		t.Errorf("expected 3 attempts, got %d", got)
+	}
+	if value, _ := mr.Get("key"); value != "value" {
+		t.Errorf("expected the value to be stored, got %q", value)
+	}
+}
+
+func TestRedisAdapterRetriesExhausted(t *testing.T) {
+	mr := miniredis.RunT(t)
+
+	adapter := NewRedisAdapter(RedisAdapterConfig{
+		Addr:            mr.Addr(),
+		MaxRetries:      2,
+		MinRetryBackoff: time.Millisecond,
+		MaxRetryBackoff: 2 * time.Millisecond,
+	})
+	defer adapter.Close()
+
+	// A third failure is propagated after MaxRetries retries
+	dials := injectConnectionErrors(adapter, 3)
+	if err := adapter.Set(context.Background(), "key", []byte("value"), time.Minute); err == nil {
+		t.Fatal("expected the error to be propagated")
+	}
+	if got := dials.Load(); got != 3 {
+		t.Errorf("expected 3 attempts, got %d", got)
+	}
+}
+
+func TestRedisAdapterOptions(t *testing.T) {
+	adapter := NewRedisAdapter(RedisAdapterConfig{
+		MaxRetries:      5,
+		MinRetryBackoff: 10 * time.Millisecond,
+		MaxRetryBackoff: time.Second,
+		DialTimeout:     2 * time.Second,
+		ReadTimeout:     3 * time.Second,
+		WriteTimeout:    4 * time.Second,
+	})
+	defer adapter.Close()
+
+	opts := adapter.client.Options()
+	if opts.MaxRetries != 5 || opts.MinRetryBackoff != 10*time.Millisecond || opts.MaxRetryBackoff != time.Second {
+		t.Errorf("retry options not wired: %+v", opts)
+	}
+	if opts.DialTimeout != 2*time.Second || opts.ReadTimeout != 3*time.Second || opts.WriteTimeout != 4*time.Second {
+		t.Errorf("timeout options not wired: %+v", opts)
+	}
+}
@@ -0,0 +1,59 @@
+package gormcache
+
+import (
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisSentinelAdapter is a Redis cache implementation for deployments where
+// Sentinel monitors the master; it follows failovers to the new master
+type RedisSentinelAdapter struct {
+	*RedisAdapter
+}
+
+// RedisSentinelAdapterConfig holds configuration for Redis Sentinel adapter
+type RedisSentinelAdapterConfig struct {
+	MasterName    string   // Name of the master monitored by Sentinel
+	SentinelAddrs []string // Sentinel addresses (default: ["localhost:26379"])
+	Password      string   // Redis password (default: "")
+	DB            int      // Redis database (default: 0)
+}
+
+// NewRedisSentinelAdapter creates a new Redis cache adapter connecting to the
+// master resolved by Sentinel
+func NewRedisSentinelAdapter(config RedisSentinelAdapterConfig) *RedisSentinelAdapter {
+	if len(config.SentinelAddrs) == 0 {
+		config.SentinelAddrs = []string{"localhost:26379"}
+	}
+
+	client := redis.NewFailoverClient(&redis.FailoverOptions{
+		MasterName:    config.MasterName,
+		SentinelAddrs: config.SentinelAddrs,
+		Password:      config.Password,
+		DB:            config.DB,
+	})
+
+	return &RedisSentinelAdapter{
+		RedisAdapter: NewRedisAdapterWithClient(client),
+	}
+}
+
+// redisClientOf returns the client of a Redis adapter, so Redis data structures
+// can be used instead of their generic Adapter emulation
+func redisClientOf(adapter Adapter) (*redis.Client, bool) {
+	switch a := adapter.(type) {
+	case *RedisAdapter:
+		return a.client, true
+	case *RedisSentinelAdapter:
+		return a.client, true
+	}
+	return nil, false
+}
+
+// redisCmdableOf is redisClientOf extended to Redis Cluster, for commands
+// touching a single key
+func redisCmdableOf(adapter Adapter) (redis.Cmdable, bool) {
+	if a, ok := adapter.(*RedisClusterAdapter); ok {
+		return a.client, true
+	}
+	return redisClientOf(adapter)
+}
@@ -0,0 +1,43 @@
+package gormcache
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+var _ Adapter = (*RedisSentinelAdapter)(nil)
+
+func TestRedisSentinelAdapter(t *testing.T) {
+	addr := os.Getenv("REDIS_SENTINEL_ADDR")
+	if addr == "" {
+		t.Skip("REDIS_SENTINEL_ADDR is not set")
+	}
+	masterName := os.Getenv("REDIS_SENTINEL_MASTER")
+	if masterName == "" {
+		masterName = "mymaster"
+	}
+
+	adapter := NewRedisSentinelAdapter(RedisSentinelAdapterConfig{
+		MasterName:    masterName,
+		SentinelAddrs: []string{addr},
+	})
+	defer adapter.Close()
+
+	ctx := context.Background()
+	if err := adapter.Set(ctx, "gorm:cache:sentinel:test", []byte("value"), time.Minute); err != nil {
+		t.Fatalf("failed to set: %v", err)
+	}
+	value, err := adapter.Get(ctx, "gorm:cache:sentinel:test")
+	if err != nil || string(value) != "value" {
+		t.Fatalf("expected value, got %q (%v)", value, err)
+	}
+
+	if err := adapter.DeletePattern(ctx, "gorm:cache:sentinel:*"); err != nil {
+		t.Fatalf("failed to delete pattern: %v", err)
+	}
+	if _, err := adapter.Get(ctx, "gorm:cache:sentinel:test"); err == nil {
+		t.Error("expected the key to be deleted")
+	}
+}
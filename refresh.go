@@ -0,0 +1,139 @@
+package gormcache
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+)
+
+// refreshInBackground re-executes the statement's query without the cache and
+// stores the fresh result under cacheKey, then calls done if the refresh succeeded
+// Concurrent refreshes of the same key are collapsed into one
+// Statements that cannot be detached are left to expire, see detachable
+func (p *CachePlugin) refreshInBackground(db *gorm.DB, cacheKey string, done func(ctx context.Context)) {
+	if !detachable(db) {
+		return
+	}
+	if _, running := p.refreshing.LoadOrStore(cacheKey, struct{}{}); running {
+		return
+	}
+
+	// 在当前 goroutine 中复制语句状态，后台执行时原语句可能已被复用
+	ctx := context.Background()
+	query := detachQuery(ctx, db)
+
+	go func() {
+		defer p.refreshing.Delete(cacheKey)
+
+		if _, err := p.refresh(ctx, query, cacheKey); err != nil {
+			return
+		}
+		if done != nil {
+			done(ctx)
+		}
+	}()
+}
+
+// detachedQuery is a copy of a statement's query that can be executed on its
+// own session, after the original statement has been reused
+type detachedQuery struct {
+	session  *gorm.DB
+	sql      string
+	vars     []interface{}
+	destType reflect.Type
+
+	// ttl is the WithTTL override of the statement, if any
+	ttl interface{}
+
+	// tags are the WithCacheTags tags of the statement, if any
+	tags interface{}
+}
+
+// detachable reports whether the query of db can be re-executed from its built
+// SQL alone; preloads and joins are resolved by GORM around the query and would
+// be missing from the refreshed result
+func detachable(db *gorm.DB) bool {
+	return len(db.Statement.Preloads) == 0 && len(db.Statement.Joins) == 0
+}
+
+// detachQuery copies the built query of db, to be executed with ctx
+func detachQuery(ctx context.Context, db *gorm.DB) *detachedQuery {
+	session := db.Session(&gorm.Session{NewDB: true, Context: ctx})
+	// Session copies db.Error, which holds ErrCacheHit when refreshing a cache hit
+	session.Error = nil
+
+	query := &detachedQuery{
+		session:  session,
+		sql:      db.Statement.SQL.String(),
+		vars:     append([]interface{}(nil), db.Statement.Vars...),
+		destType: reflect.TypeOf(db.Statement.Dest),
+	}
+	query.ttl, _ = db.Statement.Settings.Load("gorm:cache:ttl")
+	query.tags, _ = db.Statement.Settings.Load("gorm:cache:tags")
+	return query
+}
+
+// refresh runs the query against the database, bypassing the cache, caches the
+// result under cacheKey and returns it serialized, or nil if it is empty,
+// exceeds MaxCacheableRows or is rejected by CachePredicateFunc
+func (p *CachePlugin) refresh(ctx context.Context, query *detachedQuery, cacheKey string) ([]byte, error) {
+	if query.destType == nil || query.destType.Kind() != reflect.Ptr {
+		return nil, fmt.Errorf("gorm:cache: cannot refresh query into %v", query.destType)
+	}
+	dest := reflect.New(query.destType.Elem()).Interface()
+
+	// The prebuilt SQL is executed as is, BuildQuerySQL keeps a non-empty statement
+	tx := query.session.Scopes(SkipCache())
+	tx.Statement.SQL.WriteString(query.sql)
+	tx.Statement.Vars = query.vars
+	if query.ttl != nil {
+		tx.Statement.Settings.Store("gorm:cache:ttl", query.ttl)
+	}
+	if query.tags != nil {
+		tx.Statement.Settings.Store("gorm:cache:tags", query.tags)
+	}
+
+	result := tx.Find(dest)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	// 不缓存空值结果、超过 MaxCacheableRows 和 CachePredicateFunc 拒绝的结果
+	if result.RowsAffected == 0 || p.exceedsMaxRows(dest) || !p.acceptsResult(result, result.RowsAffected) {
+		return nil, p.adapter().Delete(ctx, cacheKey)
+	}
+
+	if p.config.DBQueryHook != nil {
+		if err := p.config.DBQueryHook(ctx, result, dest); err != nil {
+			return nil, err
+		}
+	}
+
+	cachedData, err := p.config.Serializer.Marshal(dest)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl, ok := p.cacheTTL(result)
+	if !ok {
+		return cachedData, p.adapter().Delete(ctx, cacheKey)
+	}
+
+	stored, err := p.storeCached(ctx, cacheKey, cachedData, ttl)
+	if err != nil {
+		p.stats.errors.Add(1)
+		return cachedData, err
+	}
+	if !stored {
+		return cachedData, nil
+	}
+	p.stats.bytesStored.Add(int64(len(cachedData)))
+	p.onStore(cacheKey, ttl)
+
+	if tags := p.statementTags(result); len(tags) > 0 {
+		_ = p.tagKey(ctx, cacheKey, tags, ttl)
+	}
+	return cachedData, nil
+}
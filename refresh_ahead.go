@@ -0,0 +1,107 @@
+package gormcache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// defaultRefreshPoolSize is the number of refresh workers used when
+// Config.RefreshPoolSize is not set
+const defaultRefreshPoolSize = 10
+
+// refreshJob is a query to refresh ahead of its expiry
+type refreshJob struct {
+	query    *detachedQuery
+	cacheKey string
+}
+
+// refreshPool runs the refreshes of RefreshThreshold on a bounded number of
+// workers, started on the first refresh
+type refreshPool struct {
+	size      int
+	jobs      chan refreshJob
+	startOnce sync.Once
+	stop      chan struct{}
+	closeOnce sync.Once
+}
+
+func newRefreshPool(size int) *refreshPool {
+	return &refreshPool{
+		size: size,
+		jobs: make(chan refreshJob, size),
+		stop: make(chan struct{}),
+	}
+}
+
+// submit queues job for run without blocking; it returns false if all
+// workers are busy and the queue is full
+func (r *refreshPool) submit(job refreshJob, run func(refreshJob)) bool {
+	r.startOnce.Do(func() {
+		for i := 0; i < r.size; i++ {
+			go r.work(run)
+		}
+	})
+
+	select {
+	case <-r.stop:
+		return false
+	case r.jobs <- job:
+		return true
+	default:
+		return false
+	}
+}
+
+// work runs queued jobs until the pool is closed
+func (r *refreshPool) work(run func(refreshJob)) {
+	for {
+		select {
+		case job := <-r.jobs:
+			run(job)
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+// shutdown stops the workers; queued jobs are dropped
+func (r *refreshPool) shutdown() {
+	r.closeOnce.Do(func() { close(r.stop) })
+}
+
+// refreshAheadIfExpiring queues a refresh of a cache entry whose remaining
+// TTL dropped below RefreshThreshold of its TTL, so it is renewed while it is
+// still valid; the entry is left as is if the refresh pool is full
+func (p *CachePlugin) refreshAheadIfExpiring(ctx context.Context, db *gorm.DB, cacheKey string) {
+	meta, err := p.loadMetadata(ctx, cacheKey)
+	if err != nil || meta.TTL <= 0 {
+		return
+	}
+	remaining := time.Until(meta.SetAt.Add(meta.TTL))
+	if remaining <= 0 || float64(remaining) >= p.config.RefreshThreshold*float64(meta.TTL) {
+		return
+	}
+	if !detachable(db) {
+		return
+	}
+
+	if _, running := p.refreshing.LoadOrStore(cacheKey, struct{}{}); running {
+		return
+	}
+
+	// 在当前 goroutine 中复制语句状态，后台执行时原语句可能已被复用
+	job := refreshJob{query: detachQuery(context.Background(), db), cacheKey: cacheKey}
+	if !p.refreshAhead.submit(job, p.runRefreshJob) {
+		// 池已满，之后的命中会再次尝试
+		p.refreshing.Delete(cacheKey)
+	}
+}
+
+// runRefreshJob refreshes the entry of job on a refresh pool worker
+func (p *CachePlugin) runRefreshJob(job refreshJob) {
+	defer p.refreshing.Delete(job.cacheKey)
+	_, _ = p.refresh(context.Background(), job.query, job.cacheKey)
+}
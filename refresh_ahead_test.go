@@ -0,0 +1,64 @@
+package gormcache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRefreshThreshold(t *testing.T) {
+	db := setupTestDB(t)
+
+	// Keep the in-memory database on a single connection for background refreshes
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+
+	const ttl = 400 * time.Millisecond
+	cachePlugin := New(Config{
+		Adapter:          NewMemoryAdapter(),
+		TTL:              ttl,
+		RefreshThreshold: 0.5,
+		RefreshPoolSize:  2,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	user := TestUser{Name: "Original Name"}
+	db.Create(&user)
+
+	first := func() string {
+		var result TestUser
+		if err := db.First(&result, user.ID).Error; err != nil {
+			t.Fatalf("query failed: %v", err)
+		}
+		return result.Name
+	}
+
+	first()
+	expiry := time.Now().Add(ttl)
+
+	// The write does not invalidate the entry, only a refresh can pick it up
+	db.Model(&user).Update("Name", "Updated Name")
+
+	// More than half of the TTL remains, hits do not refresh the entry
+	first()
+	time.Sleep(20 * time.Millisecond)
+	if name := first(); name != "Original Name" {
+		t.Fatalf("expected no refresh above the threshold, got '%s'", name)
+	}
+
+	// 剩余 TTL 低于一半时，命中仍返回缓存结果并在后台刷新
+	time.Sleep(250 * time.Millisecond)
+	if name := first(); name != "Original Name" {
+		t.Fatalf("expected the cached 'Original Name', got '%s'", name)
+	}
+
+	// The refresh completes before the original entry expires
+	for first() != "Updated Name" {
+		if time.Now().After(expiry) {
+			t.Fatal("expected a background refresh before the entry expired")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
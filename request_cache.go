@@ -0,0 +1,79 @@
+package gormcache
+
+import (
+	"context"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// WithRequestCache returns a new context carrying a request-scoped cache
+// Queries made with it, or contexts derived from it, are served from that
+// cache after their first execution when Config.RequestCacheEnabled is set;
+// the cache is dropped with the context
+func WithRequestCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextKeyRequestCache, &sync.Map{})
+}
+
+// getRequestCache returns the request cache attached by WithRequestCache
+func getRequestCache(ctx context.Context) (*sync.Map, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	entries, ok := ctx.Value(contextKeyRequestCache).(*sync.Map)
+	return entries, ok
+}
+
+// serveFromRequestCache serves the query from the request cache of ctx,
+// reporting whether it was found there
+func (p *CachePlugin) serveFromRequestCache(ctx context.Context, db *gorm.DB, cacheKey string) bool {
+	entries, ok := getRequestCache(ctx)
+	if !ok || db.Statement.Dest == nil {
+		return false
+	}
+	cachedData, ok := entries.Load(cacheKey)
+	if !ok {
+		return false
+	}
+
+	if err := p.config.Serializer.Unmarshal(cachedData.([]byte), db.Statement.Dest); err != nil {
+		entries.Delete(cacheKey)
+		return false
+	}
+
+	db.Error = &ErrCacheHit{RowsAffected: calculateRowsAffected(db.Statement.Dest)}
+	db.Statement.Settings.Store("gorm:cache:hit", true)
+	p.stats.hits.Add(1)
+	p.onHit(cacheKey)
+	return true
+}
+
+// storeRequestCache stores a serialized query result in the request cache of
+// ctx, if RequestCacheEnabled is set and ctx has one
+func (p *CachePlugin) storeRequestCache(ctx context.Context, cacheKey string, cachedData []byte) {
+	if !p.config.RequestCacheEnabled {
+		return
+	}
+	if entries, ok := getRequestCache(ctx); ok {
+		entries.Store(cacheKey, cachedData)
+	}
+}
+
+// invalidateRequestCache removes the entries matching pattern from the request
+// cache of ctx, so the request reads its own writes
+func (p *CachePlugin) invalidateRequestCache(ctx context.Context, pattern string) {
+	if !p.config.RequestCacheEnabled {
+		return
+	}
+	entries, ok := getRequestCache(ctx)
+	if !ok {
+		return
+	}
+
+	entries.Range(func(key, _ any) bool {
+		if matchPattern(pattern, key.(string)) {
+			entries.Delete(key)
+		}
+		return true
+	})
+}
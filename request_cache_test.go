@@ -0,0 +1,134 @@
+package gormcache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRequestCache(t *testing.T) {
+	db := setupTestDB(t)
+
+	// The shared adapter is down, so only the request cache can answer
+	cachePlugin := New(Config{
+		Adapter:             failingAdapter{},
+		TTL:                 5 * time.Minute,
+		RequestCacheEnabled: true,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	user := TestUser{Name: "John"}
+	db.Create(&user)
+
+	queries := countQueries(t, db)
+	ctx := WithRequestCache(context.Background())
+
+	var first, second TestUser
+	db.WithContext(ctx).First(&first, user.ID)
+	db.WithContext(ctx).First(&second, user.ID)
+	if *queries != 1 {
+		t.Errorf("expected 1 database query within the request, got %d", *queries)
+	}
+	if second.Name != "John" {
+		t.Errorf("expected 'John' from the request cache, got '%s'", second.Name)
+	}
+
+	// Another request does not see the entries of the first one
+	var other TestUser
+	db.WithContext(WithRequestCache(context.Background())).First(&other, user.ID)
+	if *queries != 2 {
+		t.Errorf("expected a new request to query the database, got %d database queries", *queries)
+	}
+}
+
+func TestRequestCacheInvalidation(t *testing.T) {
+	db := setupTestDB(t)
+
+	cachePlugin := New(Config{
+		Adapter:             NewMemoryAdapter(),
+		TTL:                 5 * time.Minute,
+		InvalidateOnUpdate:  true,
+		RequestCacheEnabled: true,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	user := TestUser{Name: "John"}
+	db.Create(&user)
+
+	ctx := WithRequestCache(context.Background())
+	var result TestUser
+	db.WithContext(ctx).First(&result, user.ID)
+
+	// A write of the request removes the entries it invalidates
+	db.WithContext(ctx).Model(&user).Update("Name", "Jane")
+
+	result = TestUser{}
+	db.WithContext(ctx).First(&result, user.ID)
+	if result.Name != "Jane" {
+		t.Errorf("expected the request to read its own write, got '%s'", result.Name)
+	}
+}
+
+func TestRequestCacheDisabled(t *testing.T) {
+	db := setupTestDB(t)
+
+	cachePlugin := New(Config{
+		Adapter: failingAdapter{},
+		TTL:     5 * time.Minute,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	user := TestUser{Name: "John"}
+	db.Create(&user)
+
+	queries := countQueries(t, db)
+	ctx := WithRequestCache(context.Background())
+
+	var result TestUser
+	db.WithContext(ctx).First(&result, user.ID)
+	db.WithContext(ctx).First(&result, user.ID)
+	if *queries != 2 {
+		t.Errorf("expected the request cache to be unused without RequestCacheEnabled, got %d database queries", *queries)
+	}
+}
+
+func TestRequestCacheSingleflight(t *testing.T) {
+	db := setupTestDB(t)
+
+	cachePlugin := New(Config{
+		Adapter:             NewMemoryAdapter(),
+		TTL:                 5 * time.Minute,
+		SingleflightEnabled: true,
+		RequestCacheEnabled: true,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	db.Create(&TestUser{Name: "John"})
+
+	// Results loaded through the singleflight group are kept for the request too
+	ctx := WithRequestCache(context.Background())
+	var users []TestUser
+	db.WithContext(ctx).Find(&users)
+
+	entries, _ := getRequestCache(ctx)
+	n := 0
+	entries.Range(func(_, _ any) bool {
+		n++
+		return true
+	})
+	if n != 1 {
+		t.Errorf("expected 1 request cache entry, got %d", n)
+	}
+}
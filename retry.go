@@ -0,0 +1,48 @@
+package gormcache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// defaultRetryBackoff is the delay before retry attempt when RetryBackoff is nil:
+// 100ms, 200ms, 300ms, ...
+func defaultRetryBackoff(attempt int) time.Duration {
+	return time.Duration(attempt) * 100 * time.Millisecond
+}
+
+// withRetry runs op and retries it up to RetryCount times while it fails with
+// an error other than ErrCacheMiss or ErrCircuitOpen, waiting RetryBackoff(attempt) before each
+// retry; it returns the last error once the retries are exhausted, or once the
+// context is done or its deadline is too close for the next attempt
+func (p *CachePlugin) withRetry(ctx context.Context, op func() error) error {
+	backoff := p.config.RetryBackoff
+	if backoff == nil {
+		backoff = defaultRetryBackoff
+	}
+
+	err := op()
+	for attempt := 1; attempt <= p.config.RetryCount; attempt++ {
+		// 未命中和熔断都不是暂时性错误，无需重试
+		if err == nil || errors.Is(err, ErrCacheMiss) || errors.Is(err, ErrCircuitOpen) {
+			return err
+		}
+
+		wait := backoff(attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return err
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+
+		err = op()
+	}
+	return err
+}
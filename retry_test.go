@@ -0,0 +1,142 @@
+package gormcache
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyAdapter fails the first `failures` Get and Set calls, then delegates to
+// the inner adapter
+type flakyAdapter struct {
+	Adapter
+	failures int64
+	gets     atomic.Int64
+	sets     atomic.Int64
+}
+
+func (a *flakyAdapter) Get(ctx context.Context, key string) ([]byte, error) {
+	if a.gets.Add(1) <= a.failures {
+		return nil, errAdapterDown
+	}
+	return a.Adapter.Get(ctx, key)
+}
+
+func (a *flakyAdapter) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if a.sets.Add(1) <= a.failures {
+		return errAdapterDown
+	}
+	return a.Adapter.Set(ctx, key, value, ttl)
+}
+
+func TestRetryTransientAdapterErrors(t *testing.T) {
+	db := setupTestDB(t)
+
+	adapter := &flakyAdapter{Adapter: NewMemoryAdapter(), failures: 2}
+	cachePlugin := New(Config{
+		Adapter:      adapter,
+		TTL:          5 * time.Minute,
+		RetryCount:   3,
+		RetryBackoff: func(attempt int) time.Duration { return time.Millisecond },
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	db.Create(&TestUser{Name: "John"})
+
+	queries := countQueries(t, db)
+	var users []TestUser
+
+	// The first two Gets fail and the third reports a miss; the result is
+	// stored on the third Set
+	if err := db.Find(&users).Error; err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if got := adapter.sets.Load(); got != 3 {
+		t.Errorf("expected 3 Set attempts, got %d", got)
+	}
+
+	if err := db.Find(&users).Error; err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if *queries != 1 {
+		t.Errorf("expected the second query to be served from cache, got %d database queries", *queries)
+	}
+	if stats := cachePlugin.Stats(); stats.Errors != 0 {
+		t.Errorf("expected no cache errors, got %d", stats.Errors)
+	}
+}
+
+func TestRetryExhausted(t *testing.T) {
+	db := setupTestDB(t)
+
+	var reported int
+	adapter := &flakyAdapter{Adapter: NewMemoryAdapter(), failures: 10}
+	cachePlugin := New(Config{
+		Adapter:      adapter,
+		TTL:          5 * time.Minute,
+		RetryCount:   2,
+		RetryBackoff: func(attempt int) time.Duration { return time.Millisecond },
+		OnError:      func(err error) { reported++ },
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	var users []TestUser
+	db.Find(&users)
+
+	// One attempt and two retries
+	if got := adapter.gets.Load(); got != 3 {
+		t.Errorf("expected 3 Get attempts, got %d", got)
+	}
+	if reported == 0 {
+		t.Error("expected the final error to be reported")
+	}
+}
+
+func TestRetryRespectsDeadline(t *testing.T) {
+	db := setupTestDB(t)
+
+	adapter := &flakyAdapter{Adapter: NewMemoryAdapter(), failures: 10}
+	cachePlugin := New(Config{
+		Adapter:      adapter,
+		TTL:          5 * time.Minute,
+		RetryCount:   3,
+		RetryBackoff: func(attempt int) time.Duration { return time.Second },
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	var users []TestUser
+	db.WithContext(ctx).Find(&users)
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("expected retries to stop at the context deadline, took %v", elapsed)
+	}
+	if got := adapter.gets.Load(); got != 1 {
+		t.Errorf("expected no Get retry past the deadline, got %d attempts", got)
+	}
+}
+
+func TestDefaultRetryBackoff(t *testing.T) {
+	for attempt, want := range map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		2: 200 * time.Millisecond,
+		3: 300 * time.Millisecond,
+	} {
+		if got := defaultRetryBackoff(attempt); got != want {
+			t.Errorf("attempt %d: expected %v, got %v", attempt, want, got)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package gormcache
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ddlKeywords are the statement prefixes treated as schema migrations
+var ddlKeywords = []string{"CREATE ", "ALTER ", "DROP ", "RENAME "}
+
+// schemaVersion returns the short hash of the current database schema, or an
+// empty string when AutoVersionFromSchema is disabled
+func (p *CachePlugin) schemaVersion() string {
+	if !p.config.AutoVersionFromSchema {
+		return ""
+	}
+	if v, ok := p.schemaHash.Load().(string); ok {
+		return v
+	}
+	return ""
+}
+
+// refreshSchemaVersion recomputes the schema hash from the tables and columns
+// currently known to the database migrator
+func (p *CachePlugin) refreshSchemaVersion(db *gorm.DB) error {
+	tx := db.Session(&gorm.Session{NewDB: true})
+	migrator := tx.Migrator()
+
+	tables, err := migrator.GetTables()
+	if err != nil {
+		return err
+	}
+	sort.Strings(tables)
+
+	var b strings.Builder
+	for _, table := range tables {
+		columns, err := migrator.ColumnTypes(table)
+		if err != nil {
+			return err
+		}
+
+		b.WriteString(table)
+		b.WriteString("(")
+		for _, column := range columns {
+			b.WriteString(column.Name())
+			b.WriteString(" ")
+			b.WriteString(column.DatabaseTypeName())
+			b.WriteString(",")
+		}
+		b.WriteString(");")
+	}
+
+	hash := md5.Sum([]byte(b.String()))
+	p.schemaHash.Store(hex.EncodeToString(hash[:])[:8])
+	return nil
+}
+
+// refreshDirtySchemaVersion recomputes the schema hash through db if a
+// migration ran since it was last computed
+func (p *CachePlugin) refreshDirtySchemaVersion(db *gorm.DB) {
+	if !p.schemaDirty.Load() {
+		return
+	}
+
+	p.schemaMu.Lock()
+	defer p.schemaMu.Unlock()
+	// 其他查询可能已经在等待锁期间重新计算过
+	if !p.schemaDirty.Load() {
+		return
+	}
+	// 先清除标记，计算期间发生的迁移会再次设置它
+	p.schemaDirty.Store(false)
+	if err := p.refreshSchemaVersion(db); err != nil {
+		p.schemaDirty.Store(true)
+		p.onError(err)
+	}
+}
+
+// migrateCallback is executed after raw statements to detect schema migrations
+// The schema hash is only marked dirty, so a migration running many statements
+// does not read the whole schema after each of them
+func (p *CachePlugin) migrateCallback(db *gorm.DB) {
+	if db.Error != nil || !isDDL(db.Statement.SQL.String()) {
+		return
+	}
+
+	p.schemaDirty.Store(true)
+}
+
+// isDDL reports whether the SQL statement changes the database schema
+func isDDL(sql string) bool {
+	sql = strings.ToUpper(strings.TrimSpace(sql))
+	for _, keyword := range ddlKeywords {
+		if strings.HasPrefix(sql, keyword) {
+			return true
+		}
+	}
+	return false
+}
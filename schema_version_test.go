@@ -0,0 +1,71 @@
+package gormcache
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testUserWithEmail is TestUser after a migration adding the email column
+type testUserWithEmail struct {
+	ID    uint
+	Name  string
+	Email string
+}
+
+func (testUserWithEmail) TableName() string {
+	return "test_users"
+}
+
+func TestAutoVersionFromSchema(t *testing.T) {
+	db := setupTestDB(t)
+
+	cachePlugin := New(Config{
+		Adapter:               NewMemoryAdapter(),
+		TTL:                   5 * time.Minute,
+		AutoVersionFromSchema: true,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	queries := countQueries(t, db)
+
+	user := TestUser{Name: "Test User"}
+	db.Create(&user)
+
+	var user1, user2 TestUser
+	db.First(&user1, user.ID)
+	db.First(&user2, user.ID)
+	if got := atomic.LoadInt64(queries); got != 1 {
+		t.Fatalf("expected 1 database query before migration, got %d", got)
+	}
+
+	versionBefore := cachePlugin.schemaVersion()
+	if versionBefore == "" {
+		t.Fatal("expected schema version to be set")
+	}
+
+	// Run a migration mid-test
+	if err := db.AutoMigrate(&testUserWithEmail{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	// The schema is read again by the next query, not by every migration statement
+	if !cachePlugin.schemaDirty.Load() || cachePlugin.schemaVersion() != versionBefore {
+		t.Error("expected the migration to mark the schema version dirty")
+	}
+
+	var user3 TestUser
+	db.First(&user3, user.ID)
+	if cachePlugin.schemaVersion() == versionBefore {
+		t.Error("expected schema version to change after migration")
+	}
+	if got := atomic.LoadInt64(queries); got != 2 {
+		t.Errorf("expected post-migration query to miss cache, got %d database queries", got)
+	}
+	if user3.Name != "Test User" {
+		t.Errorf("expected name 'Test User', got '%s'", user3.Name)
+	}
+}
@@ -1,8 +1,12 @@
 package gormcache
 
 import (
+	"bytes"
+	"encoding/gob"
 	"encoding/json"
+	"sync"
 
+	"github.com/fxamacker/cbor/v2"
 	"github.com/vmihailenco/msgpack/v5"
 )
 
@@ -25,6 +29,46 @@ func (j *JSONSerializer) Unmarshal(data []byte, v interface{}) error {
 	return json.Unmarshal(data, v)
 }
 
+// PooledJSONSerializer implements JSON serialization, reusing its buffer and
+// json.Encoder across calls instead of allocating them for every Marshal
+// Its output is identical to JSONSerializer; compare both for your data with
+// go test -bench Serializer -benchmem
+type PooledJSONSerializer struct {
+	pool sync.Pool
+}
+
+// pooledEncoder is a JSON encoder bound to its reusable buffer
+type pooledEncoder struct {
+	buf bytes.Buffer
+	enc *json.Encoder
+}
+
+// Marshal serializes v to JSON bytes
+func (j *PooledJSONSerializer) Marshal(v interface{}) ([]byte, error) {
+	e, ok := j.pool.Get().(*pooledEncoder)
+	if !ok {
+		e = &pooledEncoder{}
+		e.enc = json.NewEncoder(&e.buf)
+	}
+	defer func() {
+		e.buf.Reset()
+		j.pool.Put(e)
+	}()
+
+	if err := e.enc.Encode(v); err != nil {
+		return nil, err
+	}
+
+	// Encode 会追加换行符，去掉以保持与 json.Marshal 一致；缓冲区会被复用，必须复制
+	data := bytes.TrimSuffix(e.buf.Bytes(), []byte("\n"))
+	return append([]byte(nil), data...), nil
+}
+
+// Unmarshal deserializes JSON bytes to v
+func (j *PooledJSONSerializer) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
 // MsgPackSerializer implements MessagePack serialization
 type MsgPackSerializer struct{}
 
@@ -37,3 +81,48 @@ func (m *MsgPackSerializer) Marshal(v interface{}) ([]byte, error) {
 func (m *MsgPackSerializer) Unmarshal(data []byte, v interface{}) error {
 	return msgpack.Unmarshal(data, v)
 }
+
+// GobSerializer implements encoding/gob serialization
+// Gob does not encode zero values, so results must be decoded into zero-valued
+// destinations, and concrete types stored in interface fields must be registered
+// with gob.Register
+type GobSerializer struct{}
+
+// Marshal serializes v to gob bytes
+func (g *GobSerializer) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal deserializes gob bytes to v
+func (g *GobSerializer) Unmarshal(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// cborEncMode encodes deterministically, so equal values always produce the same
+// bytes; times keep nanosecond precision and their UTC offset
+var cborEncMode = func() cbor.EncMode {
+	opts := cbor.CoreDetEncOptions()
+	opts.Time = cbor.TimeRFC3339Nano
+	mode, err := opts.EncMode()
+	if err != nil {
+		panic(err)
+	}
+	return mode
+}()
+
+// CBORSerializer implements CBOR serialization with deterministic encoding
+type CBORSerializer struct{}
+
+// Marshal serializes v to CBOR bytes
+func (c *CBORSerializer) Marshal(v interface{}) ([]byte, error) {
+	return cborEncMode.Marshal(v)
+}
+
+// Unmarshal deserializes CBOR bytes to v
+func (c *CBORSerializer) Unmarshal(data []byte, v interface{}) error {
+	return cbor.Unmarshal(data, v)
+}
@@ -0,0 +1,247 @@
+package gormcache
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// serializerFixture returns a result set shaped like a typical cached query
+func serializerFixture() []TestUser {
+	users := make([]TestUser, 100)
+	for i := range users {
+		users[i] = TestUser{ID: uint(i + 1), Name: "user <" + string(rune('a'+i%26)) + ">"}
+	}
+	return users
+}
+
+func TestPooledJSONSerializerRoundTrip(t *testing.T) {
+	var serializer Serializer = &PooledJSONSerializer{}
+
+	users := serializerFixture()
+	for i := 0; i < 3; i++ {
+		data, err := serializer.Marshal(users)
+		if err != nil {
+			t.Fatalf("Marshal failed: %v", err)
+		}
+
+		// The output must not change with buffer reuse and must match json.Marshal
+		expected, _ := json.Marshal(users)
+		if !bytes.Equal(data, expected) {
+			t.Fatalf("expected %s, got %s", expected, data)
+		}
+
+		var decoded []TestUser
+		if err := serializer.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("Unmarshal failed: %v", err)
+		}
+		if !reflect.DeepEqual(decoded, users) {
+			t.Fatalf("round trip mismatch: %v", decoded)
+		}
+	}
+
+	// Returned slices must not alias the pooled buffer
+	first, _ := serializer.Marshal(TestUser{ID: 1, Name: "first"})
+	_, _ = serializer.Marshal(TestUser{ID: 2, Name: "second"})
+	if string(first) != `{"ID":1,"Name":"first"}` {
+		t.Errorf("expected the first result to be unchanged, got %s", first)
+	}
+}
+
+// serializerOrder is a result shape with nested pointers and slices
+type serializerOrder struct {
+	ID       uint
+	Customer *serializerCustomer
+	Items    []serializerItem
+	Notes    []*string
+	Tags     map[string]int
+}
+
+type serializerCustomer struct {
+	Name    string
+	Address *struct{ City string }
+}
+
+type serializerItem struct {
+	SKU      string
+	Quantity int
+	Price    *float64
+}
+
+func serializerOrderFixture() []serializerOrder {
+	price := 9.99
+	note := "leave at the door"
+	return []serializerOrder{
+		{
+			ID: 1,
+			Customer: &serializerCustomer{
+				Name:    "John",
+				Address: &struct{ City string }{City: "Berlin"},
+			},
+			Items: []serializerItem{
+				{SKU: "kbd-1", Quantity: 2, Price: &price},
+				{SKU: "mouse-1", Quantity: 1},
+			},
+			Notes: []*string{&note},
+			Tags:  map[string]int{"priority": 1},
+		},
+		{ID: 2, Customer: &serializerCustomer{Name: "Jane"}},
+	}
+}
+
+func TestSerializersRoundTripComplexStruct(t *testing.T) {
+	for name, serializer := range map[string]Serializer{
+		"msgpack": &MsgPackSerializer{},
+		"gob":     &GobSerializer{},
+	} {
+		t.Run(name, func(t *testing.T) {
+			orders := serializerOrderFixture()
+			data, err := serializer.Marshal(orders)
+			if err != nil {
+				t.Fatalf("Marshal failed: %v", err)
+			}
+
+			var decoded []serializerOrder
+			if err := serializer.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("Unmarshal failed: %v", err)
+			}
+			if !reflect.DeepEqual(decoded, orders) {
+				t.Errorf("round trip mismatch: %+v", decoded)
+			}
+		})
+	}
+}
+
+func TestBinarySerializersWithPlugin(t *testing.T) {
+	for name, serializer := range map[string]Serializer{
+		"gob":  &GobSerializer{},
+		"cbor": &CBORSerializer{},
+	} {
+		t.Run(name, func(t *testing.T) {
+			db := setupTestDB(t)
+
+			cachePlugin := New(Config{
+				Adapter:    NewMemoryAdapter(),
+				TTL:        5 * time.Minute,
+				Serializer: serializer,
+			})
+			if err := db.Use(cachePlugin); err != nil {
+				t.Fatalf("failed to install plugin: %v", err)
+			}
+			defer cachePlugin.Close()
+
+			db.Create(&TestUser{Name: "John"})
+			db.Create(&TestUser{Name: "Jane"})
+
+			queries := countQueries(t, db)
+
+			var first []TestUser
+			db.Order("id").Find(&first)
+			var second []TestUser
+			db.Order("id").Find(&second)
+			if *queries != 1 {
+				t.Errorf("expected the second query to be served from cache, got %d database queries", *queries)
+			}
+			if !reflect.DeepEqual(first, second) {
+				t.Errorf("expected %v from cache, got %v", first, second)
+			}
+		})
+	}
+}
+
+// serializerEvent has the field types JSON does not round-trip exactly
+type serializerEvent struct {
+	ID        uint
+	CreatedAt time.Time
+	Score     float64
+	Payload   []byte
+}
+
+func TestCBORSerializerRoundTrip(t *testing.T) {
+	var serializer Serializer = &CBORSerializer{}
+
+	events := []serializerEvent{
+		{
+			ID:        1,
+			CreatedAt: time.Date(2024, 3, 1, 12, 30, 45, 123456789, time.FixedZone("CET", 3600)),
+			Score:     0.1 + 0.2,
+			Payload:   []byte{0x00, 0xff, 0x10},
+		},
+		{ID: 2, CreatedAt: time.Unix(0, 1).UTC(), Score: -1e-300},
+	}
+
+	data, err := serializer.Marshal(events)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var decoded []serializerEvent
+	if err := serializer.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if len(decoded) != len(events) {
+		t.Fatalf("expected %d events, got %d", len(events), len(decoded))
+	}
+	for i, event := range events {
+		got := decoded[i]
+		if got.ID != event.ID || got.Score != event.Score || !bytes.Equal(got.Payload, event.Payload) {
+			t.Errorf("event %d: expected %+v, got %+v", i, event, got)
+		}
+		// 时区名称不会被编码，只比较时间点和偏移量
+		_, wantOffset := event.CreatedAt.Zone()
+		_, gotOffset := got.CreatedAt.Zone()
+		if !got.CreatedAt.Equal(event.CreatedAt) || gotOffset != wantOffset {
+			t.Errorf("event %d: expected time %v, got %v", i, event.CreatedAt, got.CreatedAt)
+		}
+	}
+
+	// Deterministic encoding produces the same bytes for equal values
+	again, _ := serializer.Marshal(map[string]int{"b": 2, "a": 1, "c": 3})
+	for i := 0; i < 10; i++ {
+		data, _ := serializer.Marshal(map[string]int{"c": 3, "a": 1, "b": 2})
+		if !bytes.Equal(data, again) {
+			t.Fatalf("expected deterministic encoding, got %x and %x", data, again)
+		}
+	}
+}
+
+func TestPooledJSONSerializerAllocations(t *testing.T) {
+	users := serializerFixture()
+	pooled := &PooledJSONSerializer{}
+
+	// Unpooled baseline: a fresh buffer and encoder per call
+	unpooled := testing.AllocsPerRun(100, func() {
+		var buf bytes.Buffer
+		_ = json.NewEncoder(&buf).Encode(users)
+		_ = append([]byte(nil), buf.Bytes()...)
+	})
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = pooled.Marshal(users)
+	})
+
+	if allocs >= unpooled {
+		t.Errorf("expected pooling to reduce allocations, got %.0f pooled vs %.0f unpooled", allocs, unpooled)
+	}
+}
+
+func BenchmarkJSONSerializerMarshal(b *testing.B) {
+	benchmarkSerializerMarshal(b, &JSONSerializer{})
+}
+
+func BenchmarkPooledJSONSerializerMarshal(b *testing.B) {
+	benchmarkSerializerMarshal(b, &PooledJSONSerializer{})
+}
+
+func benchmarkSerializerMarshal(b *testing.B, serializer Serializer) {
+	users := serializerFixture()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := serializer.Marshal(users); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
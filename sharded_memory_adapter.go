@@ -0,0 +1,214 @@
+package gormcache
+
+import (
+	"context"
+	"time"
+)
+
+// defaultShardCount is used by NewShardedMemoryAdapter when shards is not positive
+const defaultShardCount = 16
+
+// ShardedMemoryAdapter is an in-memory cache partitioning keys across several
+// independently locked MemoryAdapter shards, which reduces lock contention
+// when many goroutines read and write concurrently
+type ShardedMemoryAdapter struct {
+	shards []*MemoryAdapter
+	stopCh chan struct{}
+}
+
+// NewShardedMemoryAdapter creates an in-memory cache adapter with shards
+// partitions, each guarded by its own mutex; keys are assigned to shards by
+// their FNV-1a hash
+func NewShardedMemoryAdapter(shards int) *ShardedMemoryAdapter {
+	if shards <= 0 {
+		shards = defaultShardCount
+	}
+
+	adapter := &ShardedMemoryAdapter{
+		shards: make([]*MemoryAdapter, shards),
+		stopCh: make(chan struct{}),
+	}
+	for i := range adapter.shards {
+		// 分片不启动各自的清理协程，由 adapter 统一清理
+		adapter.shards[i] = &MemoryAdapter{
+			store:  make(map[string]*cacheItem),
+			stopCh: make(chan struct{}),
+		}
+	}
+
+	go adapter.startCleanup(time.Minute)
+
+	return adapter
+}
+
+// shard returns the shard holding key
+func (s *ShardedMemoryAdapter) shard(key string) *MemoryAdapter {
+	return s.shards[fnv32a(key)%uint32(len(s.shards))]
+}
+
+// fnv32a returns the 32-bit FNV-1a hash of key without allocating
+func fnv32a(key string) uint32 {
+	const (
+		offset32 = 2166136261
+		prime32  = 16777619
+	)
+	hash := uint32(offset32)
+	for i := 0; i < len(key); i++ {
+		hash ^= uint32(key[i])
+		hash *= prime32
+	}
+	return hash
+}
+
+// Get retrieves a value from the shard of key
+func (s *ShardedMemoryAdapter) Get(ctx context.Context, key string) ([]byte, error) {
+	return s.shard(key).Get(ctx, key)
+}
+
+// Exists reports whether key is cached and not expired
+func (s *ShardedMemoryAdapter) Exists(ctx context.Context, key string) (bool, error) {
+	return s.shard(key).Exists(ctx, key)
+}
+
+// TTL returns the remaining lifetime of key, 0 if it never expires
+func (s *ShardedMemoryAdapter) TTL(ctx context.Context, key string) (time.Duration, error) {
+	return s.shard(key).TTL(ctx, key)
+}
+
+// Touch resets the TTL of key without copying its value
+func (s *ShardedMemoryAdapter) Touch(ctx context.Context, key string, ttl time.Duration) error {
+	return s.shard(key).Touch(ctx, key, ttl)
+}
+
+// Set stores a value in the shard of key
+func (s *ShardedMemoryAdapter) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return s.shard(key).Set(ctx, key, value, ttl)
+}
+
+// Delete removes a value from the shard of key
+func (s *ShardedMemoryAdapter) Delete(ctx context.Context, key string) error {
+	return s.shard(key).Delete(ctx, key)
+}
+
+// MGet retrieves the values of the given keys, locking each shard once
+func (s *ShardedMemoryAdapter) MGet(ctx context.Context, keys []string) (map[string][]byte, error) {
+	values := make(map[string][]byte, len(keys))
+	for shard, shardKeys := range s.groupKeys(keys) {
+		shardValues, err := shard.MGet(ctx, shardKeys)
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range shardValues {
+			values[key] = value
+		}
+	}
+	return values, nil
+}
+
+// MSet stores all items with the given TTL, locking each shard once
+func (s *ShardedMemoryAdapter) MSet(ctx context.Context, items map[string][]byte, ttl time.Duration) error {
+	grouped := make(map[*MemoryAdapter]map[string][]byte)
+	for key, value := range items {
+		shard := s.shard(key)
+		if grouped[shard] == nil {
+			grouped[shard] = make(map[string][]byte)
+		}
+		grouped[shard][key] = value
+	}
+
+	for shard, shardItems := range grouped {
+		if err := shard.MSet(ctx, shardItems, ttl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MDelete removes the given keys, locking each shard once
+func (s *ShardedMemoryAdapter) MDelete(ctx context.Context, keys []string) error {
+	for shard, shardKeys := range s.groupKeys(keys) {
+		if err := shard.MDelete(ctx, shardKeys); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// groupKeys splits keys by the shard holding them
+func (s *ShardedMemoryAdapter) groupKeys(keys []string) map[*MemoryAdapter][]string {
+	grouped := make(map[*MemoryAdapter][]string)
+	for _, key := range keys {
+		shard := s.shard(key)
+		grouped[shard] = append(grouped[shard], key)
+	}
+	return grouped
+}
+
+// DeletePattern removes all keys matching the pattern from every shard
+func (s *ShardedMemoryAdapter) DeletePattern(ctx context.Context, pattern string) error {
+	for _, shard := range s.shards {
+		if err := shard.DeletePattern(ctx, pattern); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Scan returns the keys matching the pattern across all shards
+func (s *ShardedMemoryAdapter) Scan(ctx context.Context, pattern string) ([]string, error) {
+	keys := make([]string, 0)
+	for _, shard := range s.shards {
+		shardKeys, err := shard.Scan(ctx, pattern)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, shardKeys...)
+	}
+	return keys, nil
+}
+
+// Clear removes all cached data
+func (s *ShardedMemoryAdapter) Clear(ctx context.Context) error {
+	for _, shard := range s.shards {
+		if err := shard.Clear(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Count returns the number of cached entries across all shards
+func (s *ShardedMemoryAdapter) Count(ctx context.Context) (int, error) {
+	total := 0
+	for _, shard := range s.shards {
+		n, err := shard.Count(ctx)
+		if err != nil {
+			return 0, err
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// Close stops the expired entry cleanup
+func (s *ShardedMemoryAdapter) Close() error {
+	close(s.stopCh)
+	return nil
+}
+
+// startCleanup periodically removes expired items from every shard
+func (s *ShardedMemoryAdapter) startCleanup(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			for _, shard := range s.shards {
+				shard.cleanup()
+			}
+		case <-s.stopCh:
+			return
+		}
+	}
+}
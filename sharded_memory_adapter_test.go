@@ -0,0 +1,104 @@
+package gormcache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestShardedMemoryAdapter(t *testing.T) {
+	adapter := NewShardedMemoryAdapter(8)
+	defer adapter.Close()
+
+	ctx := context.Background()
+	for i := 0; i < 100; i++ {
+		if err := adapter.Set(ctx, fmt.Sprintf("users:%d", i), []byte("value"), time.Minute); err != nil {
+			t.Fatalf("failed to set: %v", err)
+		}
+	}
+	adapter.Set(ctx, "orders:1", []byte("order"), time.Minute)
+
+	// Keys are spread across the shards
+	used := 0
+	for _, shard := range adapter.shards {
+		if n, _ := shard.Count(ctx); n > 0 {
+			used++
+		}
+	}
+	if used < 2 {
+		t.Errorf("expected keys in several shards, got %d", used)
+	}
+
+	if value, err := adapter.Get(ctx, "orders:1"); err != nil || string(value) != "order" {
+		t.Errorf("expected order, got %q, %v", value, err)
+	}
+	if n, _ := adapter.Count(ctx); n != 101 {
+		t.Errorf("expected 101 entries, got %d", n)
+	}
+
+	values, _ := adapter.MGet(ctx, []string{"users:1", "users:2", "missing"})
+	if len(values) != 2 {
+		t.Errorf("expected 2 values, got %d", len(values))
+	}
+
+	if err := adapter.DeletePattern(ctx, "users:*"); err != nil {
+		t.Fatalf("failed to delete pattern: %v", err)
+	}
+	keys, _ := adapter.Scan(ctx, "*")
+	sort.Strings(keys)
+	if len(keys) != 1 || keys[0] != "orders:1" {
+		t.Errorf("expected only orders:1 to be left, got %v", keys)
+	}
+
+	adapter.Clear(ctx)
+	if _, err := adapter.Get(ctx, "orders:1"); !errors.Is(err, ErrCacheMiss) {
+		t.Errorf("expected a cache miss after clear, got %v", err)
+	}
+}
+
+// benchmarkMixedConcurrent runs 100 goroutines issuing an even mix of Get and
+// Set calls against the adapter
+func benchmarkMixedConcurrent(b *testing.B, adapter Adapter) {
+	defer adapter.Close()
+
+	ctx := context.Background()
+	const (
+		goroutines = 100
+		keys       = 1024
+	)
+	names := make([]string, keys)
+	for i := range names {
+		names[i] = fmt.Sprintf("key:%d", i)
+		adapter.Set(ctx, names[i], []byte("value"), time.Minute)
+	}
+
+	b.ResetTimer()
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := g; i < b.N; i += goroutines {
+				key := names[i%keys]
+				if i%2 == 0 {
+					adapter.Set(ctx, key, []byte("value"), time.Minute)
+				} else {
+					adapter.Get(ctx, key)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+func BenchmarkMemoryAdapterMixedConcurrent(b *testing.B) {
+	benchmarkMixedConcurrent(b, NewMemoryAdapter())
+}
+
+func BenchmarkShardedMemoryAdapterMixedConcurrent(b *testing.B) {
+	benchmarkMixedConcurrent(b, NewShardedMemoryAdapter(32))
+}
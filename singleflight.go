@@ -0,0 +1,117 @@
+package gormcache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// flightCall is a cache miss whose query is being run by its first statement,
+// the leader, while later misses of the same key wait for its result
+type flightCall struct {
+	done chan struct{}
+	// data is the serialized result of the leader, nil if it has none to share
+	data []byte
+}
+
+// flightGroup tracks the cache misses in flight by cache key
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+// join returns the call in flight for key, starting one led by the caller if
+// there is none
+func (g *flightGroup) join(key string) (call *flightCall, leader bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if call, ok := g.calls[key]; ok {
+		return call, false
+	}
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+	call = &flightCall{done: make(chan struct{})}
+	g.calls[key] = call
+	return call, true
+}
+
+// finish shares data with the waiters of call and ends it
+func (g *flightGroup) finish(key string, call *flightCall, data []byte) {
+	g.mu.Lock()
+	if g.calls[key] == call {
+		delete(g.calls, key)
+	}
+	g.mu.Unlock()
+
+	call.data = data
+	close(call.done)
+}
+
+// abandon stops new misses of key from waiting for call, so the next one
+// leads a new call; the waiters already joined keep waiting for call
+func (g *flightGroup) abandon(key string, call *flightCall) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.calls[key] == call {
+		delete(g.calls, key)
+	}
+}
+
+// loadShared collapses concurrent misses of cacheKey: the first one becomes
+// the leader and runs the query through the regular GORM pipeline, so
+// preloads, joins and callbacks apply, and the others wait for its serialized
+// result; it returns false if the statement must query the database itself,
+// i.e. it is the leader, the leader has no result to share or it did not
+// finish within SingleflightTimeout
+func (p *CachePlugin) loadShared(ctx context.Context, db *gorm.DB, cacheKey string) bool {
+	if db.Statement.Dest == nil {
+		return false
+	}
+
+	call, leader := p.flight.join(cacheKey)
+	if leader {
+		// afterQueryCallback 结束时把结果交给等待的查询
+		db.Statement.Settings.Store("gorm:cache:flight", call)
+		return false
+	}
+
+	// 领头查询可能 panic 而永远不会结束，等待超时后自行查询数据库
+	timer := time.NewTimer(p.config.SingleflightTimeout)
+	defer timer.Stop()
+	select {
+	case <-call.done:
+	case <-timer.C:
+		p.flight.abandon(cacheKey, call)
+		return false
+	case <-ctx.Done():
+		return false
+	}
+	if call.data == nil {
+		return false
+	}
+
+	if err := p.config.Serializer.Unmarshal(call.data, db.Statement.Dest); err != nil {
+		return false
+	}
+
+	p.storeRequestCache(ctx, cacheKey, call.data)
+
+	// 与缓存命中相同，设置特殊 Error 以跳过数据库查询
+	db.Error = &ErrCacheHit{RowsAffected: calculateRowsAffected(db.Statement.Dest)}
+	return true
+}
+
+// finishFlight ends the call led by db, if any, sharing data with its waiters;
+// it must run once the leader's query completed, whatever its outcome
+func (p *CachePlugin) finishFlight(db *gorm.DB, cacheKey string, data []byte) {
+	v, ok := db.Statement.Settings.LoadAndDelete("gorm:cache:flight")
+	if !ok {
+		return
+	}
+	p.flight.finish(cacheKey, v.(*flightCall), data)
+}
@@ -0,0 +1,244 @@
+package gormcache
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func TestSingleflight(t *testing.T) {
+	db := setupTestDB(t)
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+
+	config := DefaultConfig()
+	config.Adapter = NewMemoryAdapter()
+	// Keep the shared query in flight long enough for all goroutines to join it
+	config.DBQueryHook = func(ctx context.Context, db *gorm.DB, dest interface{}) error {
+		time.Sleep(100 * time.Millisecond)
+		return nil
+	}
+	cachePlugin := New(config)
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	user := TestUser{Name: "Popular"}
+	db.Create(&user)
+
+	queries := countQueries(t, db)
+
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	results := make([]TestUser, 50)
+	errs := make([]error, 50)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			errs[i] = db.First(&results[i], user.ID).Error
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	for i := range results {
+		if errs[i] != nil || results[i].Name != "Popular" {
+			t.Fatalf("goroutine %d: expected the shared result, got %+v (error: %v)", i, results[i], errs[i])
+		}
+	}
+	if *queries != 1 {
+		t.Errorf("expected the database to be queried once, got %d", *queries)
+	}
+}
+
+func TestSingleflightDisabled(t *testing.T) {
+	db := setupTestDB(t)
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+
+	var hookCalls sync.WaitGroup
+	hookCalls.Add(2)
+	cachePlugin := New(Config{
+		Adapter: NewMemoryAdapter(),
+		TTL:     5 * time.Minute,
+		// Hold both queries until each has missed the cache
+		DBQueryHook: func(ctx context.Context, db *gorm.DB, dest interface{}) error {
+			hookCalls.Done()
+			hookCalls.Wait()
+			return nil
+		},
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	user := TestUser{Name: "Stampede"}
+	db.Create(&user)
+	queries := countQueries(t, db)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var result TestUser
+			db.First(&result, user.ID)
+		}()
+	}
+	wg.Wait()
+
+	if *queries != 2 {
+		t.Errorf("expected each miss to query the database without singleflight, got %d", *queries)
+	}
+}
+
+func TestSingleflightEmptyResult(t *testing.T) {
+	db := setupTestDB(t)
+
+	cachePlugin := New(Config{
+		Adapter:             NewMemoryAdapter(),
+		TTL:                 5 * time.Minute,
+		SingleflightEnabled: true,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	var user TestUser
+	if err := db.First(&user, 404).Error; err != gorm.ErrRecordNotFound {
+		t.Errorf("expected ErrRecordNotFound for a missing record, got %v", err)
+	}
+}
+
+type preloadOrder struct {
+	ID     uint
+	UserID uint
+	Item   string
+}
+
+type preloadUser struct {
+	ID     uint
+	Name   string
+	Orders []preloadOrder `gorm:"foreignKey:UserID"`
+}
+
+func TestSingleflightPreload(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&preloadUser{}, &preloadOrder{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+
+	config := DefaultConfig()
+	config.Adapter = NewMemoryAdapter()
+	// Keep the leader in flight long enough for the other goroutines to wait for it
+	config.DBQueryHook = func(ctx context.Context, db *gorm.DB, dest interface{}) error {
+		time.Sleep(50 * time.Millisecond)
+		return nil
+	}
+	cachePlugin := New(config)
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	db.Create(&preloadUser{Name: "John", Orders: []preloadOrder{{Item: "book"}, {Item: "pen"}}})
+
+	// 领头查询经过完整的 GORM 流程，预加载的关联随结果一起共享和缓存
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	results := make([][]preloadUser, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			db.Preload("Orders").Find(&results[i])
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	var cached []preloadUser
+	db.Preload("Orders").Find(&cached)
+	for i, users := range append(results, cached) {
+		if len(users) != 1 || len(users[0].Orders) != 2 {
+			t.Errorf("query %d: expected 1 user with 2 preloaded orders, got %+v", i, users)
+		}
+	}
+}
+
+func TestSingleflightLeaderPanic(t *testing.T) {
+	db := setupTestDB(t)
+
+	config := DefaultConfig()
+	config.Adapter = NewMemoryAdapter()
+	config.SingleflightTimeout = 50 * time.Millisecond
+	cachePlugin := New(config)
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	// The first query panics before the plugin sees its result, once the other
+	// one waits for it
+	var panicked sync.Once
+	err := db.Callback().Query().After("gorm:query").Before("gorm:cache:after_query").
+		Register("test:panic", func(*gorm.DB) {
+			panicked.Do(func() {
+				time.Sleep(20 * time.Millisecond)
+				panic("query failed")
+			})
+		})
+	if err != nil {
+		t.Fatalf("failed to register callback: %v", err)
+	}
+
+	user := TestUser{Name: "Popular"}
+	db.Create(&user)
+
+	leaderDone := make(chan struct{})
+	go func() {
+		defer close(leaderDone)
+		defer func() { _ = recover() }()
+		var result TestUser
+		db.First(&result, user.ID)
+	}()
+	time.Sleep(5 * time.Millisecond)
+
+	done := make(chan error, 1)
+	go func() {
+		var result TestUser
+		done <- db.First(&result, user.ID).Error
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("expected the waiting query to query the database, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the waiting query to give up on the panicked leader")
+	}
+	<-leaderDone
+
+	// Later misses no longer wait for the abandoned call
+	cachePlugin.InvalidateAll(context.Background())
+	start := time.Now()
+	var result TestUser
+	if err := db.First(&result, user.ID).Error; err != nil || result.Name != "Popular" {
+		t.Fatalf("expected the user, got %+v, %v", result, err)
+	}
+	if elapsed := time.Since(start); elapsed >= config.SingleflightTimeout {
+		t.Errorf("expected the query not to wait for the abandoned call, took %v", elapsed)
+	}
+}
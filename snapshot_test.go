@@ -0,0 +1,105 @@
+package gormcache
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// persistenceHook emulates the BGSAVE, LASTSAVE and CONFIG GET commands, which
+// miniredis does not implement; the save completes after a few LASTSAVE polls
+// unless stuck is set
+type persistenceHook struct {
+	mu       sync.Mutex
+	stuck    bool
+	bgsaves  int
+	polls    int
+	lastSave int64
+}
+
+func (h *persistenceHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h *persistenceHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+func (h *persistenceHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		switch cmd.Name() {
+		case "bgsave":
+			h.bgsaves++
+			h.polls = 0
+			cmd.(*redis.StatusCmd).SetVal("Background saving started")
+		case "lastsave":
+			if h.bgsaves > 0 && !h.stuck {
+				if h.polls++; h.polls == 3 {
+					h.lastSave++
+				}
+			}
+			cmd.(*redis.IntCmd).SetVal(h.lastSave)
+		case "config":
+			switch name := cmd.Args()[2].(string); name {
+			case "dir":
+				cmd.(*redis.MapStringStringCmd).SetVal(map[string]string{name: "/data"})
+			case "dbfilename":
+				cmd.(*redis.MapStringStringCmd).SetVal(map[string]string{name: "dump.rdb"})
+			}
+		default:
+			return next(ctx, cmd)
+		}
+		return nil
+	}
+}
+
+func TestRedisSnapshot(t *testing.T) {
+	mr := miniredis.RunT(t)
+
+	hook := &persistenceHook{lastSave: time.Now().Unix()}
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	client.AddHook(hook)
+
+	adapter := NewRedisAdapterWithClient(client)
+	defer adapter.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	file, err := adapter.Snapshot(ctx)
+	if err != nil {
+		t.Fatalf("snapshot failed: %v", err)
+	}
+	if file != "/data/dump.rdb" {
+		t.Errorf("expected /data/dump.rdb, got %q", file)
+	}
+	if hook.bgsaves != 1 {
+		t.Errorf("expected BGSAVE to be called once, got %d", hook.bgsaves)
+	}
+}
+
+func TestRedisSnapshotTimeout(t *testing.T) {
+	mr := miniredis.RunT(t)
+
+	// LASTSAVE never changes before the deadline
+	hook := &persistenceHook{stuck: true}
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	client.AddHook(hook)
+
+	adapter := NewRedisAdapterWithClient(client)
+	defer adapter.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 250*time.Millisecond)
+	defer cancel()
+
+	if _, err := adapter.Snapshot(ctx); err == nil {
+		t.Error("expected snapshot to time out")
+	}
+}
@@ -0,0 +1,83 @@
+package gormcache
+
+import (
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// deletedAtType is the type of GORM's soft-delete field
+var deletedAtType = reflect.TypeOf(gorm.DeletedAt{})
+
+// softDeleteCallback is executed after update when InvalidateOnDelete is set
+// but InvalidateOnUpdate is not, invalidating the cache like a hard delete for
+// updates that set the soft-delete column, e.g. Update("deleted_at", time.Now())
+// db.Delete on a soft-delete model runs the delete callbacks and needs no help
+func (p *CachePlugin) softDeleteCallback(db *gorm.DB) {
+	if isSoftDeleteUpdate(db) {
+		p.invalidateCallback(db)
+	}
+}
+
+// isSoftDeleteUpdate reports whether the statement assigns the soft-delete
+// column of its model
+func isSoftDeleteUpdate(db *gorm.DB) bool {
+	columns := softDeleteColumns(db)
+	if len(columns) == 0 {
+		return false
+	}
+
+	// 用户通过 Clauses 传入的 SET 子句会保留在语句中
+	if c, ok := db.Statement.Clauses["SET"]; ok {
+		if set, ok := c.Expression.(clause.Set); ok {
+			for _, assignment := range set {
+				for _, column := range columns {
+					if assignment.Column.Name == column {
+						return true
+					}
+				}
+			}
+			return false
+		}
+	}
+
+	// GORM 在生成 SQL 后删除由更新值转换的 SET 子句，只能检查 SQL
+	assignments := setList(db.Statement.SQL.String())
+	for _, column := range columns {
+		if strings.Contains(assignments, db.Statement.Quote(column)+"=") {
+			return true
+		}
+	}
+	return false
+}
+
+// setList returns the assignments between SET and WHERE of an UPDATE statement
+func setList(sql string) string {
+	start := strings.Index(sql, " SET ")
+	if start < 0 {
+		return ""
+	}
+	sql = sql[start+len(" SET "):]
+	if end := strings.Index(sql, " WHERE "); end >= 0 {
+		sql = sql[:end]
+	}
+	return sql
+}
+
+// softDeleteColumns returns the columns of the statement's model holding a
+// gorm.DeletedAt, or deleted_at if the statement has no model
+func softDeleteColumns(db *gorm.DB) []string {
+	if db.Statement.Schema == nil {
+		return []string{"deleted_at"}
+	}
+
+	var columns []string
+	for _, field := range db.Statement.Schema.Fields {
+		if field.FieldType == deletedAtType && field.DBName != "" {
+			columns = append(columns, field.DBName)
+		}
+	}
+	return columns
+}
@@ -0,0 +1,62 @@
+package gormcache
+
+import (
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type softDeleteItem struct {
+	gorm.Model
+	Name string
+}
+
+func TestSoftDeleteInvalidation(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&softDeleteItem{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	cachePlugin := New(Config{
+		Adapter:            NewMemoryAdapter(),
+		TTL:                5 * time.Minute,
+		InvalidateOnUpdate: false,
+		InvalidateOnDelete: true,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	a, b, c := softDeleteItem{Name: "a"}, softDeleteItem{Name: "b"}, softDeleteItem{Name: "c"}
+	db.Create(&a)
+	db.Create(&b)
+	db.Create(&c)
+
+	var items []softDeleteItem
+	db.Find(&items)
+
+	// Plain updates do not invalidate with InvalidateOnUpdate off
+	db.Model(&c).Update("name", "changed")
+	items = nil
+	db.Find(&items)
+	if len(items) != 3 || items[2].Name != "c" {
+		t.Fatalf("expected the cached items, got %+v", items)
+	}
+
+	// 手动设置 deleted_at 的软删除与硬删除一样失效缓存
+	db.Model(&a).Update("deleted_at", time.Now())
+	items = nil
+	db.Find(&items)
+	if len(items) != 2 {
+		t.Errorf("expected 2 items after the soft delete update, got %d", len(items))
+	}
+
+	db.Delete(&b)
+	items = nil
+	db.Find(&items)
+	if len(items) != 1 || items[0].Name != "changed" {
+		t.Errorf("expected only the changed item after the soft delete, got %+v", items)
+	}
+}
@@ -0,0 +1,92 @@
+package gormcache
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// staleMarkerPrefix is the prefix of the keys marking a cache entry as stale
+const staleMarkerPrefix = "stale:"
+
+// softInvalidate marks all cached entries matching pattern as stale instead of
+// deleting them; it returns false if the adapter cannot list its keys
+func (p *CachePlugin) softInvalidate(ctx context.Context, pattern string) bool {
+	if !scannable(p.adapter()) {
+		return false
+	}
+
+	keys, err := scanKeys(ctx, p.adapter(), pattern)
+	if err != nil {
+		return false
+	}
+
+	marker := []byte(strconv.FormatInt(time.Now().UnixNano(), 10))
+	for _, key := range keys {
+		// 元数据等附属键随条目一起刷新，无需单独标记
+		if isSidecarKey(key) {
+			continue
+		}
+		// 标记与条目同时过期，避免条目比标记存活更久而被当作新鲜数据
+		ttl, err := p.markerTTL(ctx, key)
+		if err != nil {
+			continue
+		}
+		if err := p.adapter().Set(ctx, staleMarkerPrefix+key, marker, ttl); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// markerTTL returns the remaining lifetime of the entry under key, read from
+// the adapter (or an adapter it wraps) or the metadata sidecar, or 0 (no
+// expiry) if neither knows it
+// It returns an error wrapping ErrCacheMiss if the entry already expired
+func (p *CachePlugin) markerTTL(ctx context.Context, key string) (time.Duration, error) {
+	for _, a := range adapterChain(p.adapter()) {
+		if a, ok := a.(TTLAdapter); ok {
+			return a.TTL(ctx, key)
+		}
+	}
+
+	meta, err := p.loadMetadata(ctx, key)
+	if err != nil || meta.TTL <= 0 {
+		return 0, nil
+	}
+	ttl := time.Until(meta.SetAt.Add(meta.TTL))
+	if ttl <= 0 {
+		return 0, fmt.Errorf("%w: key expired", ErrCacheMiss)
+	}
+	return ttl, nil
+}
+
+// refreshIfStale starts a background refresh of a cache entry marked as stale;
+// the marker is removed once the entry is refreshed, unless the entry was
+// invalidated again in the meantime
+// Entries of statements that cannot be refreshed in the background are deleted
+func (p *CachePlugin) refreshIfStale(ctx context.Context, db *gorm.DB, cacheKey string) {
+	markerKey := staleMarkerPrefix + cacheKey
+
+	marker, err := p.adapter().Get(ctx, markerKey)
+	if err != nil {
+		return
+	}
+
+	// 带有预加载或关联的查询无法在后台刷新，删除条目使下一次查询完整加载
+	if !detachable(db) {
+		p.deleteEntry(ctx, cacheKey)
+		_ = p.adapter().Delete(ctx, markerKey)
+		return
+	}
+
+	p.refreshInBackground(db, cacheKey, func(ctx context.Context) {
+		if current, err := p.adapter().Get(ctx, markerKey); err == nil && bytes.Equal(current, marker) {
+			_ = p.adapter().Delete(ctx, markerKey)
+		}
+	})
+}
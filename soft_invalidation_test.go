@@ -0,0 +1,162 @@
+package gormcache
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSoftInvalidation(t *testing.T) {
+	db := setupTestDB(t)
+
+	// Keep the in-memory database on a single connection for background refreshes
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+
+	adapter := NewMemoryAdapter()
+	cachePlugin := New(Config{
+		Adapter:            adapter,
+		TTL:                5 * time.Minute,
+		InvalidateOnUpdate: true,
+		SoftInvalidation:   true,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	user := TestUser{Name: "Original Name"}
+	db.Create(&user)
+
+	first := func() string {
+		var result TestUser
+		if err := db.First(&result, user.ID).Error; err != nil {
+			t.Fatalf("query failed: %v", err)
+		}
+		return result.Name
+	}
+
+	first()
+
+	// The write marks the entry stale instead of deleting it
+	db.Model(&user).Update("Name", "Updated Name")
+
+	hasMarker := func() bool {
+		adapter.mu.RLock()
+		defer adapter.mu.RUnlock()
+		for key := range adapter.store {
+			if strings.HasPrefix(key, staleMarkerPrefix) {
+				return true
+			}
+		}
+		return false
+	}
+	if !hasMarker() {
+		t.Fatal("expected a stale marker after the update")
+	}
+
+	// The stale entry is still served from cache
+	if name := first(); name != "Original Name" {
+		t.Fatalf("expected stale 'Original Name', got '%s'", name)
+	}
+
+	// The background refresh replaces the entry and removes the marker
+	deadline := time.Now().Add(2 * time.Second)
+	for hasMarker() {
+		if time.Now().After(deadline) {
+			t.Fatal("expected background refresh to remove the stale marker")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if name := first(); name != "Updated Name" {
+		t.Errorf("expected refreshed 'Updated Name', got '%s'", name)
+	}
+}
+
+func TestSoftInvalidationMarkerOutlivesEntry(t *testing.T) {
+	db := setupTestDB(t)
+
+	adapter := NewMemoryAdapter()
+	cachePlugin := New(Config{
+		Adapter:            adapter,
+		TTL:                time.Minute,
+		ModelTTLs:          map[string]time.Duration{"test_users": time.Hour},
+		InvalidateOnUpdate: true,
+		SoftInvalidation:   true,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	user := TestUser{Name: "Original Name"}
+	db.Create(&user)
+
+	var result TestUser
+	db.First(&result, user.ID)
+	db.Model(&user).Update("Name", "Updated Name")
+
+	adapter.mu.RLock()
+	var markers []string
+	for key := range adapter.store {
+		if strings.HasPrefix(key, staleMarkerPrefix) {
+			markers = append(markers, key)
+		}
+	}
+	adapter.mu.RUnlock()
+	if len(markers) == 0 {
+		t.Fatal("expected a stale marker after the update")
+	}
+
+	// 标记必须与条目一样长寿，而不是使用默认 TTL
+	for _, marker := range markers {
+		ttl, err := adapter.TTL(context.Background(), marker)
+		if err != nil || ttl <= time.Minute {
+			t.Errorf("expected %s to expire with its one hour entry, got %v, %v", marker, ttl, err)
+		}
+	}
+}
+
+func TestSoftInvalidationWrappedAdapter(t *testing.T) {
+	db := setupTestDB(t)
+
+	adapter := NewMemoryAdapter()
+	cachePlugin := New(Config{
+		Adapter:            NewMetricsAdapter(adapter, "gormcache_test_soft_invalidation"),
+		TTL:                time.Minute,
+		InvalidateOnUpdate: true,
+		SoftInvalidation:   true,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	user := TestUser{Name: "Original Name"}
+	db.Create(&user)
+
+	var result TestUser
+	db.First(&result, user.ID)
+	db.Model(&user).Update("Name", "Updated Name")
+
+	// The wrapped adapter is scanned for the entries to mark stale
+	adapter.mu.RLock()
+	var markers []string
+	for key := range adapter.store {
+		if strings.HasPrefix(key, staleMarkerPrefix) {
+			markers = append(markers, key)
+		}
+	}
+	adapter.mu.RUnlock()
+	if len(markers) == 0 {
+		t.Fatal("expected a stale marker in the wrapped adapter")
+	}
+
+	for _, marker := range markers {
+		if ttl, err := adapter.TTL(context.Background(), marker); err != nil || ttl <= 0 {
+			t.Errorf("expected %s to expire with its entry, got %v, %v", marker, ttl, err)
+		}
+	}
+}
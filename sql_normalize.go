@@ -0,0 +1,94 @@
+package gormcache
+
+import (
+	"strings"
+	"unicode"
+)
+
+// sqlKeywords are the words lowercased by canonicalSQL; identifiers are kept
+// as written, since some databases compare them case-sensitively
+var sqlKeywords = map[string]bool{
+	"all": true, "and": true, "any": true, "as": true, "asc": true, "avg": true,
+	"between": true, "by": true, "case": true, "count": true, "cross": true,
+	"desc": true, "distinct": true, "else": true, "end": true, "exists": true,
+	"false": true, "for": true, "from": true, "full": true, "group": true,
+	"having": true, "ilike": true, "in": true, "inner": true, "is": true,
+	"join": true, "left": true, "like": true, "limit": true, "max": true,
+	"min": true, "natural": true, "not": true, "null": true, "offset": true,
+	"on": true, "or": true, "order": true, "outer": true, "right": true,
+	"select": true, "share": true, "some": true, "sum": true, "then": true,
+	"true": true, "union": true, "update": true, "using": true, "when": true,
+	"where": true, "with": true,
+}
+
+// canonicalSQL collapses whitespace runs to a single space and lowercases SQL
+// keywords, leaving quoted strings and identifiers ('...', "...", `...`) intact
+func canonicalSQL(query string) string {
+	var b strings.Builder
+	b.Grow(len(query))
+
+	pendingSpace := false
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			pendingSpace = b.Len() > 0
+			i++
+			continue
+		case c == '\'' || c == '"' || c == '`':
+			end := closingQuote(query, i)
+			writeSpace(&b, &pendingSpace)
+			b.WriteString(query[i:end])
+			i = end
+			continue
+		case isWordByte(c):
+			end := i
+			for end < len(query) && isWordByte(query[end]) {
+				end++
+			}
+			word := query[i:end]
+			if lower := strings.ToLower(word); sqlKeywords[lower] {
+				word = lower
+			}
+			writeSpace(&b, &pendingSpace)
+			b.WriteString(word)
+			i = end
+			continue
+		}
+
+		writeSpace(&b, &pendingSpace)
+		b.WriteByte(c)
+		i++
+	}
+	return b.String()
+}
+
+// closingQuote returns the index after the quoted section starting at start,
+// treating a doubled quote character as an escaped one
+func closingQuote(query string, start int) int {
+	quote := query[start]
+	for i := start + 1; i < len(query); i++ {
+		if query[i] != quote {
+			continue
+		}
+		if i+1 < len(query) && query[i+1] == quote {
+			i++
+			continue
+		}
+		return i + 1
+	}
+	return len(query)
+}
+
+// writeSpace writes the space collapsed from a whitespace run, if any
+func writeSpace(b *strings.Builder, pending *bool) {
+	if *pending {
+		b.WriteByte(' ')
+		*pending = false
+	}
+}
+
+// isWordByte reports whether c can be part of an unquoted SQL word
+func isWordByte(c byte) bool {
+	return c == '_' || c >= 0x80 || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c))
+}
@@ -0,0 +1,90 @@
+package gormcache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStaleWhileRevalidate(t *testing.T) {
+	db := setupTestDB(t)
+
+	// Keep the in-memory database on a single connection for background refreshes
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+
+	cachePlugin := New(Config{
+		Adapter:              NewMemoryAdapter(),
+		TTL:                  time.Second,
+		StaleWhileRevalidate: 900 * time.Millisecond,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	user := TestUser{Name: "Original Name"}
+	db.Create(&user)
+
+	first := func() string {
+		var result TestUser
+		if err := db.First(&result, user.ID).Error; err != nil {
+			t.Fatalf("query failed: %v", err)
+		}
+		return result.Name
+	}
+
+	first()
+
+	// The write does not invalidate the entry, only a refresh can pick it up
+	db.Model(&user).Update("Name", "Updated Name")
+
+	// Once the entry is within StaleWhileRevalidate of its expiry, it is still
+	// served from cache while a background refresh reloads it
+	time.Sleep(150 * time.Millisecond)
+	hits := cachePlugin.Stats().Hits
+	if name := first(); name != "Original Name" {
+		t.Fatalf("expected stale 'Original Name', got '%s'", name)
+	}
+	if cachePlugin.Stats().Hits != hits+1 {
+		t.Fatal("expected the stale entry to be served from cache")
+	}
+
+	// The refresh completes well before the original TTL expires
+	deadline := time.Now().Add(500 * time.Millisecond)
+	for first() != "Updated Name" {
+		if time.Now().After(deadline) {
+			t.Fatal("expected background refresh to reload the entry")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestStaleWhileRevalidateOutsideWindow(t *testing.T) {
+	db := setupTestDB(t)
+
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+
+	cachePlugin := New(Config{
+		Adapter:              NewMemoryAdapter(),
+		TTL:                  5 * time.Minute,
+		StaleWhileRevalidate: time.Second,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	db.Create(&TestUser{Name: "John"})
+
+	queries := countQueries(t, db)
+	var users []TestUser
+	db.Find(&users)
+	db.Find(&users)
+
+	// A fresh entry is served without refreshing it
+	time.Sleep(50 * time.Millisecond)
+	if *queries != 1 {
+		t.Errorf("expected 1 database query, got %d", *queries)
+	}
+}
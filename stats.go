@@ -0,0 +1,55 @@
+package gormcache
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Stats holds cache effectiveness counters
+type Stats struct {
+	Hits          int64 // Queries served from the cache
+	Misses        int64 // Queries looked up in the cache and sent to the database
+	Errors        int64 // Cached values that could not be (de)serialized or stored
+	Invalidations int64 // Writes that invalidated cached queries
+	BytesStored   int64 // Total size of the values written to the cache
+}
+
+// cacheStats holds the live counters behind Stats
+type cacheStats struct {
+	hits          atomic.Int64
+	misses        atomic.Int64
+	errors        atomic.Int64
+	invalidations atomic.Int64
+	bytesStored   atomic.Int64
+
+	// latency observes the duration of adapter reads and writes, if set
+	latency atomic.Pointer[func(operation string, d time.Duration)]
+}
+
+// observeLatency reports the duration of an adapter operation started at start
+func (s *cacheStats) observeLatency(operation string, start time.Time) {
+	if observe := s.latency.Load(); observe != nil {
+		(*observe)(operation, time.Since(start))
+	}
+}
+
+// Stats returns a snapshot of the cache counters since the plugin was created
+// or ResetStats was last called
+func (p *CachePlugin) Stats() Stats {
+	return Stats{
+		Hits:          p.stats.hits.Load(),
+		Misses:        p.stats.misses.Load(),
+		Errors:        p.stats.errors.Load(),
+		Invalidations: p.stats.invalidations.Load(),
+		BytesStored:   p.stats.bytesStored.Load(),
+	}
+}
+
+// ResetStats zeroes all cache counters
+func (p *CachePlugin) ResetStats() {
+	p.stats.hits.Store(0)
+	p.stats.misses.Store(0)
+	p.stats.errors.Store(0)
+	p.stats.invalidations.Store(0)
+	p.stats.bytesStored.Store(0)
+}
@@ -0,0 +1,80 @@
+package gormcache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestStats(t *testing.T) {
+	db := setupTestDB(t)
+
+	cachePlugin := New(Config{
+		Adapter:            NewMemoryAdapter(),
+		TTL:                5 * time.Minute,
+		InvalidateOnCreate: true,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	user := TestUser{Name: "Counted"}
+	db.Create(&user)
+
+	var first TestUser
+	db.First(&first, user.ID)
+	var second TestUser
+	db.First(&second, user.ID)
+
+	stats := cachePlugin.Stats()
+	if stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("expected 1 hit and 1 miss, got %+v", stats)
+	}
+	if stats.BytesStored <= 0 {
+		t.Errorf("expected stored bytes to be counted, got %d", stats.BytesStored)
+	}
+	if stats.Invalidations != 1 {
+		t.Errorf("expected the create to count as an invalidation, got %d", stats.Invalidations)
+	}
+	if stats.Errors != 0 {
+		t.Errorf("expected no errors, got %d", stats.Errors)
+	}
+
+	cachePlugin.ResetStats()
+	if stats := cachePlugin.Stats(); stats != (Stats{}) {
+		t.Errorf("expected zeroed stats after ResetStats, got %+v", stats)
+	}
+}
+
+func TestStatsErrors(t *testing.T) {
+	db := setupTestDB(t)
+
+	adapter := NewMemoryAdapter()
+	cachePlugin := New(Config{Adapter: adapter, TTL: 5 * time.Minute})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	user := TestUser{Name: "Corrupted"}
+	db.Create(&user)
+
+	var first TestUser
+	db.First(&first, user.ID)
+
+	// Corrupt the cached value
+	for key := range adapter.store {
+		_ = adapter.Set(context.Background(), key, []byte("not json"), time.Minute)
+	}
+
+	var second TestUser
+	if err := db.First(&second, user.ID).Error; err != nil || second.Name != "Corrupted" {
+		t.Fatalf("expected the corrupted entry to fall back to the database, got %+v (error: %v)", second, err)
+	}
+
+	stats := cachePlugin.Stats()
+	if stats.Errors != 1 || stats.Misses != 2 || stats.Hits != 0 {
+		t.Errorf("expected 1 error, 2 misses and no hits, got %+v", stats)
+	}
+}
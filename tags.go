@@ -0,0 +1,188 @@
+package gormcache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// tagIndexPrefix is the prefix of the reverse indexes of tags; it lies outside
+// the plugin key prefix so model invalidation and CacheSize leave them alone
+const tagIndexPrefix = "gorm:tag:"
+
+// tagIndexKey returns the key of the reverse index holding the cache keys of tag
+// The key prefix and cache version are kept in the key, so plugins sharing a
+// backend with different prefixes or versions have their own indexes
+func (c *Config) tagIndexKey(tag string) string {
+	return tagIndexPrefix + c.keyPrefix() + tag
+}
+
+// modelTags returns the tags configured in CacheTags for the statement's model
+func (p *CachePlugin) modelTags(db *gorm.DB) []string {
+	if len(p.cacheTags) == 0 || db.Statement.Schema == nil {
+		return nil
+	}
+	return p.cacheTags[db.Statement.Schema.ModelType.String()]
+}
+
+// statementTags returns the model's CacheTags followed by the tags added to the
+// statement with WithCacheTags
+func (p *CachePlugin) statementTags(db *gorm.DB) []string {
+	tags := p.modelTags(db)
+	if v, ok := db.Statement.Settings.Load("gorm:cache:tags"); ok {
+		if scopeTags, ok := v.([]string); ok {
+			tags = append(append([]string(nil), tags...), scopeTags...)
+		}
+	}
+	return tags
+}
+
+// tagKey adds cacheKey, cached for ttl, to the reverse index of every tag
+func (p *CachePlugin) tagKey(ctx context.Context, cacheKey string, tags []string, ttl time.Duration) error {
+	var errs []error
+	for _, tag := range tags {
+		errs = append(errs, p.addTaggedKey(ctx, p.config.tagIndexKey(tag), cacheKey, ttl))
+	}
+	return errors.Join(errs...)
+}
+
+// taggedKey is a member of a JSON encoded tag index
+type taggedKey struct {
+	Key       string    `json:"key"`
+	ExpiresAt time.Time `json:"expires_at,omitempty"` // zero if it never expires
+}
+
+// addTaggedKey adds cacheKey to the index stored under indexKey, which
+// expires no earlier than its longest lived member
+// Redis keeps the index in a set, other adapters in a JSON encoded list
+func (p *CachePlugin) addTaggedKey(ctx context.Context, indexKey, cacheKey string, ttl time.Duration) error {
+	if client, ok := redisCmdableOf(p.adapter()); ok {
+		return addRedisTaggedKey(ctx, client, indexKey, cacheKey, ttl)
+	}
+
+	p.tagMu.Lock()
+	defer p.tagMu.Unlock()
+
+	members, err := p.loadTagIndex(ctx, indexKey)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = now.Add(ttl)
+	}
+
+	// 重写索引时去掉已过期的成员
+	kept := members[:0]
+	for _, member := range members {
+		if member.Key == cacheKey || (!member.ExpiresAt.IsZero() && !member.ExpiresAt.After(now)) {
+			continue
+		}
+		kept = append(kept, member)
+	}
+	kept = append(kept, taggedKey{Key: cacheKey, ExpiresAt: expiresAt})
+
+	var indexTTL time.Duration
+	for _, member := range kept {
+		if member.ExpiresAt.IsZero() {
+			indexTTL = 0
+			break
+		}
+		if remaining := member.ExpiresAt.Sub(now); remaining > indexTTL {
+			indexTTL = remaining
+		}
+	}
+
+	data, err := json.Marshal(kept)
+	if err != nil {
+		return err
+	}
+	return p.adapter().Set(ctx, indexKey, data, indexTTL)
+}
+
+// addRedisTaggedKey adds cacheKey to the Redis set under indexKey, extending
+// its expiration to ttl if it would expire earlier
+func addRedisTaggedKey(ctx context.Context, client redis.Cmdable, indexKey, cacheKey string, ttl time.Duration) error {
+	current, err := client.PTTL(ctx, indexKey).Result()
+	if err != nil {
+		return err
+	}
+
+	pipe := client.TxPipeline()
+	pipe.SAdd(ctx, indexKey, cacheKey)
+	switch {
+	case ttl <= 0:
+		pipe.Persist(ctx, indexKey)
+	case current == -2 || (current >= 0 && current < ttl):
+		// -2 表示索引不存在，-1 表示索引永不过期
+		pipe.PExpire(ctx, indexKey, ttl)
+	}
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// loadTagIndex returns the members of the JSON encoded index under indexKey
+func (p *CachePlugin) loadTagIndex(ctx context.Context, indexKey string) ([]taggedKey, error) {
+	data, err := p.adapter().Get(ctx, indexKey)
+	if err != nil {
+		// 索引不存在表示没有 key 打上该标签
+		return nil, nil
+	}
+
+	var members []taggedKey
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// taggedKeys returns the cache keys in the index stored under indexKey
+func (p *CachePlugin) taggedKeys(ctx context.Context, indexKey string) ([]string, error) {
+	if client, ok := redisCmdableOf(p.adapter()); ok {
+		return client.SMembers(ctx, indexKey).Result()
+	}
+
+	members, err := p.loadTagIndex(ctx, indexKey)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(members))
+	for _, member := range members {
+		keys = append(keys, member.Key)
+	}
+	return keys, nil
+}
+
+// InvalidateTags deletes all cached queries tagged with any of tags
+func (p *CachePlugin) InvalidateTags(ctx context.Context, tags ...string) error {
+	var errs []error
+	for _, tag := range tags {
+		indexKey := p.config.tagIndexKey(tag)
+
+		keys, err := p.taggedKeys(ctx, indexKey)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("gorm:cache: tag %q: %w", tag, err))
+			continue
+		}
+
+		for _, key := range keys {
+			if err := p.adapter().Delete(ctx, key); err != nil {
+				errs = append(errs, fmt.Errorf("gorm:cache: tag %q: %w", tag, err))
+			}
+		}
+		if len(keys) > 0 {
+			p.publishInvalidation(ctx, invalidationMessage{Keys: keys})
+		}
+		if err := p.adapter().Delete(ctx, indexKey); err != nil {
+			errs = append(errs, fmt.Errorf("gorm:cache: tag %q: %w", tag, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,188 @@
+package gormcache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func testCacheTags(t *testing.T, adapter Adapter) {
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&testOrder{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	cachePlugin := New(Config{
+		Adapter: adapter,
+		TTL:     5 * time.Minute,
+		CacheTags: map[interface{}][]string{
+			TestUser{}: {"user-data"},
+		},
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	db.Create(&TestUser{Name: "John"})
+	db.Create(&TestUser{Name: "Jane"})
+	db.Create(&testOrder{UserID: 1})
+
+	queries := countQueries(t, db)
+	run := func() {
+		var user TestUser
+		db.First(&user, 1)
+		var users []TestUser
+		db.Find(&users)
+		var order testOrder
+		db.First(&order, 1)
+	}
+
+	run()
+	run()
+	if *queries != 3 {
+		t.Fatalf("expected 3 database queries, got %d", *queries)
+	}
+
+	if err := cachePlugin.InvalidateTags(context.Background(), "user-data"); err != nil {
+		t.Fatalf("failed to invalidate tags: %v", err)
+	}
+
+	// Both user queries reach the database again, the order query stays cached
+	run()
+	if *queries != 5 {
+		t.Errorf("expected the 2 tagged user queries to be invalidated, got %d database queries", *queries)
+	}
+}
+
+func TestCacheTags(t *testing.T) {
+	testCacheTags(t, NewMemoryAdapter())
+}
+
+func TestCacheTagsRedis(t *testing.T) {
+	mr := miniredis.RunT(t)
+	testCacheTags(t, NewRedisAdapter(RedisAdapterConfig{Addr: mr.Addr()}))
+}
+
+func TestWithCacheTags(t *testing.T) {
+	db := setupTestDB(t)
+
+	cachePlugin := New(Config{
+		Adapter: NewMemoryAdapter(),
+		TTL:     5 * time.Minute,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	db.Create(&TestUser{Name: "Keyboard"})
+	db.Create(&TestUser{Name: "Mouse"})
+
+	queries := countQueries(t, db)
+	run := func() ([]TestUser, TestUser) {
+		var list []TestUser
+		db.Scopes(WithCacheTags("product-list")).Order("id").Find(&list)
+		var first TestUser
+		db.Scopes(WithCacheTags("product-list")).First(&first)
+		return list, first
+	}
+
+	run()
+	run()
+	if *queries != 2 {
+		t.Fatalf("expected 2 database queries, got %d", *queries)
+	}
+
+	// InvalidateOnUpdate 未开启，更新不会清除缓存
+	db.Model(&TestUser{}).Where("id = ?", 1).Update("name", "Mechanical Keyboard")
+	if err := cachePlugin.InvalidateTags(context.Background(), "product-list"); err != nil {
+		t.Fatalf("failed to invalidate tags: %v", err)
+	}
+
+	list, first := run()
+	if *queries != 4 {
+		t.Errorf("expected both tagged queries to be invalidated, got %d database queries", *queries)
+	}
+	if len(list) != 2 || list[0].Name != "Mechanical Keyboard" || first.Name != "Mechanical Keyboard" {
+		t.Errorf("expected the updated product, got %+v and %+v", list, first)
+	}
+}
+
+func TestTagIndexOutlivesMembers(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+
+	tests := []struct {
+		name    string
+		adapter Adapter
+		ttl     func(key string) time.Duration
+	}{
+		{"memory", NewMemoryAdapter(), nil},
+		{"redis", NewRedisAdapter(RedisAdapterConfig{Addr: mr.Addr()}), mr.TTL},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cachePlugin := New(Config{Adapter: tt.adapter, TTL: time.Minute})
+			defer cachePlugin.Close()
+
+			indexKey := cachePlugin.config.tagIndexKey("user-data")
+			if err := cachePlugin.tagKey(ctx, "long", []string{"user-data"}, time.Hour); err != nil {
+				t.Fatalf("failed to tag key: %v", err)
+			}
+			if err := cachePlugin.tagKey(ctx, "short", []string{"user-data"}, time.Second); err != nil {
+				t.Fatalf("failed to tag key: %v", err)
+			}
+
+			var ttl time.Duration
+			if tt.ttl != nil {
+				ttl = tt.ttl(indexKey)
+			} else {
+				ttl, _ = tt.adapter.(TTLAdapter).TTL(ctx, indexKey)
+			}
+			if ttl <= time.Minute {
+				t.Errorf("expected the index to live as long as its one hour member, got %v", ttl)
+			}
+		})
+	}
+}
+
+func TestTagIndexPrunesExpiredMembers(t *testing.T) {
+	ctx := context.Background()
+	cachePlugin := New(Config{Adapter: NewMemoryAdapter(), TTL: time.Minute})
+	defer cachePlugin.Close()
+
+	cachePlugin.tagKey(ctx, "expired", []string{"user-data"}, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	cachePlugin.tagKey(ctx, "live", []string{"user-data"}, time.Minute)
+
+	keys, err := cachePlugin.taggedKeys(ctx, cachePlugin.config.tagIndexKey("user-data"))
+	if err != nil {
+		t.Fatalf("failed to read tag index: %v", err)
+	}
+	if len(keys) != 1 || keys[0] != "live" {
+		t.Errorf("expected only the live key in the index, got %v", keys)
+	}
+}
+
+func TestTagIndexOutsideKeyPrefix(t *testing.T) {
+	ctx := context.Background()
+	adapter := NewMemoryAdapter()
+	cachePlugin := New(Config{Adapter: adapter, TTL: time.Minute})
+	defer cachePlugin.Close()
+
+	if err := cachePlugin.tagKey(ctx, "gorm:cache:test_users:1", []string{"user-data"}, time.Minute); err != nil {
+		t.Fatalf("failed to tag key: %v", err)
+	}
+
+	// Deleting every entry under the key prefix leaves the index alone
+	if err := adapter.DeletePattern(ctx, "gorm:cache:*"); err != nil {
+		t.Fatalf("failed to delete pattern: %v", err)
+	}
+	keys, err := cachePlugin.taggedKeys(ctx, cachePlugin.config.tagIndexKey("user-data"))
+	if err != nil || len(keys) != 1 {
+		t.Errorf("expected the index to keep its member, got %v, %v", keys, err)
+	}
+}
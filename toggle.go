@@ -0,0 +1,27 @@
+package gormcache
+
+import "gorm.io/gorm"
+
+// Disable turns caching off at runtime: queries are neither served from nor
+// stored in the cache until Enable is called
+// Writes keep invalidating cached queries, so no stale entries are served
+// once caching is enabled again
+func (p *CachePlugin) Disable() {
+	p.disabled.Store(true)
+}
+
+// Enable turns caching back on after Disable
+func (p *CachePlugin) Enable() {
+	p.disabled.Store(false)
+}
+
+// IsEnabled reports whether caching is enabled
+func (p *CachePlugin) IsEnabled() bool {
+	return !p.disabled.Load()
+}
+
+// shouldSkipCache checks if cache should be skipped, because the plugin is
+// disabled or for the reasons of Config.shouldSkipCache
+func (p *CachePlugin) shouldSkipCache(db *gorm.DB) bool {
+	return p.disabled.Load() || p.config.shouldSkipCache(db)
+}
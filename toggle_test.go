@@ -0,0 +1,59 @@
+package gormcache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDisableEnable(t *testing.T) {
+	db := setupTestDB(t)
+
+	cachePlugin := New(Config{
+		Adapter: NewMemoryAdapter(),
+		TTL:     5 * time.Minute,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	user := TestUser{Name: "John"}
+	db.Create(&user)
+
+	queries := countQueries(t, db)
+	first := func() string {
+		var result TestUser
+		if err := db.First(&result, user.ID).Error; err != nil {
+			t.Fatalf("query failed: %v", err)
+		}
+		return result.Name
+	}
+
+	if !cachePlugin.IsEnabled() {
+		t.Fatal("expected the plugin to be enabled by default")
+	}
+
+	cachePlugin.Disable()
+	if cachePlugin.IsEnabled() {
+		t.Fatal("expected the plugin to be disabled")
+	}
+
+	// While disabled, both queries read live data
+	first()
+	db.Model(&TestUser{}).Where("id = ?", user.ID).UpdateColumn("name", "Jane")
+	if name := first(); name != "Jane" {
+		t.Errorf("expected live 'Jane' while disabled, got '%s'", name)
+	}
+	if *queries != 2 {
+		t.Errorf("expected 2 database queries while disabled, got %d", *queries)
+	}
+
+	cachePlugin.Enable()
+
+	// Once enabled, the first query populates the cache and the second is served from it
+	first()
+	first()
+	if *queries != 3 {
+		t.Errorf("expected the second query to be served from cache, got %d database queries", *queries)
+	}
+}
@@ -0,0 +1,50 @@
+package gormcache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Touch resets the TTL of the cached entry stored under key to Config.TTL
+// without changing its value, returning an error wrapping ErrCacheMiss if the
+// key is not cached
+// Adapters implementing TouchAdapter update the expiration in place; others
+// read the value and store it again
+func (p *CachePlugin) Touch(ctx context.Context, key string) error {
+	if err := p.touch(ctx, key); err != nil {
+		return err
+	}
+
+	// 元数据记录新的过期时间，否则 StaleWhileRevalidate 和 RefreshThreshold
+	// 仍按旧的过期时间刷新条目
+	if p.needsMetadata() {
+		meta, err := p.loadMetadata(ctx, key)
+		if errors.Is(err, ErrCacheMiss) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		meta.SetAt, meta.TTL = time.Now(), p.config.TTL
+		meta.CreatedAt = meta.createdAt()
+		return p.storeMetadata(ctx, key, *meta)
+	}
+	return nil
+}
+
+// touch resets the TTL of a single adapter key
+func (p *CachePlugin) touch(ctx context.Context, key string) error {
+	adapter := p.adapter()
+	ttl := p.config.TTL
+
+	if toucher, ok := adapter.(TouchAdapter); ok {
+		return toucher.Touch(ctx, key, ttl)
+	}
+
+	value, err := adapter.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	return adapter.Set(ctx, key, value, ttl)
+}
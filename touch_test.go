@@ -0,0 +1,95 @@
+package gormcache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// plainAdapter hides the optional interfaces of the adapter it wraps
+type plainAdapter struct {
+	Adapter
+}
+
+func TestTouch(t *testing.T) {
+	tests := []struct {
+		name    string
+		adapter Adapter
+	}{
+		{"map", NewMemoryAdapter()},
+		{"sync.Map", NewMemoryAdapterWithSyncMap()},
+		{"get and set", plainAdapter{NewMemoryAdapter()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cachePlugin := New(Config{Adapter: tt.adapter, TTL: 100 * time.Millisecond})
+			defer cachePlugin.Close()
+
+			ctx := context.Background()
+			tt.adapter.Set(ctx, "key1", []byte("value1"), 100*time.Millisecond)
+
+			time.Sleep(80 * time.Millisecond)
+			if err := cachePlugin.Touch(ctx, "key1"); err != nil {
+				t.Fatalf("failed to touch: %v", err)
+			}
+			time.Sleep(80 * time.Millisecond)
+
+			// 没有 Touch 时条目已经在 100ms 后过期
+			value, err := tt.adapter.Get(ctx, "key1")
+			if err != nil || string(value) != "value1" {
+				t.Errorf("expected the touched entry to be valid, got %q, %v", value, err)
+			}
+
+			if err := cachePlugin.Touch(ctx, "missing"); !errors.Is(err, ErrCacheMiss) {
+				t.Errorf("expected ErrCacheMiss for a missing key, got %v", err)
+			}
+		})
+	}
+}
+
+func TestTouchMetadata(t *testing.T) {
+	db := setupTestDB(t)
+
+	cachePlugin := New(Config{
+		Adapter:              NewMemoryAdapter(),
+		TTL:                  time.Hour,
+		StaleWhileRevalidate: time.Minute,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	db.Create(&TestUser{Name: "Touched"})
+	var users []TestUser
+	db.Find(&users)
+
+	ctx := context.Background()
+	keys, err := cachePlugin.Keys(ctx, "test_users")
+	if err != nil || len(keys) != 1 {
+		t.Fatalf("expected one cached entry, got %v, %v", keys, err)
+	}
+	before, err := cachePlugin.loadMetadata(ctx, keys[0])
+	if err != nil {
+		t.Fatalf("failed to load metadata: %v", err)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	if err := cachePlugin.Touch(ctx, keys[0]); err != nil {
+		t.Fatalf("failed to touch: %v", err)
+	}
+
+	// The metadata records the new expiry but keeps the read time
+	after, err := cachePlugin.loadMetadata(ctx, keys[0])
+	if err != nil {
+		t.Fatalf("failed to load metadata: %v", err)
+	}
+	if !after.SetAt.After(before.SetAt) || after.TTL != time.Hour {
+		t.Errorf("expected the expiry to restart at the touch, got %+v, was %+v", after, before)
+	}
+	if !after.createdAt().Equal(before.createdAt()) {
+		t.Errorf("expected the read time to be kept, got %v, was %v", after.createdAt(), before.createdAt())
+	}
+}
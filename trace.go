@@ -0,0 +1,50 @@
+package gormcache
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+)
+
+const (
+	// traceSuffix is appended to a cache key to form the key of its stack trace
+	traceSuffix = ":trace"
+
+	// maxTraceDepth bounds the number of frames captured by TraceQueries
+	maxTraceDepth = 64
+)
+
+// GetTrace returns the stack trace of the query that stored the cache entry
+// key, recorded when Config.TraceQueries is enabled
+func (p *CachePlugin) GetTrace(ctx context.Context, key string) (string, error) {
+	trace, err := p.adapter().Get(ctx, key+traceSuffix)
+	if err != nil {
+		return "", err
+	}
+	return string(trace), nil
+}
+
+// storeTrace stores the stack trace of the current goroutine next to cacheKey
+func (p *CachePlugin) storeTrace(ctx context.Context, cacheKey string, ttl time.Duration) error {
+	return p.adapter().Set(ctx, cacheKey+traceSuffix, []byte(captureStack(3)), ttl)
+}
+
+// captureStack formats the stack of the calling goroutine, skipping the
+// given number of frames (0 = runtime.Callers itself)
+func captureStack(skip int) string {
+	pcs := make([]uintptr, maxTraceDepth)
+	n := runtime.Callers(skip, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var b strings.Builder
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(&b, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
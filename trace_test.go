@@ -0,0 +1,75 @@
+package gormcache
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTraceQueries(t *testing.T) {
+	db := setupTestDB(t)
+
+	adapter := NewMemoryAdapter()
+	cachePlugin := New(Config{
+		Adapter:      adapter,
+		TTL:          5 * time.Minute,
+		TraceQueries: true,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	user := TestUser{Name: "Traced"}
+	db.Create(&user)
+
+	var result TestUser
+	db.First(&result, user.ID)
+
+	var cacheKey string
+	for key := range adapter.store {
+		if !isSidecarKey(key) {
+			cacheKey = key
+		}
+	}
+	if cacheKey == "" {
+		t.Fatal("expected the query to be cached")
+	}
+	if _, ok := adapter.store[cacheKey+traceSuffix]; !ok {
+		t.Fatal("expected the trace key to be populated")
+	}
+
+	trace, err := cachePlugin.GetTrace(context.Background(), cacheKey)
+	if err != nil {
+		t.Fatalf("GetTrace failed: %v", err)
+	}
+	if !strings.Contains(trace, "TestTraceQueries") {
+		t.Errorf("expected the trace to contain the test function, got:\n%s", trace)
+	}
+	if !strings.Contains(trace, "trace_test.go") {
+		t.Errorf("expected the trace to contain the test file, got:\n%s", trace)
+	}
+}
+
+func TestTraceQueriesDisabled(t *testing.T) {
+	db := setupTestDB(t)
+
+	adapter := NewMemoryAdapter()
+	cachePlugin := New(Config{Adapter: adapter, TTL: 5 * time.Minute})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	db.Create(&TestUser{Name: "Untraced"})
+
+	var users []TestUser
+	db.Find(&users)
+
+	for key := range adapter.store {
+		if strings.HasSuffix(key, traceSuffix) {
+			t.Errorf("expected no trace keys, got %q", key)
+		}
+	}
+}
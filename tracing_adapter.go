@@ -0,0 +1,94 @@
+package gormcache
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracingAdapter wraps an adapter and records an OpenTelemetry span for every
+// operation, as a child of the span carried by the operation's context
+// Spans are named "gormcache.<Operation>" and carry the db.cache.key attribute;
+// Get spans also carry db.cache.hit
+type TracingAdapter struct {
+	inner  Adapter
+	tracer trace.Tracer
+}
+
+// NewTracingAdapter creates a new tracing adapter recording spans with tracer
+func NewTracingAdapter(inner Adapter, tracer trace.Tracer) *TracingAdapter {
+	return &TracingAdapter{inner: inner, tracer: tracer}
+}
+
+// start starts the span of operation, attributed with key if not empty
+func (a *TracingAdapter) start(ctx context.Context, operation, key string) (context.Context, trace.Span) {
+	ctx, span := a.tracer.Start(ctx, "gormcache."+operation, trace.WithSpanKind(trace.SpanKindClient))
+	if key != "" {
+		span.SetAttributes(attribute.String("db.cache.key", key))
+	}
+	return ctx, span
+}
+
+// endSpan records err on span and ends it
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Get retrieves a value from the inner adapter
+func (a *TracingAdapter) Get(ctx context.Context, key string) ([]byte, error) {
+	ctx, span := a.start(ctx, "Get", key)
+	value, err := a.inner.Get(ctx, key)
+	span.SetAttributes(attribute.Bool("db.cache.hit", err == nil))
+	// 未命中是正常结果，不标记为错误
+	span.End()
+	return value, err
+}
+
+// Set stores a value in the inner adapter
+func (a *TracingAdapter) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	ctx, span := a.start(ctx, "Set", key)
+	err := a.inner.Set(ctx, key, value, ttl)
+	endSpan(span, err)
+	return err
+}
+
+// Delete removes a value from the inner adapter
+func (a *TracingAdapter) Delete(ctx context.Context, key string) error {
+	ctx, span := a.start(ctx, "Delete", key)
+	err := a.inner.Delete(ctx, key)
+	endSpan(span, err)
+	return err
+}
+
+// DeletePattern removes all keys matching the pattern from the inner adapter
+func (a *TracingAdapter) DeletePattern(ctx context.Context, pattern string) error {
+	ctx, span := a.start(ctx, "DeletePattern", pattern)
+	err := a.inner.DeletePattern(ctx, pattern)
+	endSpan(span, err)
+	return err
+}
+
+// Clear removes all cached data from the inner adapter
+func (a *TracingAdapter) Clear(ctx context.Context) error {
+	ctx, span := a.start(ctx, "Clear", "")
+	err := a.inner.Clear(ctx)
+	endSpan(span, err)
+	return err
+}
+
+// Close closes the inner adapter
+func (a *TracingAdapter) Close() error {
+	return a.inner.Close()
+}
+
+// Unwrap returns the inner adapter
+func (a *TracingAdapter) Unwrap() Adapter {
+	return a.inner
+}
@@ -0,0 +1,105 @@
+package gormcache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracingAdapter(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	tracer := provider.Tracer("gormcache-test")
+
+	adapter := NewTracingAdapter(NewMemoryAdapter(), tracer)
+	defer adapter.Close()
+
+	ctx, parent := tracer.Start(context.Background(), "request")
+	adapter.Get(ctx, "users:1")
+	adapter.Set(ctx, "users:1", []byte("john"), time.Minute)
+	adapter.Get(ctx, "users:1")
+	adapter.DeletePattern(ctx, "users:*")
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 5 {
+		t.Fatalf("expected 5 spans, got %d", len(spans))
+	}
+
+	miss, hit := false, true
+	expected := []struct {
+		name string
+		key  string
+		hit  *bool
+	}{
+		{"gormcache.Get", "users:1", &miss},
+		{"gormcache.Set", "users:1", nil},
+		{"gormcache.Get", "users:1", &hit},
+		{"gormcache.DeletePattern", "users:*", nil},
+	}
+	for i, want := range expected {
+		span := spans[i]
+		if span.Name() != want.name {
+			t.Errorf("span %d: expected name %s, got %s", i, want.name, span.Name())
+		}
+		if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("span %d: expected to be a child of the request span", i)
+		}
+
+		attrs := make(map[attribute.Key]attribute.Value)
+		for _, kv := range span.Attributes() {
+			attrs[kv.Key] = kv.Value
+		}
+		if key := attrs["db.cache.key"].AsString(); key != want.key {
+			t.Errorf("span %d: expected db.cache.key %q, got %q", i, want.key, key)
+		}
+		got, ok := attrs["db.cache.hit"]
+		if want.hit == nil {
+			if ok {
+				t.Errorf("span %d: unexpected db.cache.hit attribute", i)
+			}
+		} else if !ok || got.AsBool() != *want.hit {
+			t.Errorf("span %d: expected db.cache.hit %v, got %v", i, *want.hit, got.AsBool())
+		}
+	}
+}
+
+func TestTracingAdapterWithPlugin(t *testing.T) {
+	db := setupTestDB(t)
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	cachePlugin := New(Config{
+		Adapter: NewTracingAdapter(NewMemoryAdapter(), provider.Tracer("gormcache-test")),
+		TTL:     5 * time.Minute,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	db.Create(&TestUser{Name: "John"})
+	var users []TestUser
+	db.Find(&users)
+	db.Find(&users)
+
+	var names []string
+	for _, span := range recorder.Ended() {
+		names = append(names, span.Name())
+	}
+	want := []string{"gormcache.Get", "gormcache.Set", "gormcache.Get"}
+	if len(names) < len(want) {
+		t.Fatalf("expected spans %v, got %v", want, names)
+	}
+	for i := range want {
+		if names[len(names)-len(want)+i] != want[i] {
+			t.Errorf("expected spans %v, got %v", want, names)
+			break
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package gormcache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWithTTL(t *testing.T) {
+	db := setupTestDB(t)
+
+	cachePlugin := New(Config{
+		Adapter: NewMemoryAdapter(),
+		TTL:     5 * time.Minute,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	short := TestUser{Name: "Cart"}
+	long := TestUser{Name: "Catalog"}
+	db.Create(&short)
+	db.Create(&long)
+
+	queries := countQueries(t, db)
+
+	var first TestUser
+	db.Scopes(WithTTL(50*time.Millisecond)).First(&first, short.ID)
+	var second TestUser
+	db.First(&second, long.ID)
+	if *queries != 2 {
+		t.Fatalf("expected 2 database queries, got %d", *queries)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	// The short-lived entry has expired, the default one has not
+	var third TestUser
+	db.Scopes(WithTTL(50*time.Millisecond)).First(&third, short.ID)
+	if *queries != 3 {
+		t.Errorf("expected the 50ms entry to have expired, got %d database queries", *queries)
+	}
+
+	var fourth TestUser
+	db.First(&fourth, long.ID)
+	if *queries != 3 {
+		t.Errorf("expected the default TTL entry to be cached, got %d database queries", *queries)
+	}
+}
+
+func TestWithTTLChainedWithSkipCache(t *testing.T) {
+	db := setupTestDB(t)
+
+	adapter := NewMemoryAdapter()
+	cachePlugin := New(Config{Adapter: adapter, TTL: 5 * time.Minute})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	db.Create(&TestUser{Name: "Skipped"})
+
+	var users []TestUser
+	db.Scopes(WithTTL(time.Hour), SkipCache()).Find(&users)
+	if len(users) != 1 {
+		t.Fatalf("expected 1 user, got %d", len(users))
+	}
+	if len(adapter.store) != 0 {
+		t.Errorf("expected SkipCache to win over WithTTL, got %d cached entries", len(adapter.store))
+	}
+}
+
+func TestSlidingExpiration(t *testing.T) {
+	db := setupTestDB(t)
+
+	cachePlugin := New(Config{
+		Adapter:           NewMemoryAdapter(),
+		TTL:               200 * time.Millisecond,
+		SlidingExpiration: true,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	db.Create(&TestUser{Name: "Session"})
+
+	queries := countQueries(t, db)
+	find := func() {
+		var users []TestUser
+		db.Find(&users)
+	}
+
+	find()
+	time.Sleep(100 * time.Millisecond)
+	find() // 命中并重置 TTL
+
+	// 150ms after the second access, 250ms after the entry was stored
+	time.Sleep(150 * time.Millisecond)
+	find()
+	if *queries != 1 {
+		t.Fatalf("expected the read to extend the TTL, got %d database queries", *queries)
+	}
+
+	// 250ms after the last access
+	time.Sleep(250 * time.Millisecond)
+	find()
+	if *queries != 2 {
+		t.Errorf("expected the entry to expire once unread for its TTL, got %d database queries", *queries)
+	}
+}
+
+func TestTTLJitter(t *testing.T) {
+	db := setupTestDB(t)
+
+	adapter := NewMemoryAdapter()
+	cachePlugin := New(Config{
+		Adapter:   adapter,
+		TTL:       time.Second,
+		TTLJitter: 500 * time.Millisecond,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	db.Create(&TestUser{Name: "John"})
+
+	// Every query has its own cache key
+	const n = 1000
+	for i := 0; i < n; i++ {
+		var users []TestUser
+		db.Where("id <> ?", -i).Find(&users)
+	}
+
+	ctx := context.Background()
+	keys, _ := adapter.Scan(ctx, "gorm:cache:test_users:*")
+	if len(keys) != n {
+		t.Fatalf("expected %d cached queries, got %d", n, len(keys))
+	}
+
+	// Without jitter, all entries would expire at 1s
+	time.Sleep(1001 * time.Millisecond)
+
+	valid := 0
+	for _, key := range keys {
+		if _, err := adapter.Get(ctx, key); err == nil {
+			valid++
+		}
+	}
+	if valid == 0 || valid == n {
+		t.Errorf("expected some but not all entries to be valid after 1001ms, got %d of %d", valid, n)
+	}
+}
+
+func TestTTLJitterRange(t *testing.T) {
+	db := setupTestDB(t)
+
+	cachePlugin := New(Config{
+		TTL:       time.Second,
+		TTLJitter: 500 * time.Millisecond,
+	})
+	defer cachePlugin.Close()
+
+	for i := 0; i < 1000; i++ {
+		ttl, ok := cachePlugin.cacheTTL(db)
+		if !ok || ttl < time.Second || ttl >= 1500*time.Millisecond {
+			t.Fatalf("expected TTL in [1s, 1.5s), got %v", ttl)
+		}
+	}
+}
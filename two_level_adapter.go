@@ -0,0 +1,83 @@
+package gormcache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// TwoLevelAdapter composes a fast local L1 adapter (e.g. MemoryAdapter) with a
+// shared L2 adapter (e.g. RedisAdapter)
+// Reads check L1 first and promote L2 hits into L1; writes and invalidations go to both
+type TwoLevelAdapter struct {
+	l1    Adapter
+	l2    Adapter
+	l1TTL time.Duration
+}
+
+// NewTwoLevelAdapter creates a new two-level adapter; entries are kept in l1 for
+// at most l1TTL (0 = the TTL they are stored with), so other instances' writes
+// to l2 become visible after l1TTL at the latest
+// L2 hits are only promoted into l1 when l1TTL is set, as their remaining TTL is unknown
+func NewTwoLevelAdapter(l1, l2 Adapter, l1TTL time.Duration) *TwoLevelAdapter {
+	return &TwoLevelAdapter{l1: l1, l2: l2, l1TTL: l1TTL}
+}
+
+// Get retrieves a value from L1, falling back to L2
+func (a *TwoLevelAdapter) Get(ctx context.Context, key string) ([]byte, error) {
+	if value, err := a.l1.Get(ctx, key); err == nil {
+		return value, nil
+	}
+
+	value, err := a.l2.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	// L2 不提供剩余 TTL，提升到 L1 时使用 l1TTL
+	if a.l1TTL > 0 && !isPeek(ctx) {
+		_ = a.l1.Set(ctx, key, value, a.l1TTL)
+	}
+	return value, nil
+}
+
+// Set stores a value in both levels
+func (a *TwoLevelAdapter) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := a.l2.Set(ctx, key, value, ttl); err != nil {
+		return err
+	}
+	return a.l1.Set(ctx, key, value, a.localTTL(ttl))
+}
+
+// Delete removes a value from both levels
+func (a *TwoLevelAdapter) Delete(ctx context.Context, key string) error {
+	return errors.Join(a.l2.Delete(ctx, key), a.l1.Delete(ctx, key))
+}
+
+// DeletePattern removes all keys matching the pattern from both levels
+func (a *TwoLevelAdapter) DeletePattern(ctx context.Context, pattern string) error {
+	return errors.Join(a.l2.DeletePattern(ctx, pattern), a.l1.DeletePattern(ctx, pattern))
+}
+
+// Clear removes all data from both levels
+func (a *TwoLevelAdapter) Clear(ctx context.Context) error {
+	return errors.Join(a.l2.Clear(ctx), a.l1.Clear(ctx))
+}
+
+// Close closes both levels
+func (a *TwoLevelAdapter) Close() error {
+	return errors.Join(a.l2.Close(), a.l1.Close())
+}
+
+// Unwrap returns the shared L2 adapter, which holds the complete data
+func (a *TwoLevelAdapter) Unwrap() Adapter {
+	return a.l2
+}
+
+// localTTL returns the L1 TTL of an entry stored with ttl
+func (a *TwoLevelAdapter) localTTL(ttl time.Duration) time.Duration {
+	if a.l1TTL > 0 && (ttl <= 0 || a.l1TTL < ttl) {
+		return a.l1TTL
+	}
+	return ttl
+}
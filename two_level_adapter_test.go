@@ -0,0 +1,93 @@
+package gormcache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// countingAdapter counts the Get calls reaching the wrapped adapter
+type countingAdapter struct {
+	Adapter
+	gets int
+}
+
+func (a *countingAdapter) Get(ctx context.Context, key string) ([]byte, error) {
+	a.gets++
+	return a.Adapter.Get(ctx, key)
+}
+
+func TestTwoLevelAdapter(t *testing.T) {
+	ctx := context.Background()
+	l1 := NewMemoryAdapter()
+	l2 := &countingAdapter{Adapter: NewMemoryAdapter()}
+	adapter := NewTwoLevelAdapter(l1, l2, time.Minute)
+
+	if err := adapter.Set(ctx, "key", []byte("value"), 5*time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	// The value is readable from L1 directly
+	if value, err := l1.Get(ctx, "key"); err != nil || string(value) != "value" {
+		t.Fatalf("expected the value in L1, got %q (error: %v)", value, err)
+	}
+
+	for i := 0; i < 2; i++ {
+		value, err := adapter.Get(ctx, "key")
+		if err != nil || string(value) != "value" {
+			t.Fatalf("expected value, got %q (error: %v)", value, err)
+		}
+	}
+	if l2.gets != 0 {
+		t.Errorf("expected L1 hits not to touch L2, got %d L2 gets", l2.gets)
+	}
+
+	// L1 keeps entries for at most l1TTL
+	if item := l1.store["key"]; time.Until(item.expiration) > time.Minute {
+		t.Errorf("expected the L1 entry to expire within l1TTL, expires in %v", time.Until(item.expiration))
+	}
+}
+
+func TestTwoLevelAdapterPromotion(t *testing.T) {
+	ctx := context.Background()
+	l1 := NewMemoryAdapter()
+	l2 := &countingAdapter{Adapter: NewMemoryAdapter()}
+	adapter := NewTwoLevelAdapter(l1, l2, time.Minute)
+
+	// Written by another instance sharing L2
+	_ = l2.Set(ctx, "key", []byte("shared"), 5*time.Minute)
+
+	if value, err := adapter.Get(ctx, "key"); err != nil || string(value) != "shared" {
+		t.Fatalf("expected the L2 value, got %q (error: %v)", value, err)
+	}
+	if value, err := adapter.Get(ctx, "key"); err != nil || string(value) != "shared" {
+		t.Fatalf("expected the promoted value, got %q (error: %v)", value, err)
+	}
+	if l2.gets != 1 {
+		t.Errorf("expected the second Get to be served by L1, got %d L2 gets", l2.gets)
+	}
+}
+
+func TestTwoLevelAdapterInvalidation(t *testing.T) {
+	ctx := context.Background()
+	l1 := NewMemoryAdapter()
+	l2 := NewMemoryAdapter()
+	adapter := NewTwoLevelAdapter(l1, l2, 0)
+
+	_ = adapter.Set(ctx, "gorm:cache:users:1", []byte("1"), time.Minute)
+	_ = adapter.Set(ctx, "gorm:cache:users:2", []byte("2"), time.Minute)
+	_ = adapter.Set(ctx, "gorm:cache:orders:1", []byte("3"), time.Minute)
+
+	if err := adapter.Delete(ctx, "gorm:cache:orders:1"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := adapter.DeletePattern(ctx, "gorm:cache:users:*"); err != nil {
+		t.Fatalf("DeletePattern failed: %v", err)
+	}
+
+	for _, level := range []*MemoryAdapter{l1, l2} {
+		if len(level.store) != 0 {
+			t.Errorf("expected both levels to be invalidated, got %d keys", len(level.store))
+		}
+	}
+}
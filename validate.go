@@ -0,0 +1,92 @@
+package gormcache
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// NewChecked creates a new cache plugin like New, but returns an error if the
+// resulting configuration is invalid, see CachePlugin.ValidateConfig
+func NewChecked(config Config) (*CachePlugin, error) {
+	p := New(config)
+	if err := p.ValidateConfig(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// MustNew creates a new cache plugin like New, but panics if the resulting
+// configuration is invalid, see CachePlugin.ValidateConfig
+func MustNew(config Config) *CachePlugin {
+	p, err := NewChecked(config)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// ValidateConfig checks the plugin configuration for common mistakes, so they
+// can be caught before db.Use; all problems found are joined in the error
+func (p *CachePlugin) ValidateConfig() error {
+	p.adapterMu.RLock()
+	c := p.config
+	p.adapterMu.RUnlock()
+	var errs []error
+
+	if c.Adapter == nil {
+		errs = append(errs, errors.New("gorm:cache: Adapter is nil"))
+	}
+	if c.TTL <= 0 {
+		errs = append(errs, fmt.Errorf("gorm:cache: TTL must be positive, got %v", c.TTL))
+	}
+	if c.KeyHashLength < 0 {
+		errs = append(errs, fmt.Errorf("gorm:cache: KeyHashLength must not be negative, got %d", c.KeyHashLength))
+	}
+	if c.HotKeyThreshold < 0 {
+		errs = append(errs, fmt.Errorf("gorm:cache: HotKeyThreshold must not be negative, got %d", c.HotKeyThreshold))
+	}
+	if c.TTLJitter < 0 {
+		errs = append(errs, fmt.Errorf("gorm:cache: TTLJitter must not be negative, got %v", c.TTLJitter))
+	}
+	if c.MaxCacheableRows < 0 {
+		errs = append(errs, fmt.Errorf("gorm:cache: MaxCacheableRows must not be negative, got %d", c.MaxCacheableRows))
+	}
+	if c.RetryCount < 0 {
+		errs = append(errs, fmt.Errorf("gorm:cache: RetryCount must not be negative, got %d", c.RetryCount))
+	}
+	if c.RefreshThreshold < 0 || c.RefreshThreshold >= 1 {
+		errs = append(errs, fmt.Errorf("gorm:cache: RefreshThreshold must be in [0, 1), got %v", c.RefreshThreshold))
+	}
+	if c.MaxQueryCacheAge < 0 {
+		errs = append(errs, fmt.Errorf("gorm:cache: MaxQueryCacheAge must not be negative, got %v", c.MaxQueryCacheAge))
+	}
+	if _, err := parseInvalidationPatterns(c.ModelInvalidationPatterns); err != nil {
+		errs = append(errs, err)
+	}
+	if c.CacheKeyGenerator != nil {
+		if err := checkKeyGenerator(c.CacheKeyGenerator); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// checkKeyGenerator calls generator with a zero-value statement, which must
+// produce a non-empty key
+func checkKeyGenerator(generator func(*gorm.DB) string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("gorm:cache: CacheKeyGenerator panics on an empty statement: %v", r)
+		}
+	}()
+
+	db := &gorm.DB{Config: &gorm.Config{}}
+	db.Statement = &gorm.Statement{DB: db}
+	if generator(db) == "" {
+		return errors.New("gorm:cache: CacheKeyGenerator returns an empty key")
+	}
+	return nil
+}
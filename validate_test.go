@@ -0,0 +1,104 @@
+package gormcache
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr string
+	}{
+		{
+			name:   "valid",
+			config: Config{Adapter: NewMemoryAdapter(), TTL: time.Minute},
+		},
+		{
+			name:    "negative TTL",
+			config:  Config{TTL: -time.Second},
+			wantErr: "TTL must be positive",
+		},
+		{
+			name:    "negative KeyHashLength",
+			config:  Config{KeyHashLength: -1},
+			wantErr: "KeyHashLength must not be negative",
+		},
+		{
+			name:    "negative HotKeyThreshold",
+			config:  Config{HotKeyThreshold: -5},
+			wantErr: "HotKeyThreshold must not be negative",
+		},
+		{
+			name:    "negative MaxQueryCacheAge",
+			config:  Config{MaxQueryCacheAge: -time.Second},
+			wantErr: "MaxQueryCacheAge must not be negative",
+		},
+		{
+			name:    "RefreshThreshold of 1",
+			config:  Config{RefreshThreshold: 1},
+			wantErr: "RefreshThreshold must be in [0, 1)",
+		},
+		{
+			name: "empty generated key",
+			config: Config{CacheKeyGenerator: func(db *gorm.DB) string {
+				return db.Statement.Table
+			}},
+			wantErr: "CacheKeyGenerator returns an empty key",
+		},
+		{
+			name: "panicking key generator",
+			config: Config{CacheKeyGenerator: func(db *gorm.DB) string {
+				return db.Statement.Schema.Table
+			}},
+			wantErr: "CacheKeyGenerator panics",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := New(tt.config).ValidateConfig()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateConfigNilAdapter(t *testing.T) {
+	// New always sets an adapter, so only a zero-value plugin lacks one
+	err := (&CachePlugin{config: Config{TTL: time.Minute}}).ValidateConfig()
+	if err == nil || !strings.Contains(err.Error(), "Adapter is nil") {
+		t.Errorf("expected a nil adapter error, got %v", err)
+	}
+}
+
+func TestNewChecked(t *testing.T) {
+	if _, err := NewChecked(Config{TTL: -time.Second}); err == nil {
+		t.Error("expected NewChecked to return the validation error")
+	}
+
+	p, err := NewChecked(Config{})
+	if err != nil || p == nil {
+		t.Errorf("expected defaults to be valid, got %v", err)
+	}
+}
+
+func TestMustNew(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected MustNew to panic on an invalid config")
+		}
+	}()
+	MustNew(Config{TTL: -time.Second})
+}
@@ -0,0 +1,82 @@
+package gormcache
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWarmFromFile(t *testing.T) {
+	db := setupTestDB(t)
+
+	cachePlugin := New(Config{
+		Adapter: NewMemoryAdapter(),
+		TTL:     5 * time.Minute,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	path := filepath.Join(t.TempDir(), "warm.json")
+	fixture := `[
+		{"query": "SELECT * FROM ` + "`test_users`" + `", "result": [{"ID": 1, "Name": "John"}, {"ID": 2, "Name": "Jane"}]},
+		{"query": "SELECT * FROM ` + "`test_users`" + ` WHERE name = ?", "vars": ["Jane"], "result": [{"ID": 2, "Name": "Jane"}]}
+	]`
+	if err := os.WriteFile(path, []byte(fixture), 0o600); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+
+	db.Create(&TestUser{Name: "Bob"})
+	if err := cachePlugin.WarmFromFile(context.Background(), db, path); err != nil {
+		t.Fatalf("failed to warm from file: %v", err)
+	}
+
+	// 绕过 GORM 删除数据库中的行，结果只能来自预热的缓存
+	sqlDB, _ := db.DB()
+	if _, err := sqlDB.Exec("DELETE FROM test_users"); err != nil {
+		t.Fatalf("failed to delete rows: %v", err)
+	}
+	queries := countQueries(t, db)
+
+	var users []TestUser
+	if err := db.Find(&users).Error; err != nil {
+		t.Fatalf("failed to query: %v", err)
+	}
+	if len(users) != 2 || users[0].Name != "John" || users[1].Name != "Jane" {
+		t.Errorf("expected the pre-warmed users, got %+v", users)
+	}
+
+	var jane []TestUser
+	db.Where("name = ?", "Jane").Find(&jane)
+	if len(jane) != 1 || jane[0].ID != 2 {
+		t.Errorf("expected the pre-warmed user Jane, got %+v", jane)
+	}
+
+	if *queries != 0 {
+		t.Errorf("expected the cache to serve both queries, got %d database queries", *queries)
+	}
+}
+
+func TestWarmFromFileInvalid(t *testing.T) {
+	db := setupTestDB(t)
+
+	cachePlugin := New(Config{Adapter: NewMemoryAdapter(), TTL: 5 * time.Minute})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	dir := t.TempDir()
+	if err := cachePlugin.WarmFromFile(context.Background(), db, filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+
+	path := filepath.Join(dir, "invalid.json")
+	os.WriteFile(path, []byte(`[{"result": []}]`), 0o600)
+	if err := cachePlugin.WarmFromFile(context.Background(), db, path); err == nil {
+		t.Error("expected an error for an entry without query")
+	}
+}
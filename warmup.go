@@ -0,0 +1,85 @@
+package gormcache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// WarmQuery is a query executed to populate the cache ahead of traffic
+type WarmQuery struct {
+	// Name identifies the query in returned errors
+	Name string
+
+	// Fn runs the query on a fresh session, e.g.
+	// func(tx *gorm.DB) error { return tx.Find(&[]User{}).Error }
+	Fn func(*gorm.DB) error
+
+	// TTL overrides the TTL of the results cached by Fn, like WithTTL
+	// If 0, the results use the TTL of their statement
+	TTL time.Duration
+}
+
+// WarmUp runs queries concurrently, with at most Config.MaxConcurrentWarmups
+// queries in flight, so their results are cached
+// If queries is empty, Config.WarmupQueries are run
+// When Config.WarmupStaggerInterval is set, queries are started one interval apart
+// Failed queries are reported in the returned error by name
+func (p *CachePlugin) WarmUp(ctx context.Context, db *gorm.DB, queries []WarmQuery) error {
+	if len(queries) == 0 {
+		queries = p.config.WarmupQueries
+	}
+	if len(queries) == 0 {
+		return nil
+	}
+
+	sem := make(chan struct{}, p.config.MaxConcurrentWarmups)
+	errs := make([]error, len(queries))
+
+	var wg sync.WaitGroup
+	var stagger <-chan time.Time
+	if p.config.WarmupStaggerInterval > 0 {
+		ticker := time.NewTicker(p.config.WarmupStaggerInterval)
+		defer ticker.Stop()
+		stagger = ticker.C
+	}
+
+	var canceled error
+	for i, query := range queries {
+		if stagger != nil && i > 0 {
+			select {
+			case <-stagger:
+			case <-ctx.Done():
+				canceled = ctx.Err()
+			}
+			if canceled != nil {
+				break
+			}
+		}
+
+		wg.Add(1)
+		go func(i int, query WarmQuery) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			tx := db.Session(&gorm.Session{NewDB: true, Context: ctx})
+			if query.TTL > 0 {
+				// Session 复制语句设置，使 Fn 的每个查询都带有 TTL
+				tx = tx.Set("gorm:cache:ttl", query.TTL).Session(&gorm.Session{})
+			}
+
+			if err := query.Fn(tx); err != nil {
+				errs[i] = fmt.Errorf("gorm:cache: warmup query %q: %w", query.Name, err)
+			}
+		}(i, query)
+	}
+	wg.Wait()
+
+	return errors.Join(append(errs, canceled)...)
+}
@@ -0,0 +1,290 @@
+package gormcache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func TestWarmupMaxConcurrency(t *testing.T) {
+	db := setupTestDB(t)
+
+	// Keep the in-memory database on a single connection
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+
+	var inFlight, peak int64
+	queries := make([]WarmQuery, 100)
+	for i := range queries {
+		queries[i] = WarmQuery{
+			Name: fmt.Sprintf("users-%d", i),
+			Fn: func(tx *gorm.DB) error {
+				n := atomic.AddInt64(&inFlight, 1)
+				defer atomic.AddInt64(&inFlight, -1)
+
+				for {
+					p := atomic.LoadInt64(&peak)
+					if n <= p || atomic.CompareAndSwapInt64(&peak, p, n) {
+						break
+					}
+				}
+
+				// Simulate a slow database
+				time.Sleep(2 * time.Millisecond)
+				var users []TestUser
+				return tx.Find(&users).Error
+			},
+		}
+	}
+
+	cachePlugin := New(Config{
+		Adapter:              NewMemoryAdapter(),
+		TTL:                  5 * time.Minute,
+		WarmupQueries:        queries,
+		MaxConcurrentWarmups: 3,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	if err := cachePlugin.WarmUp(context.Background(), db, nil); err != nil {
+		t.Fatalf("warmup failed: %v", err)
+	}
+
+	if got := atomic.LoadInt64(&peak); got > 3 {
+		t.Errorf("expected at most 3 warmup queries in flight, got %d", got)
+	}
+	if got := atomic.LoadInt64(&peak); got < 2 {
+		t.Errorf("expected warmup queries to run concurrently, peak was %d", got)
+	}
+}
+
+func TestWarmupCachesResults(t *testing.T) {
+	db := setupTestDB(t)
+
+	cachePlugin := New(Config{
+		Adapter: NewMemoryAdapter(),
+		TTL:     5 * time.Minute,
+		WarmupQueries: []WarmQuery{
+			{Name: "all-users", Fn: func(tx *gorm.DB) error { return tx.Find(&[]TestUser{}).Error }},
+			{Name: "broken", Fn: func(tx *gorm.DB) error { return tx.Table("missing").Find(&[]TestUser{}).Error }},
+		},
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	db.Create(&TestUser{Name: "Test User"})
+
+	err := cachePlugin.WarmUp(context.Background(), db, nil)
+	if err == nil || !strings.Contains(err.Error(), `"broken"`) {
+		t.Errorf("expected error naming the broken query, got %v", err)
+	}
+
+	queries := countQueries(t, db)
+
+	var users []TestUser
+	db.Find(&users)
+	if len(users) != 1 {
+		t.Errorf("expected 1 user, got %d", len(users))
+	}
+	if got := atomic.LoadInt64(queries); got != 0 {
+		t.Errorf("expected warmed query to be served from cache, got %d database queries", got)
+	}
+}
+
+func TestWarmupStaggerInterval(t *testing.T) {
+	db := setupTestDB(t)
+
+	// Keep the in-memory database on a single connection
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+
+	for i := 0; i < 10; i++ {
+		db.Create(&TestUser{Name: fmt.Sprintf("User %d", i)})
+	}
+
+	var mu sync.Mutex
+	var starts []time.Time
+	queries := make([]WarmQuery, 10)
+	for i := range queries {
+		id := i + 1
+		queries[i] = WarmQuery{
+			Name: fmt.Sprintf("user-%d", id),
+			Fn: func(tx *gorm.DB) error {
+				mu.Lock()
+				starts = append(starts, time.Now())
+				mu.Unlock()
+
+				var user TestUser
+				return tx.First(&user, id).Error
+			},
+		}
+	}
+
+	adapter := NewMemoryAdapter()
+	cachePlugin := New(Config{
+		Adapter:               adapter,
+		TTL:                   5 * time.Minute,
+		WarmupQueries:         queries,
+		MaxConcurrentWarmups:  10,
+		WarmupStaggerInterval: 10 * time.Millisecond,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	if err := cachePlugin.WarmUp(context.Background(), db, nil); err != nil {
+		t.Fatalf("warmup failed: %v", err)
+	}
+
+	first, last := starts[0], starts[0]
+	for _, start := range starts {
+		if start.Before(first) {
+			first = start
+		}
+		if start.After(last) {
+			last = start
+		}
+	}
+	if spread := last.Sub(first); spread < 80*time.Millisecond {
+		t.Errorf("expected warmup queries spread over ~90ms, got %v", spread)
+	}
+
+	if len(adapter.store) != 10 {
+		t.Errorf("expected 10 cached entries, got %d", len(adapter.store))
+	}
+}
+
+func TestWarmupStaggerCanceled(t *testing.T) {
+	db := setupTestDB(t)
+
+	var ran int64
+	queries := make([]WarmQuery, 10)
+	for i := range queries {
+		queries[i] = WarmQuery{
+			Name: fmt.Sprintf("users-%d", i),
+			Fn: func(tx *gorm.DB) error {
+				atomic.AddInt64(&ran, 1)
+				return nil
+			},
+		}
+	}
+
+	cachePlugin := New(Config{
+		Adapter:               NewMemoryAdapter(),
+		TTL:                   5 * time.Minute,
+		WarmupQueries:         queries,
+		WarmupStaggerInterval: time.Hour,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := cachePlugin.WarmUp(ctx, db, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+	if got := atomic.LoadInt64(&ran); got != 1 {
+		t.Errorf("expected only the first warmup query to run, got %d", got)
+	}
+}
+
+func TestWarmUpQueries(t *testing.T) {
+	db := setupTestDB(t)
+
+	adapter := NewMemoryAdapter()
+	cachePlugin := New(Config{
+		Adapter: adapter,
+		TTL:     5 * time.Minute,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	db.Create(&TestUser{Name: "John"})
+	db.Create(&TestUser{Name: "Jane"})
+
+	err := cachePlugin.WarmUp(context.Background(), db, []WarmQuery{
+		{
+			Name: "all-users",
+			Fn:   func(tx *gorm.DB) error { return tx.Find(&[]TestUser{}).Error },
+			TTL:  time.Hour,
+		},
+		{
+			Name: "john",
+			Fn:   func(tx *gorm.DB) error { return tx.Where("name = ?", "John").First(&TestUser{}).Error },
+		},
+	})
+	if err != nil {
+		t.Fatalf("warm up failed: %v", err)
+	}
+
+	// The table is emptied behind the cache's back
+	db.Exec("DELETE FROM test_users")
+
+	queries := countQueries(t, db)
+
+	var users []TestUser
+	db.Find(&users)
+	if len(users) != 2 {
+		t.Errorf("expected 2 cached users, got %d", len(users))
+	}
+	var john TestUser
+	db.Where("name = ?", "John").First(&john)
+	if john.Name != "John" {
+		t.Errorf("expected cached 'John', got '%s'", john.Name)
+	}
+	if got := atomic.LoadInt64(queries); got != 0 {
+		t.Errorf("expected warmed queries to be served from cache, got %d database queries", got)
+	}
+
+	// Only the query with a TTL override is cached beyond Config.TTL
+	var long, short int
+	adapter.mu.RLock()
+	for _, item := range adapter.store {
+		if time.Until(item.expiration) > 30*time.Minute {
+			long++
+		} else {
+			short++
+		}
+	}
+	adapter.mu.RUnlock()
+	if long != 1 || short != 1 {
+		t.Errorf("expected 1 entry with the warm up TTL and 1 with Config.TTL, got %d and %d", long, short)
+	}
+}
+
+func TestWarmUpQueryError(t *testing.T) {
+	db := setupTestDB(t)
+
+	cachePlugin := New(Config{
+		Adapter: NewMemoryAdapter(),
+		TTL:     5 * time.Minute,
+	})
+	if err := db.Use(cachePlugin); err != nil {
+		t.Fatalf("failed to install plugin: %v", err)
+	}
+	defer cachePlugin.Close()
+
+	err := cachePlugin.WarmUp(context.Background(), db, []WarmQuery{
+		{Name: "broken", Fn: func(tx *gorm.DB) error { return tx.Table("missing").Find(&[]TestUser{}).Error }},
+	})
+	if err == nil || !strings.Contains(err.Error(), `"broken"`) {
+		t.Errorf("expected error naming the broken query, got %v", err)
+	}
+}